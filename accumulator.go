@@ -0,0 +1,293 @@
+package llmprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrIncompleteStream indicates a stream ended without its final metadata event.
+var ErrIncompleteStream = errors.New("llmprovider: stream ended before final metadata")
+
+// Accumulator rebuilds a response from StreamEvents.
+//
+// It applies the BlockDelta bookkeeping (BlockIndex tracking, concatenating text,
+// signature and JSON deltas, parsing tool input) so consumers don't have to:
+//
+//	acc := llmprovider.NewAccumulator()
+//	for event := range events {
+//	    if err := acc.Add(event); err != nil {
+//	        return nil, err
+//	    }
+//	    render(acc.Blocks()) // In-progress blocks
+//	}
+//	resp, err := acc.Response() // Same shape as GenerateResponse
+//
+// Complete Block events replace the block built from deltas at the same index.
+// Accumulator is safe for concurrent use.
+type Accumulator struct {
+	mu       sync.Mutex
+	partial  map[int]*partialBlock // BlockIndex -> block being built from deltas
+	complete map[int]*Block        // Sequence -> complete block from a Block event
+	usage    usageTotals
+	metadata *StreamMetadata
+	err      error
+}
+
+// partialBlock accumulates the deltas of a single block.
+type partialBlock struct {
+	blockType    string
+	text         strings.Builder
+	signature    strings.Builder
+	json         PartialJSON
+	toolCallID   string
+	toolCallName string
+}
+
+// usageTotals holds the latest usage delta values.
+type usageTotals struct {
+	inputTokens    *int
+	outputTokens   *int
+	thinkingTokens *int
+}
+
+// NewAccumulator creates an empty Accumulator.
+func NewAccumulator() *Accumulator {
+	return &Accumulator{
+		partial:  make(map[int]*partialBlock),
+		complete: make(map[int]*Block),
+	}
+}
+
+// Add consumes one event. It returns the event's error, if any.
+func (a *Accumulator) Add(event StreamEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if event.Delta != nil {
+		a.addDelta(event.Delta)
+	}
+	if event.Block != nil {
+		a.complete[event.Block.Sequence] = event.Block
+	}
+	if event.Metadata != nil {
+		a.metadata = event.Metadata
+	}
+	if event.Error != nil {
+		a.err = event.Error
+	}
+	return event.Error
+}
+
+// Blocks returns the blocks received so far, ordered by index. Finished blocks are the
+// provider's complete blocks; unfinished ones are built from deltas, with tool input
+// parsed best-effort from the JSON received so far (see PartialJSON), so arguments can
+// be rendered while the model is still writing them. The returned blocks must not be
+// modified.
+func (a *Accumulator) Blocks() []*Block {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.blocks(true)
+}
+
+// Done reports whether the final metadata event has been received.
+func (a *Accumulator) Done() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.metadata != nil
+}
+
+// Err returns the stream error, if any was received.
+func (a *Accumulator) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// Response returns the accumulated response, shaped like the non-streaming GenerateResponse.
+// It returns the stream error if one was received, or ErrIncompleteStream if the final
+// metadata event hasn't arrived.
+func (a *Accumulator) Response() (*GenerateResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.err != nil {
+		return nil, a.err
+	}
+	if a.metadata == nil {
+		return nil, ErrIncompleteStream
+	}
+
+	resp := &GenerateResponse{
+		Blocks:       a.blocks(false),
+		Model:        a.metadata.Model,
+		InputTokens:  a.metadata.InputTokens,
+		OutputTokens: a.metadata.OutputTokens,
+		StopReason:   a.metadata.StopReason,
+	}
+
+	// Fall back to usage deltas when the final metadata has no usage
+	if resp.InputTokens == 0 && a.usage.inputTokens != nil {
+		resp.InputTokens = *a.usage.inputTokens
+	}
+	if resp.OutputTokens == 0 && a.usage.outputTokens != nil {
+		resp.OutputTokens = *a.usage.outputTokens
+	}
+
+	if len(a.metadata.ResponseMetadata) > 0 || a.usage.thinkingTokens != nil {
+		resp.ResponseMetadata = make(map[string]interface{}, len(a.metadata.ResponseMetadata)+1)
+		for key, value := range a.metadata.ResponseMetadata {
+			resp.ResponseMetadata[key] = value
+		}
+		if _, ok := resp.ResponseMetadata["thinking_tokens"]; !ok && a.usage.thinkingTokens != nil {
+			resp.ResponseMetadata["thinking_tokens"] = *a.usage.thinkingTokens
+		}
+	}
+
+	return resp, nil
+}
+
+// addDelta applies a delta to its partial block.
+func (a *Accumulator) addDelta(delta *BlockDelta) {
+	if delta.IsUsageDelta() {
+		if delta.InputTokens != nil {
+			a.usage.inputTokens = delta.InputTokens
+		}
+		if delta.OutputTokens != nil {
+			a.usage.outputTokens = delta.OutputTokens
+		}
+		if delta.ThinkingTokens != nil {
+			a.usage.thinkingTokens = delta.ThinkingTokens
+		}
+		return
+	}
+
+	block, ok := a.partial[delta.BlockIndex]
+	if !ok {
+		block = &partialBlock{}
+		a.partial[delta.BlockIndex] = block
+	}
+	if delta.BlockType != nil {
+		block.blockType = *delta.BlockType
+	} else if block.blockType == "" {
+		block.blockType = inferBlockType(delta.DeltaType)
+	}
+
+	if delta.TextDelta != nil {
+		block.text.WriteString(*delta.TextDelta)
+	}
+	if delta.SignatureDelta != nil {
+		block.signature.WriteString(*delta.SignatureDelta)
+	} else if delta.ThinkingSignature != nil {
+		block.signature.WriteString(*delta.ThinkingSignature)
+	}
+	if delta.JSONDelta != nil {
+		block.json.Append(*delta.JSONDelta)
+	}
+
+	if id := firstNonNil(delta.ToolCallID, delta.ToolUseID); id != nil {
+		block.toolCallID = *id
+	}
+	if name := firstNonNil(delta.ToolCallName, delta.ToolName); name != nil {
+		block.toolCallName = *name
+	}
+}
+
+// blocks merges complete and partial blocks, ordered by index. If partial is false,
+// tool input and results are omitted until their JSON is complete.
+func (a *Accumulator) blocks(partial bool) []*Block {
+	indexes := make([]int, 0, len(a.partial)+len(a.complete))
+	for index := range a.complete {
+		indexes = append(indexes, index)
+	}
+	for index := range a.partial {
+		if _, ok := a.complete[index]; !ok {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+
+	blocks := make([]*Block, 0, len(indexes))
+	for _, index := range indexes {
+		if block, ok := a.complete[index]; ok {
+			blocks = append(blocks, block)
+			continue
+		}
+		blocks = append(blocks, a.partial[index].build(index, partial))
+	}
+	return blocks
+}
+
+// build converts the accumulated deltas into a Block.
+func (p *partialBlock) build(index int, partial bool) *Block {
+	block := &Block{BlockType: p.blockType, Sequence: index}
+
+	switch p.blockType {
+	case BlockTypeText, BlockTypeThinking:
+		text := p.text.String()
+		block.TextContent = &text
+		if p.signature.Len() > 0 {
+			// Stored where providers keep it, so the block replays like a non-streamed one
+			block.ProviderData, _ = json.Marshal(map[string]string{"signature": p.signature.String()})
+		}
+
+	case BlockTypeToolResult:
+		block.Content = map[string]interface{}{}
+		if p.toolCallID != "" {
+			block.Content["tool_use_id"] = p.toolCallID
+		}
+		if result, ok := p.jsonValue(partial); ok {
+			block.Content["result"] = result
+		}
+
+	default:
+		// tool_use and other tool invocation blocks (e.g. web_search_use)
+		block.Content = map[string]interface{}{
+			"tool_use_id": p.toolCallID,
+			"tool_name":   p.toolCallName,
+		}
+		if p.json.String() == "" {
+			block.Content["input"] = map[string]interface{}{}
+		} else if input, ok := p.jsonValue(partial); ok {
+			block.Content["input"] = input
+		}
+	}
+
+	return block
+}
+
+// inferBlockType guesses a block's type from its delta type, for providers
+// that omit BlockType on the first delta.
+func inferBlockType(deltaType string) string {
+	switch deltaType {
+	case DeltaTypeThinking, DeltaTypeSignature:
+		return BlockTypeThinking
+	case DeltaTypeToolCallStart, DeltaTypeJSON:
+		return BlockTypeToolUse
+	case DeltaTypeToolResult:
+		return BlockTypeToolResult
+	default:
+		return BlockTypeText
+	}
+}
+
+// jsonValue returns the block's JSON value. Unless partial is set, it reports false
+// while the JSON is incomplete.
+func (p *partialBlock) jsonValue(partial bool) (interface{}, bool) {
+	if !partial && !p.json.Complete() {
+		return nil, false
+	}
+	return p.json.Value()
+}
+
+// firstNonNil returns the first non-nil pointer.
+func firstNonNil(values ...*string) *string {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package llmprovider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAccumulator_DeltasToResponse(t *testing.T) {
+	thinking, text, tool := BlockTypeThinking, BlockTypeText, BlockTypeToolUse
+	input := 42
+	output := 17
+
+	acc := NewAccumulator()
+	events := []StreamEvent{
+		{Delta: &BlockDelta{BlockIndex: 0, BlockType: &thinking, DeltaType: DeltaTypeThinking, TextDelta: stringPtr("Let me ")}},
+		{Delta: &BlockDelta{BlockIndex: 0, DeltaType: DeltaTypeThinking, TextDelta: stringPtr("check.")}},
+		{Delta: &BlockDelta{BlockIndex: 0, DeltaType: DeltaTypeSignature, SignatureDelta: stringPtr("sig")}},
+		{Delta: &BlockDelta{BlockIndex: 1, BlockType: &text, DeltaType: DeltaTypeText, TextDelta: stringPtr("Checking the weather.")}},
+		{Delta: &BlockDelta{BlockIndex: 2, BlockType: &tool, DeltaType: DeltaTypeToolCallStart, ToolCallID: stringPtr("call_1"), ToolCallName: stringPtr("get_weather")}},
+		{Delta: &BlockDelta{BlockIndex: 2, DeltaType: DeltaTypeJSON, JSONDelta: stringPtr(`{"city": "Par`)}},
+	}
+	for _, event := range events {
+		if err := acc.Add(event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	// In-progress view: incomplete tool input is parsed best-effort
+	blocks := acc.Blocks()
+	if len(blocks) != 3 || *blocks[0].TextContent != "Let me check." || string(blocks[0].ProviderData) != `{"signature":"sig"}` {
+		t.Fatalf("unexpected in-progress blocks: %+v", blocks)
+	}
+	if name, _ := blocks[2].GetToolName(); name != "get_weather" {
+		t.Errorf("expected tool name, got %v", blocks[2].Content)
+	}
+	if input, _ := blocks[2].GetToolInput(); input["city"] != "Par" {
+		t.Errorf("expected partial input, got %v", blocks[2].Content["input"])
+	}
+	if _, err := acc.Response(); !errors.Is(err, ErrIncompleteStream) || acc.Done() {
+		t.Errorf("expected ErrIncompleteStream before metadata, got %v", err)
+	}
+
+	events = []StreamEvent{
+		{Delta: &BlockDelta{BlockIndex: 2, DeltaType: DeltaTypeJSON, JSONDelta: stringPtr(`is"}`)}},
+		{Delta: &BlockDelta{DeltaType: DeltaTypeUsage, InputTokens: &input, OutputTokens: &output}},
+		{Block: &Block{BlockType: BlockTypeText, Sequence: 1, TextContent: stringPtr("Checking the weather."), Citations: []Citation{{URL: "https://example.com"}}}},
+		{Metadata: &StreamMetadata{Model: "claude-sonnet-4-5", StopReason: "tool_use", ResponseMetadata: map[string]interface{}{"id": "msg_1"}}},
+	}
+	for _, event := range events {
+		if err := acc.Add(event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	resp, err := acc.Response()
+	if err != nil {
+		t.Fatalf("Response() error = %v", err)
+	}
+	if resp.Model != "claude-sonnet-4-5" || resp.StopReason != "tool_use" || resp.InputTokens != 42 || resp.OutputTokens != 17 {
+		t.Errorf("unexpected response fields: %+v", resp)
+	}
+	if resp.ResponseMetadata["id"] != "msg_1" {
+		t.Errorf("expected response metadata, got %v", resp.ResponseMetadata)
+	}
+	if len(resp.Blocks[1].Citations) != 1 {
+		t.Errorf("expected complete block to replace delta-built block")
+	}
+	toolInput, ok := resp.Blocks[2].GetToolInput()
+	if !ok || !reflect.DeepEqual(toolInput, map[string]interface{}{"city": "Paris"}) {
+		t.Errorf("unexpected tool input: %v", resp.Blocks[2].Content)
+	}
+	if id, _ := resp.Blocks[2].GetToolUseID(); id != "call_1" || resp.Blocks[2].Sequence != 2 {
+		t.Errorf("unexpected tool block: %+v", resp.Blocks[2])
+	}
+}
+
+func TestAccumulator_Error(t *testing.T) {
+	acc := NewAccumulator()
+	for _, event := range textStreamEvents("gpt-4.1", "partial")[:2] {
+		_ = acc.Add(event)
+	}
+	if err := acc.Add(StreamEvent{Error: ErrProviderUnavailable}); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("expected Add to return the event error, got %v", err)
+	}
+	if _, err := acc.Response(); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("expected Response to return the stream error, got %v", err)
+	}
+	if blocks := acc.Blocks(); len(blocks) != 1 || *blocks[0].TextContent != "partial" {
+		t.Errorf("expected partial text to remain available, got %+v", blocks)
+	}
+}
@@ -0,0 +1,182 @@
+package llmprovider
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriberBuffer is the buffer size used when SubscriberConfig.Buffer is zero.
+const DefaultSubscriberBuffer = 64
+
+// ErrSubscriberOverflow is delivered to an OverflowError subscriber whose buffer filled up.
+var ErrSubscriberOverflow = errors.New("llmprovider: subscriber buffer overflow")
+
+// OverflowPolicy decides what Broadcast does when a subscriber's buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock waits for the subscriber, slowing every other subscriber down with it.
+	OverflowBlock OverflowPolicy = "block"
+
+	// OverflowDropOldest discards the subscriber's oldest buffered event to make room.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+
+	// OverflowError ends the subscription: buffered events are delivered, followed by
+	// an ErrSubscriberOverflow event, and the channel is closed.
+	OverflowError OverflowPolicy = "error"
+)
+
+// SubscriberConfig configures one consumer of Broadcast.
+type SubscriberConfig struct {
+	Buffer int                    // Channel buffer size (DefaultSubscriberBuffer if zero)
+	Policy OverflowPolicy         // What to do when the buffer is full (OverflowBlock if empty)
+	Filter func(StreamEvent) bool // Only events it returns true for are delivered (all if nil)
+}
+
+// Subscription is one consumer's view of a broadcast stream.
+type Subscription struct {
+	config  SubscriberConfig
+	events  chan StreamEvent
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+// Events returns the subscriber's channel. It is closed when the source stream ends,
+// after Close, or after an overflow error.
+func (s *Subscription) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Close unsubscribes; the channel is closed shortly after. Consumers that stop reading
+// early must call Close, or an OverflowBlock subscription stalls the broadcast.
+func (s *Subscription) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Dropped returns the number of events discarded under OverflowDropOldest.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Broadcast fans events out to one Subscription per config, in order. Each subscriber
+// has its own buffer and overflow policy, so a slow consumer only affects the others
+// if it uses OverflowBlock:
+//
+//	subs := llmprovider.Broadcast(events,
+//	    llmprovider.SubscriberConfig{Policy: llmprovider.OverflowDropOldest}, // UI deltas
+//	    llmprovider.SubscriberConfig{Buffer: 1024, Policy: llmprovider.OverflowError, // Persister
+//	        Filter: func(e llmprovider.StreamEvent) bool { return e.Delta == nil }},
+//	)
+//
+// When every subscription has been closed, the rest of events is drained; cancel the
+// stream's context to stop the provider as well.
+func Broadcast(events <-chan StreamEvent, configs ...SubscriberConfig) []*Subscription {
+	subs := make([]*Subscription, len(configs))
+	for i, config := range configs {
+		if config.Buffer <= 0 {
+			config.Buffer = DefaultSubscriberBuffer
+		}
+		if config.Policy == "" {
+			config.Policy = OverflowBlock
+		}
+		subs[i] = &Subscription{
+			config: config,
+			events: make(chan StreamEvent, config.Buffer),
+			done:   make(chan struct{}),
+		}
+	}
+
+	go func() {
+		active := append([]*Subscription(nil), subs...)
+		for event := range events {
+			remaining := active[:0]
+			for _, sub := range active {
+				if sub.deliver(event) {
+					remaining = append(remaining, sub)
+				}
+			}
+			active = remaining
+
+			if len(active) == 0 {
+				drainStream(events)
+				return
+			}
+		}
+
+		for _, sub := range active {
+			close(sub.events)
+		}
+	}()
+
+	return subs
+}
+
+// Tee fans events out to n consumers that each receive every event. All consumers
+// must read to the end (or the stream must be cancelled), since a stalled consumer
+// stalls the others once its buffer fills.
+func Tee(events <-chan StreamEvent, n int) []<-chan StreamEvent {
+	configs := make([]SubscriberConfig, n)
+	channels := make([]<-chan StreamEvent, n)
+	for i, sub := range Broadcast(events, configs...) {
+		channels[i] = sub.Events()
+	}
+	return channels
+}
+
+// deliver passes event to the subscriber according to its policy. It returns false
+// once the subscription has ended (and its channel was closed or handed off).
+func (s *Subscription) deliver(event StreamEvent) bool {
+	select {
+	case <-s.done:
+		close(s.events)
+		return false
+	default:
+	}
+
+	if s.config.Filter != nil && !s.config.Filter(event) {
+		return true
+	}
+
+	switch s.config.Policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.events <- event:
+				return true
+			default:
+			}
+			select {
+			case <-s.events:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+
+	case OverflowError:
+		select {
+		case s.events <- event:
+			return true
+		default:
+		}
+		// Deliver the error after the buffered events without holding up the broadcast
+		go func() {
+			defer close(s.events)
+			select {
+			case s.events <- StreamEvent{Error: ErrSubscriberOverflow}:
+			case <-s.done:
+			}
+		}()
+		return false
+
+	default:
+		select {
+		case s.events <- event:
+			return true
+		case <-s.done:
+			close(s.events)
+			return false
+		}
+	}
+}
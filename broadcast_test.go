@@ -0,0 +1,104 @@
+package llmprovider
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// numberedEvents returns n text deltas whose BlockIndex is their position.
+func numberedEvents(n int) []StreamEvent {
+	events := make([]StreamEvent, n)
+	for i := range events {
+		events[i] = StreamEvent{Delta: &BlockDelta{BlockIndex: i, DeltaType: DeltaTypeText, TextDelta: stringPtr("x")}}
+	}
+	return events
+}
+
+func readAll(events <-chan StreamEvent) []StreamEvent {
+	var got []StreamEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	return got
+}
+
+func TestTee(t *testing.T) {
+	events := textStreamEvents("gpt-4.1", "Hello there")
+	outputs := Tee(eventsChannel(events...), 3)
+
+	var wg sync.WaitGroup
+	results := make([][]StreamEvent, len(outputs))
+	for i, out := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = readAll(out)
+		}()
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if !reflect.DeepEqual(got, events) {
+			t.Errorf("consumer %d got %d events, want %d", i, len(got), len(events))
+		}
+	}
+}
+
+func TestBroadcast_SlowSubscribersDoNotStall(t *testing.T) {
+	events := numberedEvents(100)
+	subs := Broadcast(eventsChannel(events...),
+		SubscriberConfig{},
+		SubscriberConfig{Buffer: 4, Policy: OverflowDropOldest},
+		SubscriberConfig{Buffer: 2, Policy: OverflowError},
+	)
+
+	// The fast subscriber finishes while the others are not reading at all
+	done := make(chan []StreamEvent)
+	go func() { done <- readAll(subs[0].Events()) }()
+	select {
+	case got := <-done:
+		if len(got) != len(events) {
+			t.Errorf("fast subscriber got %d events, want %d", len(got), len(events))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fast subscriber stalled by slow subscribers")
+	}
+
+	dropped := readAll(subs[1].Events())
+	if len(dropped) != 4 || dropped[0].Delta.BlockIndex != 96 || subs[1].Dropped() != 96 {
+		t.Errorf("drop-oldest kept %d events starting at %d, dropped %d", len(dropped), dropped[0].Delta.BlockIndex, subs[1].Dropped())
+	}
+
+	failed := readAll(subs[2].Events())
+	if len(failed) != 3 || failed[1].Delta.BlockIndex != 1 || failed[2].Error != ErrSubscriberOverflow {
+		t.Errorf("expected 2 buffered events then ErrSubscriberOverflow, got %+v", failed)
+	}
+}
+
+func TestBroadcast_FilterAndClose(t *testing.T) {
+	events := textStreamEvents("gpt-4.1", "Hello there")
+	subs := Broadcast(eventsChannel(events...),
+		SubscriberConfig{Filter: func(e StreamEvent) bool { return e.Block != nil || e.Metadata != nil }},
+		SubscriberConfig{Buffer: 1}, // Never read
+	)
+
+	// Closing the blocked subscriber releases the others
+	subs[1].Close()
+	if got := readAll(subs[0].Events()); len(got) != 2 || got[0].Block == nil || got[1].Metadata == nil {
+		t.Errorf("filter delivered unexpected events: %+v", got)
+	}
+
+	// Once every subscriber has closed, the source is drained so the producer can finish
+	source := make(chan StreamEvent)
+	sub := Broadcast(source, SubscriberConfig{Buffer: 1})[0]
+	sub.Close()
+	select {
+	case source <- events[0]:
+		source <- events[1]
+	case <-time.After(2 * time.Second):
+		t.Fatal("source not drained after all subscribers closed")
+	}
+	close(source)
+}
@@ -1,6 +1,7 @@
 package llmprovider
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -162,3 +163,43 @@ func FormatToolResults(blocks []*Block) string {
 
 	return strings.TrimSpace(sb.String())
 }
+
+// ToolResultText extracts the text that should be sent back to the model for a tool_result block.
+// Returns the result text and whether the block represents a failed tool execution.
+//
+// Result content can be in multiple fields (priority order):
+//  1. TextContent field (if set)
+//  2. Content["content"] string (if set)
+//  3. Content["result"] (strings used directly, other values JSON-marshaled)
+//  4. Content["error"] (error message string, only for is_error results)
+func ToolResultText(block *Block) (string, bool) {
+	isError := false
+	if errFlag, ok := block.Content["is_error"].(bool); ok {
+		isError = errFlag
+	}
+
+	if block.TextContent != nil {
+		return *block.TextContent, isError
+	}
+
+	if contentStr, ok := block.Content["content"].(string); ok {
+		return contentStr, isError
+	}
+
+	if isError {
+		if errMsg, ok := block.Content["error"].(string); ok {
+			return errMsg, true
+		}
+	}
+
+	if result, ok := block.Content["result"]; ok && result != nil {
+		if resultStr, ok := result.(string); ok {
+			return resultStr, isError
+		}
+		if resultJSON, err := json.Marshal(result); err == nil {
+			return string(resultJSON), isError
+		}
+	}
+
+	return "", isError
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 // ErrorCode is a machine-readable error identifier
@@ -17,6 +19,7 @@ const (
 	ErrorCodeUnsupportedTool     ErrorCode = "UNSUPPORTED_TOOL"
 	ErrorCodeToolUnavailable     ErrorCode = "TOOL_UNAVAILABLE"
 	ErrorCodeToolExecution       ErrorCode = "TOOL_EXECUTION_FAILED"
+	ErrorCodeInvalidToolInput    ErrorCode = "TOOL_INVALID_INPUT"
 	ErrorCodeInvalidRequest      ErrorCode = "INVALID_REQUEST"
 	ErrorCodeProviderUnavailable ErrorCode = "PROVIDER_UNAVAILABLE"
 	ErrorCodeTimeout             ErrorCode = "TIMEOUT"
@@ -44,6 +47,9 @@ var (
 	// ErrToolUnavailable indicates a tool temporarily unavailable (e.g., search service down).
 	ErrToolUnavailable = errors.New("llmprovider: tool temporarily unavailable")
 
+	// ErrInvalidToolInput indicates a tool_use block's input doesn't match the tool's schema.
+	ErrInvalidToolInput = errors.New("llmprovider: invalid tool input")
+
 	// ErrInvalidRequest indicates the request parameters are invalid.
 	ErrInvalidRequest = errors.New("llmprovider: invalid request")
 
@@ -103,9 +109,19 @@ type ToolError struct {
 	Reason    string    // Human-readable explanation
 	Err       error     // Wrapped sentinel error
 	Retryable bool      // Whether this error can be retried
+
+	// Violations lists the schema violations of invalid tool input (see ValidateToolInput)
+	Violations []SchemaViolation
 }
 
 func (e *ToolError) Error() string {
+	if e.Model == "" {
+		// Errors from executing tools locally (timeouts, invalid input) have no model
+		if e.Provider == "" {
+			return fmt.Sprintf("tool '%s' error: %s", e.Tool, e.Reason)
+		}
+		return fmt.Sprintf("tool '%s' error (%s): %s", e.Tool, e.Provider, e.Reason)
+	}
 	return fmt.Sprintf("tool '%s' error for model '%s' (%s): %s", e.Tool, e.Model, e.Provider, e.Reason)
 }
 
@@ -115,12 +131,14 @@ func (e *ToolError) Unwrap() error {
 
 // ProviderError represents an error from the underlying provider API.
 type ProviderError struct {
-	Code       ErrorCode // Machine-readable error code
-	Provider   string    // The provider name
-	StatusCode int       // HTTP status code (if applicable)
-	Message    string    // Error message from provider
-	Retryable  bool      // Whether this error is potentially retryable
-	Err        error     // Wrapped sentinel error (ErrRateLimited, ErrProviderUnavailable, etc.)
+	Code       ErrorCode     // Machine-readable error code
+	Provider   string        // The provider name
+	StatusCode int           // HTTP status code (if applicable)
+	Message    string        // Error message from provider
+	Retryable  bool          // Whether this error is potentially retryable
+	RetryAfter time.Duration // Server-requested wait before retrying (0 if not specified)
+	Headers    http.Header   // Response headers (if applicable), see WithResponseHeaders
+	Err        error         // Wrapped sentinel error (ErrRateLimited, ErrProviderUnavailable, etc.)
 }
 
 func (e *ProviderError) Error() string {
@@ -0,0 +1,126 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrGenerationNotFound indicates the event log has no generation with the given ID.
+	ErrGenerationNotFound = errors.New("llmprovider: generation not found")
+
+	// ErrGenerationExists indicates a generation with the given ID was already started.
+	ErrGenerationExists = errors.New("llmprovider: generation already exists")
+)
+
+// LoggedEvent is a StreamEvent with its position in a generation's event log.
+type LoggedEvent struct {
+	Seq   int64 // 1-based, contiguous within a generation
+	Event StreamEvent
+}
+
+// EventLog stores the events of a generation so they can be replayed. StreamBuffer
+// uses it to serve reconnecting clients; MemoryEventLog is the default. Implementations
+// backed by external storage must be able to serialize StreamEvent errors.
+type EventLog interface {
+	// Create registers a new generation. Returns ErrGenerationExists if it is already known.
+	Create(ctx context.Context, generationID string) error
+
+	// Append stores event and returns its sequence number.
+	Append(ctx context.Context, generationID string, event StreamEvent) (int64, error)
+
+	// Finish marks the generation complete; no more events will be appended.
+	Finish(ctx context.Context, generationID string) error
+
+	// Read returns the events after afterSeq and whether the generation is finished.
+	// Returns ErrGenerationNotFound for unknown generations.
+	Read(ctx context.Context, generationID string, afterSeq int64) ([]LoggedEvent, bool, error)
+
+	// Delete removes the generation and its events.
+	Delete(ctx context.Context, generationID string) error
+}
+
+// MemoryEventLog is an in-process EventLog. It is safe for concurrent use.
+type MemoryEventLog struct {
+	mu          sync.RWMutex
+	generations map[string]*memoryGeneration
+}
+
+type memoryGeneration struct {
+	events   []LoggedEvent
+	finished bool
+}
+
+// NewMemoryEventLog creates an empty in-memory event log.
+func NewMemoryEventLog() *MemoryEventLog {
+	return &MemoryEventLog{generations: make(map[string]*memoryGeneration)}
+}
+
+// Create implements EventLog.
+func (l *MemoryEventLog) Create(ctx context.Context, generationID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.generations[generationID]; ok {
+		return ErrGenerationExists
+	}
+	l.generations[generationID] = &memoryGeneration{}
+	return nil
+}
+
+// Append implements EventLog.
+func (l *MemoryEventLog) Append(ctx context.Context, generationID string, event StreamEvent) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gen, ok := l.generations[generationID]
+	if !ok {
+		return 0, ErrGenerationNotFound
+	}
+	seq := int64(len(gen.events)) + 1
+	gen.events = append(gen.events, LoggedEvent{Seq: seq, Event: event})
+	return seq, nil
+}
+
+// Finish implements EventLog.
+func (l *MemoryEventLog) Finish(ctx context.Context, generationID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gen, ok := l.generations[generationID]
+	if !ok {
+		return ErrGenerationNotFound
+	}
+	gen.finished = true
+	return nil
+}
+
+// Read implements EventLog.
+func (l *MemoryEventLog) Read(ctx context.Context, generationID string, afterSeq int64) ([]LoggedEvent, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	gen, ok := l.generations[generationID]
+	if !ok {
+		return nil, false, ErrGenerationNotFound
+	}
+	if afterSeq < 0 {
+		afterSeq = 0
+	}
+	if afterSeq >= int64(len(gen.events)) {
+		return nil, gen.finished, nil
+	}
+	events := make([]LoggedEvent, int64(len(gen.events))-afterSeq)
+	copy(events, gen.events[afterSeq:])
+	return events, gen.finished, nil
+}
+
+// Delete implements EventLog.
+func (l *MemoryEventLog) Delete(ctx context.Context, generationID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.generations, generationID)
+	return nil
+}
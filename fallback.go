@@ -0,0 +1,218 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Response metadata keys set by FallbackProvider.
+const (
+	MetadataRequestedModel = "requested_model" // The model in the original request
+	MetadataServedModel    = "served_model"    // The model that actually produced the response
+	MetadataFallbackIndex  = "fallback_index"  // 0 = primary, 1 = first fallback, ...
+)
+
+// FallbackProvider honors RequestParams.FallbackModels.
+//
+// When the primary model fails with a retryable error (per IsRetryable, which includes
+// timeouts) or a ModelError, the request is retried on each fallback model in order.
+// The wrapped provider resolves every model, so wrap a Registry to fail over across providers:
+//
+//	provider := llmprovider.NewFallbackProvider(registry)
+//	resp, err := provider.GenerateResponse(ctx, &llmprovider.GenerateRequest{
+//	    Model:  "claude-sonnet-4-5",
+//	    Params: &llmprovider.RequestParams{FallbackModels: []string{"gpt-4.1", "gemini-2.5-pro"}},
+//	})
+//
+// Streams fail over only if they fail before the first event; once content has been
+// delivered, later errors are passed through. FallbackMiddleware adds it to a Chain.
+type FallbackProvider struct {
+	provider Provider
+}
+
+// NewFallbackProvider wraps provider with FallbackModels failover.
+func NewFallbackProvider(provider Provider) *FallbackProvider {
+	return &FallbackProvider{provider: provider}
+}
+
+// GenerateResponse tries the primary model, then each fallback model.
+func (f *FallbackProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	models := fallbackModels(req)
+	if len(models) == 1 {
+		return f.provider.GenerateResponse(ctx, req)
+	}
+
+	var lastErr error
+	for i, model := range models {
+		resp, err := f.provider.GenerateResponse(ctx, withModel(req, model))
+		if err == nil {
+			if resp.Model == "" {
+				resp.Model = model
+			}
+			resp.ResponseMetadata = withFallbackMetadata(resp.ResponseMetadata, req.Model, model, i)
+			return resp, nil
+		}
+
+		lastErr = err
+		if !shouldFallback(ctx, err) {
+			return nil, err
+		}
+	}
+
+	return nil, allModelsFailed(models, lastErr)
+}
+
+// StreamResponse tries the primary model, then each fallback model, until a stream
+// produces its first non-error event.
+func (f *FallbackProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	models := fallbackModels(req)
+	if len(models) == 1 {
+		return f.provider.StreamResponse(ctx, req)
+	}
+
+	var lastErr error
+	for i, model := range models {
+		events, err := f.provider.StreamResponse(ctx, withModel(req, model))
+		if err == nil {
+			var first StreamEvent
+			first, err = firstStreamEvent(ctx, events)
+			if err == nil {
+				return forwardStream(ctx, first, events, func(metadata *StreamMetadata) {
+					if metadata.Model == "" {
+						metadata.Model = model
+					}
+					metadata.ResponseMetadata = withFallbackMetadata(metadata.ResponseMetadata, req.Model, model, i)
+				}), nil
+			}
+		}
+
+		lastErr = err
+		if !shouldFallback(ctx, err) {
+			return nil, err
+		}
+	}
+
+	return nil, allModelsFailed(models, lastErr)
+}
+
+// Name returns the wrapped provider's name.
+func (f *FallbackProvider) Name() ProviderID {
+	return f.provider.Name()
+}
+
+// SupportsModel reports whether the wrapped provider supports the model.
+func (f *FallbackProvider) SupportsModel(model string) bool {
+	return f.provider.SupportsModel(model)
+}
+
+// fallbackModels returns the primary model followed by the fallback models.
+func fallbackModels(req *GenerateRequest) []string {
+	models := []string{req.Model}
+	if req.Params != nil {
+		models = append(models, req.Params.FallbackModels...)
+	}
+	return models
+}
+
+// withModel returns a shallow copy of req for model, with FallbackModels cleared so
+// nested wrappers don't fail over a second time. The caller's request is never modified.
+func withModel(req *GenerateRequest, model string) *GenerateRequest {
+	attempt := *req
+	attempt.Model = model
+	if req.Params != nil {
+		params := *req.Params
+		params.FallbackModels = nil
+		attempt.Params = &params
+	}
+	return &attempt
+}
+
+// shouldFallback reports whether err warrants trying the next model.
+// Cancellation by the caller never does.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var modelErr *ModelError
+	return errors.As(err, &modelErr) || IsRetryable(err)
+}
+
+// allModelsFailed wraps the last error with the list of models tried.
+func allModelsFailed(models []string, lastErr error) error {
+	return fmt.Errorf("all fallback models failed (%s): %w", strings.Join(models, ", "), lastErr)
+}
+
+// withFallbackMetadata returns a copy of metadata with the fallback keys set.
+func withFallbackMetadata(metadata map[string]interface{}, requested, served string, index int) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+3)
+	for key, value := range metadata {
+		result[key] = value
+	}
+	result[MetadataRequestedModel] = requested
+	result[MetadataServedModel] = served
+	result[MetadataFallbackIndex] = index
+	return result
+}
+
+// firstStreamEvent waits for the first event of a stream.
+// An error event (or a stream closed without events) is returned as an error.
+// Unless it returns an event, the rest of the stream is drained so the producer can exit.
+func firstStreamEvent(ctx context.Context, events <-chan StreamEvent) (StreamEvent, error) {
+	select {
+	case <-ctx.Done():
+		go drainStream(events)
+		return StreamEvent{}, ctx.Err()
+	case event, ok := <-events:
+		if !ok {
+			return StreamEvent{}, &ProviderError{
+				Code:      ErrorCodeProviderUnavailable,
+				Message:   "stream closed before the first event",
+				Retryable: true,
+				Err:       ErrProviderUnavailable,
+			}
+		}
+		if event.Error != nil {
+			go drainStream(events)
+			return StreamEvent{}, event.Error
+		}
+		return event, nil
+	}
+}
+
+// forwardStream re-emits first and the rest of events, letting update modify a copy
+// of the final metadata event.
+func forwardStream(ctx context.Context, first StreamEvent, events <-chan StreamEvent, update func(*StreamMetadata)) <-chan StreamEvent {
+	out := make(chan StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(out)
+
+		event, ok := first, true
+		for ok {
+			if event.Metadata != nil {
+				metadata := *event.Metadata
+				update(&metadata)
+				event.Metadata = &metadata
+			}
+
+			select {
+			case <-ctx.Done():
+				go drainStream(events)
+				return
+			case out <- event:
+			}
+
+			event, ok = <-events
+		}
+	}()
+
+	return out
+}
+
+// drainStream discards the remaining events so the producer can finish.
+func drainStream(events <-chan StreamEvent) {
+	for range events {
+	}
+}
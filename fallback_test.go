@@ -0,0 +1,162 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFallbackProvider_GenerateResponse(t *testing.T) {
+	overloaded := &mockProvider{
+		name:   ProviderAnthropic,
+		prefix: "claude-",
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, NewProviderError("anthropic", 503, "overloaded", ErrProviderUnavailable)
+		},
+	}
+	openai := &mockProvider{name: ProviderOpenAI, prefix: "gpt-"}
+	provider := NewFallbackProvider(NewRegistry(overloaded, openai))
+
+	req := &GenerateRequest{
+		Model:  "claude-sonnet-4-5",
+		Params: &RequestParams{FallbackModels: []string{"unknown-model", "gpt-4.1"}},
+	}
+	resp, err := provider.GenerateResponse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+
+	if resp.Model != "gpt-4.1" {
+		t.Errorf("expected served model gpt-4.1, got %s", resp.Model)
+	}
+	if resp.ResponseMetadata[MetadataRequestedModel] != "claude-sonnet-4-5" || resp.ResponseMetadata[MetadataFallbackIndex] != 2 {
+		t.Errorf("unexpected fallback metadata: %v", resp.ResponseMetadata)
+	}
+	if len(req.Params.FallbackModels) != 2 {
+		t.Errorf("caller's params were modified: %v", req.Params.FallbackModels)
+	}
+}
+
+func TestFallbackProvider_NonRetryableStops(t *testing.T) {
+	invalid := &mockProvider{
+		name:   ProviderAnthropic,
+		prefix: "claude-",
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, &ProviderError{Code: ErrorCodeInvalidRequest, Message: "bad request", Err: ErrInvalidRequest}
+		},
+	}
+	openai := &mockProvider{name: ProviderOpenAI, prefix: "gpt-"}
+	provider := NewFallbackProvider(NewRegistry(invalid, openai))
+
+	_, err := provider.GenerateResponse(context.Background(), &GenerateRequest{
+		Model:  "claude-sonnet-4-5",
+		Params: &RequestParams{FallbackModels: []string{"gpt-4.1"}},
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", err)
+	}
+	if openai.callCount() != 0 {
+		t.Errorf("expected no fallback for non-retryable error, got %d calls", openai.callCount())
+	}
+}
+
+func TestFallbackProvider_StreamFailsBeforeFirstEvent(t *testing.T) {
+	failing := &mockProvider{
+		name:   ProviderAnthropic,
+		prefix: "claude-",
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			return eventsChannel(StreamEvent{Error: &ProviderError{Code: ErrorCodeRateLimited, Retryable: true, Err: ErrRateLimited}}), nil
+		},
+	}
+	openai := &mockProvider{name: ProviderOpenAI, prefix: "gpt-"}
+	provider := NewFallbackProvider(NewRegistry(failing, openai))
+
+	events, err := provider.StreamResponse(context.Background(), &GenerateRequest{
+		Model:  "claude-sonnet-4-5",
+		Params: &RequestParams{FallbackModels: []string{"gpt-4.1"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+
+	var metadata *StreamMetadata
+	var count int
+	for event := range events {
+		count++
+		if event.Error != nil {
+			t.Fatalf("unexpected stream error: %v", event.Error)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if count != 4 {
+		t.Errorf("expected all 4 events forwarded, got %d", count)
+	}
+	if metadata == nil || metadata.ResponseMetadata[MetadataServedModel] != "gpt-4.1" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestFallbackProvider_AllFail(t *testing.T) {
+	unavailable := &mockProvider{
+		name:   ProviderAnthropic,
+		prefix: "claude-",
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, NewProviderError("anthropic", 503, "overloaded", ErrProviderUnavailable)
+		},
+	}
+	provider := NewFallbackProvider(unavailable)
+
+	_, err := provider.GenerateResponse(context.Background(), &GenerateRequest{
+		Model:  "claude-sonnet-4-5",
+		Params: &RequestParams{FallbackModels: []string{"claude-haiku-4-5"}},
+	})
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.StatusCode != 503 {
+		t.Errorf("expected wrapped ProviderError, got %v", err)
+	}
+	if unavailable.callCount() != 2 {
+		t.Errorf("expected 2 attempts, got %d", unavailable.callCount())
+	}
+}
+
+func TestFallbackProvider_StreamCancelledDrains(t *testing.T) {
+	done := make(chan struct{})
+	slow := &mockProvider{
+		name:   ProviderAnthropic,
+		prefix: "claude-",
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := make(chan StreamEvent) // Unbuffered, and sends ignore ctx
+			go func() {
+				defer close(done)
+				defer close(events)
+				<-ctx.Done()
+				for _, event := range textStreamEvents(req.Model, "late") {
+					events <- event
+				}
+			}()
+			return events, nil
+		},
+	}
+	provider := NewFallbackProvider(NewRegistry(slow))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	events, err := provider.StreamResponse(ctx, &GenerateRequest{
+		Model:  "claude-sonnet-4-5",
+		Params: &RequestParams{FallbackModels: []string{"claude-haiku-4-5"}},
+	})
+	if err == nil {
+		for range events {
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("producer blocked after cancellation; stream was not drained")
+	}
+}
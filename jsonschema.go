@@ -0,0 +1,228 @@
+package llmprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// SchemaFor returns the JSON Schema of T for FunctionDetails.Parameters. T must be a
+// struct (or pointer to one), mapped the way encoding/json encodes it:
+//
+//	type WeatherInput struct {
+//	    City string `json:"city" jsonschema:"description=City name\\, e.g. Paris"`
+//	    Unit string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+//	    Days int    `json:"days,omitempty" jsonschema:"description=Forecast length,required"`
+//	}
+//
+// Field names come from json tags; fields tagged "-" and unexported fields are skipped,
+// and embedded structs are flattened. Fields are required unless tagged omitempty.
+// The jsonschema tag is a comma-separated list of:
+//   - description=...: the field's description (escape commas as \\, in the tag)
+//   - enum=...: an allowed value, repeated for each value; parsed by the field's type
+//   - required: require an omitempty field
+//
+// Structs are closed (additionalProperties: false), maps become objects with
+// additionalProperties of the value's schema, []byte and time.Time are strings, and
+// interface types accept any value. Recursive types are not supported.
+func SchemaFor[T any]() (map[string]interface{}, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema for %s: tool input must be a struct", t)
+	}
+	return typeSchema(t, make(map[reflect.Type]bool))
+}
+
+// typeSchema returns the schema of t. visiting holds the struct types being expanded,
+// to detect recursion.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("recursive type %s is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := make(map[string]interface{})
+		required := []string{}
+		if err := addFields(t, properties, &required, visiting); err != nil {
+			return nil, err
+		}
+
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// addFields adds the schemas of struct t's fields to properties, flattening embedded structs.
+func addFields(t reflect.Type, properties map[string]interface{}, required *[]string, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addFields(embedded, properties, required, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, err := typeSchema(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		isRequired := !strings.Contains(","+options+",", ",omitempty,")
+		for _, option := range splitSchemaTag(field.Tag.Get("jsonschema")) {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "description":
+				schema["description"] = value
+			case "enum":
+				enumValue, err := parseEnumValue(field.Type, value)
+				if err != nil {
+					return fmt.Errorf("field %s: %w", field.Name, err)
+				}
+				target := schema
+				if items, ok := schema["items"].(map[string]interface{}); ok {
+					target = items // Enums of slices constrain their items
+				}
+				enum, _ := target["enum"].([]interface{})
+				target["enum"] = append(enum, enumValue)
+			case "required":
+				isRequired = true
+			case "":
+			default:
+				return fmt.Errorf("field %s: unknown jsonschema option %q", field.Name, key)
+			}
+		}
+
+		properties[name] = schema
+		if isRequired {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// splitSchemaTag splits a jsonschema tag at commas not escaped as \,.
+func splitSchemaTag(tag string) []string {
+	var options []string
+	var option strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			option.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			options = append(options, option.String())
+			option.Reset()
+		default:
+			option.WriteByte(tag[i])
+		}
+	}
+	if option.Len() > 0 {
+		options = append(options, option.String())
+	}
+	return options
+}
+
+// parseEnumValue converts an enum tag value to the JSON value of type t.
+func parseEnumValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	}
+	return nil, fmt.Errorf("enum is not supported for type %s", t)
+}
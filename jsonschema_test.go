@@ -0,0 +1,99 @@
+package llmprovider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	ID string `json:"id" jsonschema:"description=Record ID"`
+}
+
+type schemaInput struct {
+	schemaBase
+	City     string             `json:"city" jsonschema:"description=City name\\, e.g. Paris"`
+	Unit     string             `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+	Days     int                `json:"days,omitempty" jsonschema:"required,enum=1,enum=7"`
+	Tags     []string           `json:"tags,omitempty" jsonschema:"enum=a,enum=b"`
+	Scores   map[string]float64 `json:"scores,omitempty"`
+	Location *struct {
+		Lat float64 `json:"lat"`
+	} `json:"location,omitempty"`
+	When    time.Time   `json:"when,omitempty"`
+	Extra   interface{} `json:"extra,omitempty"`
+	Skipped string      `json:"-"`
+	hidden  string
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema, err := SchemaFor[*schemaInput]()
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"id", "city", "days"},
+		"properties": map[string]interface{}{
+			"id":   map[string]interface{}{"type": "string", "description": "Record ID"},
+			"city": map[string]interface{}{"type": "string", "description": "City name, e.g. Paris"},
+			"unit": map[string]interface{}{"type": "string", "enum": []interface{}{"celsius", "fahrenheit"}},
+			"days": map[string]interface{}{"type": "integer", "enum": []interface{}{int64(1), int64(7)}},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}},
+			},
+			"scores": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "number"}},
+			"location": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"lat"},
+				"properties":           map[string]interface{}{"lat": map[string]interface{}{"type": "number"}},
+			},
+			"when":  map[string]interface{}{"type": "string", "format": "date-time"},
+			"extra": map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("schema = %#v\nwant %#v", schema, want)
+	}
+
+	tool := Tool{Type: "function", Function: FunctionDetails{Name: "weather", Parameters: schema}}
+	if err := tool.Validate(); err != nil {
+		t.Errorf("schema is not a valid tool schema: %v", err)
+	}
+}
+
+type recursiveInput struct {
+	Children []recursiveInput `json:"children"`
+}
+
+func TestSchemaFor_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  func() (map[string]interface{}, error)
+		wantErr string
+	}{
+		{"not a struct", SchemaFor[string], "must be a struct"},
+		{"recursive", SchemaFor[recursiveInput], "recursive type"},
+		{"bad enum", SchemaFor[struct {
+			N int `json:"n" jsonschema:"enum=many"`
+		}], "field N"},
+		{"unknown option", SchemaFor[struct {
+			N int `json:"n" jsonschema:"minimum=1"`
+		}], "unknown jsonschema option"},
+		{"map key", SchemaFor[struct {
+			M map[int]string `json:"m"`
+		}], "unsupported map key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.schema(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package mcp is a Model Context Protocol client that exposes an MCP server's tools as
+// llmprovider tools.
+//
+// Connect to a server over stdio (a subprocess) or streamable HTTP, then convert its
+// tools for a request and execute the model's tool_use blocks with tools/call:
+//
+//	client, err := mcp.ConnectStdio(ctx, exec.Command("npx", "-y", "@modelcontextprotocol/server-everything"))
+//	if err != nil {
+//	    return err
+//	}
+//	defer client.Close()
+//
+//	tools, err := client.Tools(ctx) // ExecutionSideServer llmprovider.Tools
+//	...
+//	for _, block := range resp.Blocks {
+//	    if block.IsToolUseBlock() {
+//	        result, err := client.Execute(ctx, block) // tool_result block
+//	        ...
+//	    }
+//	}
+//
+// Handlers returns ToolHandlers for llmprovider.Runner, which runs the whole loop.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// ProtocolVersion is the MCP protocol version the client requests.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the protocol versions the client accepts from a server.
+var supportedVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// clientInfo identifies the client to servers.
+var clientInfo = Implementation{Name: "meridian-llm-go", Version: "1.0.0"}
+
+// Implementation names an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Client is a connection to an MCP server. It is safe for concurrent use.
+type Client struct {
+	transport    transport
+	nextID       atomic.Int64
+	serverInfo   Implementation
+	instructions string
+}
+
+// ConnectStdio starts cmd as an MCP server and initializes a session over its stdin and
+// stdout. The server's stderr is cmd.Stderr (discarded if nil). Close stops the server.
+func ConnectStdio(ctx context.Context, cmd *exec.Cmd) (*Client, error) {
+	transport, err := startStdio(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return connect(ctx, transport)
+}
+
+// ConnectHTTP initializes a session with the MCP server at endpoint over the streamable
+// HTTP transport. Options such as llmprovider.WithHeaders (e.g. for an Authorization
+// header) and llmprovider.WithHTTPClient configure the HTTP client; by default there is
+// no client timeout, so bound calls with ctx.
+func ConnectHTTP(ctx context.Context, endpoint string, opts ...llmprovider.Option) (*Client, error) {
+	options := llmprovider.ApplyOptions(opts...)
+	transport := &httpTransport{endpoint: endpoint, client: options.NewHTTPClient(0)}
+	return connect(ctx, transport)
+}
+
+// connect runs the initialization handshake on transport.
+func connect(ctx context.Context, transport transport) (*Client, error) {
+	c := &Client{transport: transport}
+
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions"`
+	}
+	err := c.call(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      clientInfo,
+	}, &result)
+	if err == nil && !slices.Contains(supportedVersions, result.ProtocolVersion) {
+		err = fmt.Errorf("mcp: unsupported protocol version %q", result.ProtocolVersion)
+	}
+	if err == nil {
+		transport.setProtocolVersion(result.ProtocolVersion)
+		err = c.notify(ctx, "notifications/initialized")
+	}
+	if err != nil {
+		_ = transport.close()
+		return nil, err
+	}
+
+	c.serverInfo = result.ServerInfo
+	c.instructions = result.Instructions
+	return c, nil
+}
+
+// ServerInfo returns the server's name and version.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// Instructions returns the server's usage instructions, if any, e.g. for the system prompt.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// ListTools returns the server's tools, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// Tools returns the server's tools as llmprovider tools for RequestParams.Tools.
+func (c *Client) Tools(ctx context.Context) ([]llmprovider.Tool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]llmprovider.Tool, len(tools))
+	for i, tool := range tools {
+		converted[i] = tool.LLMTool()
+	}
+	return converted, nil
+}
+
+// CallTool calls a tool. A tool that fails returns a result with IsError set; an error
+// is returned for protocol failures, such as an unknown tool (an *RPCError).
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", map[string]interface{}{"name": name, "arguments": arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Execute calls the tool of a tool_use block and returns the tool_result block answering it.
+func (c *Client) Execute(ctx context.Context, block *llmprovider.Block) (*llmprovider.Block, error) {
+	name, ok := block.GetToolName()
+	if !ok {
+		return nil, errors.New("mcp: block is not a tool_use block")
+	}
+	input, _ := block.GetToolInput()
+
+	result, err := c.CallTool(ctx, name, input)
+	if err != nil {
+		return nil, err
+	}
+	return result.ToolResultBlock(block), nil
+}
+
+// Handlers returns a ToolHandler per server tool, for llmprovider.Runner or
+// llmprovider.ToolExecutor. A result with IsError set is returned as an error, so the
+// model receives an is_error tool_result.
+func (c *Client) Handlers(ctx context.Context) (map[string]llmprovider.ToolHandler, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make(map[string]llmprovider.ToolHandler, len(tools))
+	for _, tool := range tools {
+		name := tool.Name
+		handlers[name] = func(ctx context.Context, input map[string]interface{}) (string, error) {
+			result, err := c.CallTool(ctx, name, input)
+			if err != nil {
+				return "", err
+			}
+			if result.IsError {
+				return "", errors.New(result.Text())
+			}
+			return result.Text(), nil
+		}
+	}
+	return handlers, nil
+}
+
+// Close ends the session. For stdio servers it closes stdin and waits for the process
+// to exit, killing it if it doesn't exit promptly.
+func (c *Client) Close() error {
+	return c.transport.close()
+}
+
+// call sends a request and decodes its result into result.
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	request, err := newMessage(id, method, params)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.transport.call(ctx, request)
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("mcp: invalid %s result: %w", method, err)
+	}
+	return nil
+}
+
+// notify sends a notification without params.
+func (c *Client) notify(ctx context.Context, method string) error {
+	notification, err := newMessage(nil, method, nil)
+	if err != nil {
+		return err
+	}
+	return c.transport.notify(ctx, notification)
+}
@@ -0,0 +1,360 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// fixtureEnv makes the test binary run as a stdio MCP server (see TestMain).
+const fixtureEnv = "MCP_FIXTURE_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fixtureEnv) == "1" {
+		serveFixture()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fixtureTools are the tools of the fixture server, listed two per page.
+var fixtureTools = []Tool{
+	{
+		Name:        "add",
+		Description: "Add two numbers",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{"type": "number"},
+				"b": map[string]interface{}{"type": "number"},
+			},
+			"required": []interface{}{"a", "b"},
+		},
+	},
+	{Name: "fail", Title: "Always fails"},
+	{Name: "chart", Description: "Draw a chart"},
+}
+
+// serveFixture is a minimal MCP server on stdin/stdout. Before answering a tools/call
+// it pings the client and waits for the reply, as servers may.
+func serveFixture() {
+	reader := bufio.NewReader(os.Stdin)
+	write := func(msg interface{}) {
+		data, _ := json.Marshal(msg)
+		os.Stdout.Write(append(data, '\n'))
+	}
+	respond := func(id json.RawMessage, result interface{}) {
+		write(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result})
+	}
+
+	calls := make(map[string]*message) // Ping id -> tools/call waiting for its reply
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+
+		switch {
+		case msg.isResponse() && calls[string(msg.ID)] != nil:
+			call := calls[string(msg.ID)]
+			delete(calls, string(msg.ID))
+			var params struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			_ = json.Unmarshal(call.Params, &params)
+			switch params.Name {
+			case "add":
+				sum := params.Arguments["a"].(float64) + params.Arguments["b"].(float64)
+				respond(call.ID, map[string]interface{}{
+					"content": []interface{}{map[string]interface{}{"type": "text", "text": fmt.Sprint(sum)}},
+				})
+			case "fail":
+				respond(call.ID, map[string]interface{}{
+					"content": []interface{}{map[string]interface{}{"type": "text", "text": "disk full"}},
+					"isError": true,
+				})
+			case "chart":
+				respond(call.ID, map[string]interface{}{
+					"content": []interface{}{
+						map[string]interface{}{"type": "text", "text": "Here is the chart"},
+						map[string]interface{}{"type": "image", "data": "iVBORw0K", "mimeType": "image/png"},
+					},
+				})
+			default:
+				write(map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "error": map[string]interface{}{
+					"code": CodeInvalidParams, "message": "unknown tool: " + params.Name,
+				}})
+			}
+		case msg.Method == "initialize":
+			respond(msg.ID, map[string]interface{}{
+				"protocolVersion": ProtocolVersion,
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+				"serverInfo":      map[string]interface{}{"name": "fixture", "version": "0.1.0"},
+				"instructions":    "Use add for arithmetic.",
+			})
+		case msg.Method == "tools/list":
+			var params struct {
+				Cursor string `json:"cursor"`
+			}
+			_ = json.Unmarshal(msg.Params, &params)
+			if params.Cursor == "" {
+				respond(msg.ID, map[string]interface{}{"tools": fixtureTools[:2], "nextCursor": "page2"})
+			} else {
+				respond(msg.ID, map[string]interface{}{"tools": fixtureTools[2:]})
+			}
+		case msg.Method == "tools/call":
+			pingID := fmt.Sprintf(`"ping-%s"`, msg.ID)
+			calls[pingID] = &msg
+			write(map[string]interface{}{"jsonrpc": "2.0", "id": json.RawMessage(pingID), "method": "ping"})
+		}
+	}
+}
+
+// connectFixture starts the fixture server and closes it when the test ends.
+func connectFixture(t *testing.T) *Client {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), fixtureEnv+"=1")
+	cmd.Stderr = os.Stderr
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ConnectStdio(ctx, cmd)
+	if err != nil {
+		t.Fatalf("ConnectStdio() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func toolUse(id, name string, input map[string]interface{}) *llmprovider.Block {
+	return &llmprovider.Block{
+		BlockType: llmprovider.BlockTypeToolUse,
+		Content:   map[string]interface{}{"tool_use_id": id, "tool_name": name, "input": input},
+	}
+}
+
+func TestConnectStdio(t *testing.T) {
+	client := connectFixture(t)
+
+	if got := client.ServerInfo(); got.Name != "fixture" || got.Version != "0.1.0" {
+		t.Errorf("ServerInfo() = %+v", got)
+	}
+	if got := client.Instructions(); got != "Use add for arithmetic." {
+		t.Errorf("Instructions() = %q", got)
+	}
+}
+
+func TestClient_Tools(t *testing.T) {
+	client := connectFixture(t)
+	ctx := context.Background()
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 3 || tools[2].Name != "chart" {
+		t.Fatalf("ListTools() = %+v, want all 3 tools across pages", tools)
+	}
+
+	converted, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	add := converted[0]
+	if add.Type != "function" || add.Function.Name != "add" || add.Function.Description != "Add two numbers" {
+		t.Errorf("Tools()[0] = %+v", add)
+	}
+	if add.ExecutionSide != llmprovider.ExecutionSideServer {
+		t.Errorf("ExecutionSide = %q, want server", add.ExecutionSide)
+	}
+	if add.Function.Parameters["required"] == nil {
+		t.Errorf("Parameters = %v, want the server's input schema", add.Function.Parameters)
+	}
+
+	fail := converted[1]
+	if fail.Function.Description != "Always fails" {
+		t.Errorf("Description = %q, want the title as fallback", fail.Function.Description)
+	}
+	if fail.Function.Parameters["type"] != "object" {
+		t.Errorf("Parameters = %v, want an empty object schema", fail.Function.Parameters)
+	}
+}
+
+func TestClient_CallTool(t *testing.T) {
+	client := connectFixture(t)
+	ctx := context.Background()
+
+	result, err := client.CallTool(ctx, "add", map[string]interface{}{"a": 2, "b": 3})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError || result.Text() != "5" {
+		t.Errorf("CallTool() = %+v, want text 5", result)
+	}
+
+	result, err = client.CallTool(ctx, "fail", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || result.Text() != "disk full" {
+		t.Errorf("CallTool() = %+v, want an error result", result)
+	}
+
+	_, err = client.CallTool(ctx, "missing", nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("CallTool() error = %v, want RPCError %d", err, CodeInvalidParams)
+	}
+}
+
+func TestClient_Execute(t *testing.T) {
+	client := connectFixture(t)
+	ctx := context.Background()
+
+	block, err := client.Execute(ctx, toolUse("toolu_1", "add", map[string]interface{}{"a": 1.5, "b": 1}))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if block.BlockType != llmprovider.BlockTypeToolResult {
+		t.Fatalf("BlockType = %q, want tool_result", block.BlockType)
+	}
+	if id, _ := block.GetToolUseID(); id != "toolu_1" {
+		t.Errorf("tool_use_id = %q, want toolu_1", id)
+	}
+	if text, isError := llmprovider.ToolResultText(block); text != "2.5" || isError {
+		t.Errorf("ToolResultText() = %q, %v", text, isError)
+	}
+	if block.ProviderData != nil {
+		t.Errorf("ProviderData = %s, want none for a text result", block.ProviderData)
+	}
+
+	block, err = client.Execute(ctx, toolUse("toolu_2", "fail", nil))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, isError := llmprovider.ToolResultText(block); !isError {
+		t.Error("is_error = false, want true")
+	}
+
+	block, err = client.Execute(ctx, toolUse("toolu_3", "chart", nil))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if text, _ := llmprovider.ToolResultText(block); text != "Here is the chart\n[image (image/png)]" {
+		t.Errorf("ToolResultText() = %q", text)
+	}
+	var raw CallToolResult
+	if err := json.Unmarshal(block.ProviderData, &raw); err != nil || raw.Content[1].Data != "iVBORw0K" {
+		t.Errorf("ProviderData = %s, want the full result", block.ProviderData)
+	}
+
+	if _, err := client.Execute(ctx, &llmprovider.Block{BlockType: llmprovider.BlockTypeText}); err == nil {
+		t.Error("Execute() on a text block: want error")
+	}
+}
+
+func TestClient_Handlers(t *testing.T) {
+	client := connectFixture(t)
+	ctx := context.Background()
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	handlers, err := client.Handlers(ctx)
+	if err != nil {
+		t.Fatalf("Handlers() error = %v", err)
+	}
+
+	provider := &stubProvider{responses: []*llmprovider.GenerateResponse{
+		{Model: "m", Blocks: []*llmprovider.Block{
+			toolUse("toolu_1", "add", map[string]interface{}{"a": 20, "b": 22}),
+			toolUse("toolu_2", "fail", map[string]interface{}{}),
+		}, StopReason: "tool_use"},
+		{Model: "m", Blocks: []*llmprovider.Block{
+			{BlockType: llmprovider.BlockTypeText, TextContent: stringPtr("42")},
+		}, StopReason: "end_turn"},
+	}}
+
+	runner := llmprovider.NewRunner(provider, handlers, llmprovider.RunnerConfig{})
+	result, err := runner.Run(ctx, &llmprovider.GenerateRequest{
+		Model:    "m",
+		Messages: []llmprovider.Message{{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: stringPtr("20+22?")}}}},
+		Params:   &llmprovider.RequestParams{Tools: tools},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Steps != 2 {
+		t.Errorf("Steps = %d, want 2", result.Steps)
+	}
+
+	results := result.Messages[2].Blocks
+	if text, isError := llmprovider.ToolResultText(results[0]); text != "42" || isError {
+		t.Errorf("add result = %q, %v", text, isError)
+	}
+	if text, isError := llmprovider.ToolResultText(results[1]); !strings.Contains(text, "disk full") || !isError {
+		t.Errorf("fail result = %q, %v", text, isError)
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	client := connectFixture(t)
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := client.ListTools(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("ListTools() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestClient_CallCancelled(t *testing.T) {
+	client := connectFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.CallTool(ctx, "add", map[string]interface{}{"a": 1, "b": 2}); !errors.Is(err, context.Canceled) {
+		t.Errorf("CallTool() error = %v, want context.Canceled", err)
+	}
+}
+
+// stubProvider returns canned responses in order.
+type stubProvider struct {
+	responses []*llmprovider.GenerateResponse
+	calls     int
+}
+
+func (p *stubProvider) Name() llmprovider.ProviderID { return "stub" }
+
+func (p *stubProvider) SupportsModel(model string) bool { return true }
+
+func (p *stubProvider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if p.calls >= len(p.responses) {
+		return nil, errors.New("no more responses")
+	}
+	p.calls++
+	return p.responses[p.calls-1], nil
+}
+
+func (p *stubProvider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func stringPtr(s string) *string { return &s }
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/haowjy/meridian-llm-go/sse"
+)
+
+// MCP streamable HTTP headers.
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+)
+
+// httpTransport talks to a server over the streamable HTTP transport: every message is
+// POSTed to the endpoint, and a request's response comes back as JSON or as an SSE
+// stream that may carry server requests and notifications before it.
+type httpTransport struct {
+	endpoint string
+	client   *http.Client
+
+	mu              sync.Mutex
+	sessionID       string // Assigned by the server on initialize
+	protocolVersion string
+}
+
+func (t *httpTransport) call(ctx context.Context, request *message) (*message, error) {
+	resp, err := t.post(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+	if sessionID := resp.Header.Get(headerSessionID); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var msg message
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("mcp: invalid response to %s: %w", request.Method, err)
+		}
+		return &msg, nil
+	}
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: stream ended without a response to %s", ErrClosed, request.Method)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mcp: error reading stream: %w", err)
+		}
+
+		var msg message
+		if err := json.Unmarshal([]byte(event.Data), &msg); err != nil {
+			continue // Not a JSON-RPC message
+		}
+		switch {
+		case msg.isResponse() && bytes.Equal(msg.ID, request.ID):
+			return &msg, nil
+		case msg.isRequest():
+			if err := t.send(ctx, answer(&msg)); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (t *httpTransport) notify(ctx context.Context, notification *message) error {
+	return t.send(ctx, notification)
+}
+
+// send POSTs a notification or a response, which the server acknowledges with 202 Accepted.
+func (t *httpTransport) send(ctx context.Context, msg *message) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return statusError(resp)
+	}
+	return nil
+}
+
+// post sends a message to the endpoint with the session headers.
+func (t *httpTransport) post(ctx context.Context, msg *message) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setSessionHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: request failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) setSessionHeaders(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+}
+
+func (t *httpTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolVersion = version
+}
+
+// close ends the session with a DELETE, as the spec asks. Servers that don't support
+// it answer 405, which is fine.
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("mcp: %w", err)
+	}
+	t.setSessionHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mcp: failed to end session: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// statusError describes an unexpected HTTP response. A 404 for a request with a session
+// means the server has expired the session.
+func statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode == http.StatusNotFound && resp.Request.Header.Get(headerSessionID) != "" {
+		return fmt.Errorf("%w: session expired (HTTP 404)", ErrClosed)
+	}
+	return fmt.Errorf("mcp: unexpected HTTP status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// httpFixture is a streamable HTTP MCP server. It answers initialize and tools/list with
+// JSON and tools/call with an SSE stream that pings the client first.
+type httpFixture struct {
+	pinged chan struct{} // Closed when the client answers the ping
+
+	mu       sync.Mutex
+	deleted  bool // The client ended the session
+	headers  []http.Header
+	messages []string // Methods received, "response" for responses
+}
+
+func (f *httpFixture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		f.mu.Lock()
+		f.deleted = r.Header.Get(headerSessionID) == "session-1"
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var msg message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	if msg.isResponse() {
+		f.messages = append(f.messages, "response")
+	} else {
+		f.messages = append(f.messages, msg.Method)
+	}
+	if msg.Method != "initialize" {
+		f.headers = append(f.headers, r.Header.Clone())
+	}
+	f.mu.Unlock()
+
+	switch {
+	case msg.isResponse():
+		if string(msg.ID) == `"ping-1"` && string(msg.Result) == "{}" {
+			close(f.pinged)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case msg.Method == "initialize":
+		w.Header().Set(headerSessionID, "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-03-26","capabilities":{},"serverInfo":{"name":"http-fixture","version":"1"}}}`, msg.ID)
+	case msg.Method == "tools/list":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"tools":[{"name":"echo","inputSchema":{"type":"object"}}]}}`, msg.ID)
+	case msg.Method == "tools/call":
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{}}\n\n")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"id\":\"ping-1\",\"method\":\"ping\"}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-f.pinged:
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"content\":[{\"type\":\"text\",\"text\":\"hi\"}]}}\n\n", msg.ID)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func TestConnectHTTP(t *testing.T) {
+	fixture := &httpFixture{pinged: make(chan struct{})}
+	server := httptest.NewServer(fixture)
+	defer server.Close()
+	ctx := context.Background()
+
+	client, err := ConnectHTTP(ctx, server.URL, llmprovider.WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	if err != nil {
+		t.Fatalf("ConnectHTTP() error = %v", err)
+	}
+	if got := client.ServerInfo().Name; got != "http-fixture" {
+		t.Errorf("ServerInfo().Name = %q", got)
+	}
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	if len(tools) != 1 || tools[0].Function.Name != "echo" || tools[0].Function.Description != "echo" {
+		t.Errorf("Tools() = %+v", tools)
+	}
+
+	block, err := client.Execute(ctx, toolUse("toolu_1", "echo", map[string]interface{}{"text": "hi"}))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if text, isError := llmprovider.ToolResultText(block); text != "hi" || isError {
+		t.Errorf("ToolResultText() = %q, %v", text, isError)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	fixture.mu.Lock()
+	defer fixture.mu.Unlock()
+	want := []string{"initialize", "notifications/initialized", "tools/list", "tools/call", "response"}
+	if fmt.Sprint(fixture.messages) != fmt.Sprint(want) {
+		t.Errorf("server received %v, want %v", fixture.messages, want)
+	}
+	for _, header := range fixture.headers {
+		if header.Get(headerSessionID) != "session-1" || header.Get(headerProtocolVersion) != "2025-03-26" {
+			t.Errorf("headers = %v, want the session id and negotiated version", header)
+		}
+		if header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q, want the configured header", header.Get("Authorization"))
+		}
+	}
+	if !fixture.deleted {
+		t.Error("Close() did not end the session")
+	}
+}
+
+func TestConnectHTTP_SessionExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		switch {
+		case msg.Method == "initialize":
+			w.Header().Set(headerSessionID, "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"s","version":"1"}}}`, msg.ID)
+		case msg.Method == "tools/list":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	client, err := ConnectHTTP(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("ConnectHTTP() error = %v", err)
+	}
+	if _, err := client.ListTools(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("ListTools() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestConnectHTTP_UnsupportedVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"1999-01-01","capabilities":{},"serverInfo":{"name":"s","version":"1"}}}`, msg.ID)
+	}))
+	defer server.Close()
+
+	if _, err := ConnectHTTP(context.Background(), server.URL); err == nil {
+		t.Error("ConnectHTTP() error = nil, want unsupported protocol version")
+	}
+}
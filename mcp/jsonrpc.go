@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON-RPC error codes used by MCP.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// RPCError is a JSON-RPC error returned by the server, e.g. for an unknown tool.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp: %s (code %d)", e.Message, e.Code)
+}
+
+// message is a JSON-RPC 2.0 request, notification or response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// isResponse reports whether m answers a request.
+func (m *message) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+// isRequest reports whether m is a request from the server, which needs a response.
+func (m *message) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// newMessage creates a request (or, with a nil id, a notification) for method.
+func newMessage(id json.RawMessage, method string, params interface{}) (*message, error) {
+	msg := &message{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp: failed to encode %s params: %w", method, err)
+		}
+		msg.Params = data
+	}
+	return msg, nil
+}
+
+// answer returns the client's response to a request from the server. MCP servers may
+// ping the client; other requests (sampling, elicitation, roots) aren't supported.
+func answer(request *message) *message {
+	if request.Method == "ping" {
+		return &message{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage("{}")}
+	}
+	return &message{JSONRPC: "2.0", ID: request.ID, Error: &RPCError{
+		Code:    CodeMethodNotFound,
+		Message: "method not supported by client: " + request.Method,
+	}}
+}
+
+// transport carries JSON-RPC messages between a Client and an MCP server.
+type transport interface {
+	// call sends a request and returns the matching response.
+	call(ctx context.Context, request *message) (*message, error)
+
+	// notify sends a notification.
+	notify(ctx context.Context, notification *message) error
+
+	// setProtocolVersion records the version negotiated during initialization.
+	setProtocolVersion(version string)
+
+	// close releases the connection.
+	close() error
+}
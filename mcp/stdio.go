@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned for calls on a closed connection or after the server exits.
+var ErrClosed = errors.New("mcp: connection closed")
+
+// stdioShutdownGrace is how long Close waits for a server to exit after closing its stdin.
+const stdioShutdownGrace = 2 * time.Second
+
+// stdioTransport talks to a server subprocess over newline-delimited JSON on its stdin
+// and stdout.
+type stdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	exited chan struct{} // Closed when stdout is closed and the process has exited
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *message // Request id -> response
+	err     error                    // Set when the connection is gone
+	done    chan struct{}            // Closed with err
+}
+
+// startStdio starts cmd and reads its stdout until it exits.
+func startStdio(cmd *exec.Cmd) (*stdioTransport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: failed to start server: %w", err)
+	}
+
+	t := &stdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		exited:  make(chan struct{}),
+		pending: make(map[string]chan *message),
+		done:    make(chan struct{}),
+	}
+	go t.read(stdout)
+	return t, nil
+}
+
+// read dispatches messages from the server until stdout closes, then reaps the process.
+func (t *stdioTransport) read(stdout io.Reader) {
+	defer close(t.exited)
+
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n') // No line length limit
+		if len(line) > 0 {
+			t.dispatch(line)
+		}
+		if err != nil {
+			t.fail(ErrClosed)
+			_ = t.cmd.Wait() // Only after all reads, as Wait closes stdout
+			return
+		}
+	}
+}
+
+// dispatch handles one line from the server. Lines that aren't JSON-RPC are ignored.
+func (t *stdioTransport) dispatch(line []byte) {
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	switch {
+	case msg.isResponse():
+		t.mu.Lock()
+		response, ok := t.pending[string(msg.ID)]
+		delete(t.pending, string(msg.ID))
+		t.mu.Unlock()
+		if ok {
+			response <- &msg
+		}
+	case msg.isRequest():
+		go func() { _ = t.write(answer(&msg)) }()
+	}
+	// Notifications (logging, list_changed, progress) are ignored
+}
+
+// fail ends the connection with err, failing pending calls.
+func (t *stdioTransport) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		t.err = err
+		close(t.done)
+	}
+}
+
+func (t *stdioTransport) write(msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("mcp: %w", err)
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	return nil
+}
+
+func (t *stdioTransport) call(ctx context.Context, request *message) (*message, error) {
+	response := make(chan *message, 1)
+
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+		return nil, t.err
+	}
+	t.pending[string(request.ID)] = response
+	t.mu.Unlock()
+
+	forget := func() {
+		t.mu.Lock()
+		delete(t.pending, string(request.ID))
+		t.mu.Unlock()
+	}
+
+	if err := t.write(request); err != nil {
+		forget()
+		return nil, err
+	}
+
+	select {
+	case msg := <-response:
+		return msg, nil
+	case <-t.done:
+		forget()
+		return nil, t.err
+	case <-ctx.Done():
+		forget()
+		if cancel, err := newMessage(nil, "notifications/cancelled", map[string]interface{}{
+			"requestId": request.ID,
+			"reason":    ctx.Err().Error(),
+		}); err == nil {
+			_ = t.write(cancel)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(ctx context.Context, notification *message) error {
+	return t.write(notification)
+}
+
+func (t *stdioTransport) setProtocolVersion(version string) {}
+
+// close closes the server's stdin and waits for it to exit, killing it if it doesn't
+// within stdioShutdownGrace.
+func (t *stdioTransport) close() error {
+	t.fail(ErrClosed)
+	_ = t.stdin.Close()
+
+	select {
+	case <-t.exited:
+	case <-time.After(stdioShutdownGrace):
+		_ = t.cmd.Process.Kill()
+		<-t.exited
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// Tool is a tool listed by an MCP server.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"` // Hints such as readOnlyHint, destructiveHint
+}
+
+// LLMTool converts the tool to an llmprovider function tool executed by the backend
+// (ExecutionSideServer). The description falls back to the title, then the name, as
+// providers require one.
+func (t Tool) LLMTool() llmprovider.Tool {
+	description := t.Description
+	if description == "" {
+		description = t.Title
+	}
+	if description == "" {
+		description = t.Name
+	}
+
+	parameters := t.InputSchema
+	if parameters == nil {
+		parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	return llmprovider.Tool{
+		Type: "function",
+		Function: llmprovider.FunctionDetails{
+			Name:        t.Name,
+			Description: description,
+			Parameters:  parameters,
+		},
+		ExecutionSide: llmprovider.ExecutionSideServer,
+	}
+}
+
+// Content is an item of a tool result: text, an image or audio clip (base64 Data), an
+// embedded resource, or a link to one.
+type Content struct {
+	Type     string            `json:"type"` // "text", "image", "audio", "resource" or "resource_link"
+	Text     string            `json:"text,omitempty"`
+	Data     string            `json:"data,omitempty"`
+	MimeType string            `json:"mimeType,omitempty"`
+	Resource *ResourceContents `json:"resource,omitempty"` // For "resource"
+	URI      string            `json:"uri,omitempty"`      // For "resource_link"
+	Name     string            `json:"name,omitempty"`     // For "resource_link"
+}
+
+// ResourceContents is the content of an embedded resource: Text, or base64 Blob.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// CallToolResult is the result of tools/call.
+type CallToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// Text returns the result as text for the model: text items and text resources, one
+// per line, with a short placeholder for binary content. A result with only structured
+// content returns it as JSON.
+func (r *CallToolResult) Text() string {
+	parts := make([]string, 0, len(r.Content))
+	for _, content := range r.Content {
+		switch {
+		case content.Type == "text":
+			parts = append(parts, content.Text)
+		case content.Type == "resource" && content.Resource != nil && content.Resource.Blob == "":
+			parts = append(parts, content.Resource.Text)
+		case content.Type == "resource" && content.Resource != nil:
+			parts = append(parts, fmt.Sprintf("[resource %s (%s)]", content.Resource.URI, content.Resource.MimeType))
+		case content.Type == "resource_link":
+			parts = append(parts, fmt.Sprintf("[resource_link %s: %s]", content.Name, content.URI))
+		default:
+			parts = append(parts, fmt.Sprintf("[%s (%s)]", content.Type, content.MimeType))
+		}
+	}
+
+	if len(parts) == 0 && r.StructuredContent != nil {
+		if data, err := json.Marshal(r.StructuredContent); err == nil {
+			return string(data)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ToolResultBlock returns the tool_result block answering the tool_use block call. The
+// text is Text(); if the result has content that text can't carry (images, audio,
+// binary resources or structured content), the full result is kept in ProviderData.
+func (r *CallToolResult) ToolResultBlock(call *llmprovider.Block) *llmprovider.Block {
+	id, _ := call.GetToolUseID()
+	name, _ := call.GetToolName()
+	block := llmprovider.NewToolResultBlock(id, name, r.Text(), r.IsError)
+
+	if r.lossy() {
+		if data, err := json.Marshal(r); err == nil {
+			block.ProviderData = data
+		}
+	}
+	return block
+}
+
+// lossy reports whether Text drops some of the result.
+func (r *CallToolResult) lossy() bool {
+	if r.StructuredContent != nil {
+		return true
+	}
+	for _, content := range r.Content {
+		switch content.Type {
+		case "text", "resource_link":
+		case "resource":
+			if content.Resource == nil || content.Resource.Blob != "" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package llmprovider
+
+import (
+	"context"
+	"time"
+)
+
+// Response metadata keys set by MetricsProvider. Durations are whole milliseconds
+// measured from the GenerateResponse/StreamResponse call.
+const (
+	MetadataTimeToFirstToken = "time_to_first_token_ms"   // First content delta, including thinking
+	MetadataTimeToFirstText  = "time_to_first_text_ms"    // First text delta, i.e. after thinking
+	MetadataDuration         = "duration_ms"              // Until the final metadata event (or response)
+	MetadataBlockDurations   = "block_durations_ms"       // []int64 by block index: first delta until the block completed
+	MetadataTokensPerSecond  = "output_tokens_per_second" // Output tokens over the time after the first token
+)
+
+// MetricsProvider records latency metrics for every call of the wrapped provider and
+// adds them to the response metadata under the Metadata* keys above: on the final
+// StreamMetadata of streams (and so on responses built with Accumulator or
+// GenerateFromStream), and on GenerateResponse (duration and throughput only).
+//
+//	provider := llmprovider.Chain(registry, llmprovider.MetricsMiddleware())
+//	...
+//	ttft := resp.ResponseMetadata[llmprovider.MetadataTimeToFirstToken] // int64 ms
+//
+// Outside a RetryMiddleware the metrics cover all attempts; inside, only the last one.
+type MetricsProvider struct {
+	provider Provider
+	now      func() time.Time
+}
+
+// NewMetricsProvider wraps provider with latency metrics.
+func NewMetricsProvider(provider Provider) *MetricsProvider {
+	return &MetricsProvider{provider: provider, now: time.Now}
+}
+
+// MetricsMiddleware returns a Middleware that wraps providers with NewMetricsProvider.
+func MetricsMiddleware() Middleware {
+	return func(next Provider) Provider {
+		return NewMetricsProvider(next)
+	}
+}
+
+// GenerateResponse calls the wrapped provider and records its duration and throughput.
+func (m *MetricsProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	started := m.now()
+	resp, err := m.provider.GenerateResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := m.now().Sub(started)
+	resp.ResponseMetadata = withMetadataValue(resp.ResponseMetadata, MetadataDuration, duration.Milliseconds())
+	if rate, ok := tokensPerSecond(resp.OutputTokens, duration); ok {
+		resp.ResponseMetadata[MetadataTokensPerSecond] = rate
+	}
+	return resp, nil
+}
+
+// StreamResponse starts a stream and records its timing, adding it to the final metadata.
+func (m *MetricsProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	timer := &streamTimer{started: m.now(), blocks: make(map[int]*blockTiming)}
+
+	events, err := m.provider.StreamResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(out)
+
+		for event := range events {
+			timer.observe(event, m.now())
+			if event.Metadata != nil {
+				metadata := *event.Metadata
+				metadata.ResponseMetadata = timer.metadata(metadata.ResponseMetadata, metadata.OutputTokens)
+				event.Metadata = &metadata
+			}
+
+			select {
+			case <-ctx.Done():
+				go drainStream(events)
+				return
+			case out <- event:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Name returns the wrapped provider's name.
+func (m *MetricsProvider) Name() ProviderID {
+	return m.provider.Name()
+}
+
+// SupportsModel reports whether the wrapped provider supports the model.
+func (m *MetricsProvider) SupportsModel(model string) bool {
+	return m.provider.SupportsModel(model)
+}
+
+// streamTimer records the timing of one stream's events.
+type streamTimer struct {
+	started    time.Time
+	firstToken time.Time
+	firstText  time.Time
+	finished   time.Time
+	blocks     map[int]*blockTiming // Block index -> timing
+}
+
+// blockTiming is the time span of a single block.
+type blockTiming struct {
+	start, end time.Time
+	complete   bool   // end is the complete Block event, not just the latest delta
+	blockType  string // From the block's start delta, if it had one
+}
+
+// isText reports whether delta belongs to a text block, going by the delta type if the
+// block's type is unknown.
+func (b *blockTiming) isText(delta *BlockDelta) bool {
+	if b.blockType != "" {
+		return b.blockType == BlockTypeText
+	}
+	return delta.DeltaType == DeltaTypeText
+}
+
+// observe records an event received at now.
+func (t *streamTimer) observe(event StreamEvent, now time.Time) {
+	if delta := event.Delta; delta != nil && !delta.IsUsageDelta() {
+		block := t.block(delta.BlockIndex, now)
+		if delta.BlockType != nil {
+			block.blockType = *delta.BlockType
+		}
+
+		// Block starts may be empty, and some providers send thinking as text deltas on a
+		// thinking block, so only deltas with content count and the block type decides text
+		hasText := delta.TextDelta != nil && *delta.TextDelta != ""
+		if t.firstToken.IsZero() && (hasText || (delta.JSONDelta != nil && *delta.JSONDelta != "")) {
+			t.firstToken = now
+		}
+		if t.firstText.IsZero() && hasText && block.isText(delta) {
+			t.firstText = now
+		}
+
+		if !block.complete {
+			block.end = now
+		}
+	}
+
+	if event.Block != nil {
+		block := t.block(event.Block.Sequence, now)
+		block.end, block.complete = now, true
+	}
+
+	if event.Metadata != nil {
+		t.finished = now
+	}
+}
+
+// block returns the timing for index, starting it at now if it is new.
+func (t *streamTimer) block(index int, now time.Time) *blockTiming {
+	block, ok := t.blocks[index]
+	if !ok {
+		block = &blockTiming{start: now, end: now}
+		t.blocks[index] = block
+	}
+	return block
+}
+
+// metadata returns a copy of metadata with the recorded metrics added.
+func (t *streamTimer) metadata(metadata map[string]interface{}, outputTokens int) map[string]interface{} {
+	metadata = withMetadataValue(metadata, MetadataDuration, t.finished.Sub(t.started).Milliseconds())
+
+	if !t.firstToken.IsZero() {
+		metadata[MetadataTimeToFirstToken] = t.firstToken.Sub(t.started).Milliseconds()
+		if rate, ok := tokensPerSecond(outputTokens, t.finished.Sub(t.firstToken)); ok {
+			metadata[MetadataTokensPerSecond] = rate
+		}
+	}
+	if !t.firstText.IsZero() {
+		metadata[MetadataTimeToFirstText] = t.firstText.Sub(t.started).Milliseconds()
+	}
+
+	if len(t.blocks) > 0 {
+		last := 0
+		for index := range t.blocks {
+			last = max(last, index)
+		}
+		durations := make([]int64, last+1)
+		for index, block := range t.blocks {
+			if index >= 0 {
+				durations[index] = block.end.Sub(block.start).Milliseconds()
+			}
+		}
+		metadata[MetadataBlockDurations] = durations
+	}
+
+	return metadata
+}
+
+// tokensPerSecond returns the output rate over duration, if both are positive.
+func tokensPerSecond(outputTokens int, duration time.Duration) (float64, bool) {
+	if outputTokens <= 0 || duration <= 0 {
+		return 0, false
+	}
+	return float64(outputTokens) / duration.Seconds(), true
+}
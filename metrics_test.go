@@ -0,0 +1,120 @@
+package llmprovider
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedClock returns the given offsets from a fixed start, one per call.
+func scriptedClock(offsets ...time.Duration) func() time.Time {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		next := offsets[0]
+		if len(offsets) > 1 {
+			offsets = offsets[1:]
+		}
+		return start.Add(next)
+	}
+}
+
+func TestMetricsProvider_Stream(t *testing.T) {
+	thinking, text := BlockTypeThinking, BlockTypeText
+	outputTokens := 50
+	events := []StreamEvent{
+		{Delta: &BlockDelta{BlockIndex: 0, BlockType: &thinking, DeltaType: DeltaTypeThinking, TextDelta: stringPtr("Hmm")}},
+		{Block: &Block{BlockType: BlockTypeThinking, Sequence: 0, TextContent: stringPtr("Hmm")}},
+		{Delta: &BlockDelta{BlockIndex: 1, BlockType: &text, DeltaType: DeltaTypeText, TextDelta: stringPtr("Hi")}},
+		{Delta: &BlockDelta{BlockIndex: 1, DeltaType: DeltaTypeText, TextDelta: stringPtr(" there")}},
+		{Block: &Block{BlockType: BlockTypeText, Sequence: 1, TextContent: stringPtr("Hi there")}},
+		{Delta: &BlockDelta{DeltaType: DeltaTypeUsage, OutputTokens: &outputTokens}},
+		{Metadata: &StreamMetadata{Model: "claude-sonnet-4-5", OutputTokens: 50, StopReason: "end_turn", ResponseMetadata: map[string]interface{}{"id": "msg_1"}}},
+	}
+	mock := &mockProvider{
+		name: ProviderAnthropic,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			return eventsChannel(events...), nil
+		},
+	}
+	provider := NewMetricsProvider(mock)
+	provider.now = scriptedClock(0, 100*time.Millisecond, 200*time.Millisecond, 300*time.Millisecond,
+		500*time.Millisecond, 600*time.Millisecond, 650*time.Millisecond, 1100*time.Millisecond)
+
+	resp, err := GenerateFromStream(context.Background(), provider.StreamResponse, &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("GenerateFromStream() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"id":                     "msg_1",
+		MetadataTimeToFirstToken: int64(100),
+		MetadataTimeToFirstText:  int64(300),
+		MetadataDuration:         int64(1100),
+		MetadataBlockDurations:   []int64{100, 300},
+		MetadataTokensPerSecond:  50.0,
+	}
+	if !reflect.DeepEqual(resp.ResponseMetadata, want) {
+		t.Errorf("metadata = %v, want %v", resp.ResponseMetadata, want)
+	}
+	if _, ok := events[6].Metadata.ResponseMetadata[MetadataDuration]; ok {
+		t.Error("original metadata event was modified")
+	}
+}
+
+func TestMetricsProvider_Stream_EmptyBlockStarts(t *testing.T) {
+	// OpenRouter style: each block starts with an empty delta, and thinking arrives as
+	// text deltas on a thinking block
+	thinking, text := BlockTypeThinking, BlockTypeText
+	events := []StreamEvent{
+		{Delta: &BlockDelta{BlockIndex: 0, BlockType: &thinking, DeltaType: DeltaTypeText}},
+		{Delta: &BlockDelta{BlockIndex: 0, DeltaType: DeltaTypeText, TextDelta: stringPtr("Hmm")}},
+		{Delta: &BlockDelta{BlockIndex: 1, BlockType: &text, DeltaType: DeltaTypeText}},
+		{Delta: &BlockDelta{BlockIndex: 1, DeltaType: DeltaTypeText, TextDelta: stringPtr("Hi")}},
+		{Metadata: &StreamMetadata{Model: "deepseek/deepseek-r1", StopReason: "stop"}},
+	}
+	mock := &mockProvider{
+		name: ProviderOpenRouter,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			return eventsChannel(events...), nil
+		},
+	}
+	provider := NewMetricsProvider(mock)
+	provider.now = scriptedClock(0, 100*time.Millisecond, 200*time.Millisecond, 300*time.Millisecond,
+		400*time.Millisecond, 500*time.Millisecond)
+
+	resp, err := GenerateFromStream(context.Background(), provider.StreamResponse, &GenerateRequest{Model: "deepseek/deepseek-r1"})
+	if err != nil {
+		t.Fatalf("GenerateFromStream() error = %v", err)
+	}
+
+	if got := resp.ResponseMetadata[MetadataTimeToFirstToken]; got != int64(200) {
+		t.Errorf("time to first token = %v, want 200", got)
+	}
+	if got := resp.ResponseMetadata[MetadataTimeToFirstText]; got != int64(400) {
+		t.Errorf("time to first text = %v, want 400", got)
+	}
+}
+
+func TestMetricsProvider_Generate(t *testing.T) {
+	mock := &mockProvider{
+		name: ProviderOpenAI,
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return &GenerateResponse{Model: req.Model, OutputTokens: 100}, nil
+		},
+	}
+	provider := NewMetricsProvider(mock)
+	provider.now = scriptedClock(0, 2*time.Second)
+
+	resp, err := provider.GenerateResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+	if resp.ResponseMetadata[MetadataDuration] != int64(2000) || resp.ResponseMetadata[MetadataTokensPerSecond] != 50.0 {
+		t.Errorf("unexpected metadata: %v", resp.ResponseMetadata)
+	}
+}
@@ -0,0 +1,152 @@
+package llmprovider
+
+import "context"
+
+// Middleware wraps a Provider with cross-cutting behavior (logging, retries, redaction, metrics).
+type Middleware func(Provider) Provider
+
+// Chain wraps provider with middlewares. The first middleware is the outermost, so it
+// sees each request first and each response last:
+//
+//	provider := llmprovider.Chain(registry,
+//	    llmprovider.HooksMiddleware(logging),                          // Sees the final outcome
+//	    llmprovider.FallbackMiddleware(),                              // Fails over across models
+//	    llmprovider.RetryMiddleware(llmprovider.DefaultRetryConfig()), // Retries each model first
+//	)
+func Chain(provider Provider, middlewares ...Middleware) Provider {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		provider = middlewares[i](provider)
+	}
+	return provider
+}
+
+// RetryMiddleware returns a Middleware that wraps providers with NewRetryProvider.
+func RetryMiddleware(config RetryConfig) Middleware {
+	return func(next Provider) Provider {
+		return NewRetryProvider(next, config)
+	}
+}
+
+// FallbackMiddleware returns a Middleware that wraps providers with NewFallbackProvider.
+func FallbackMiddleware() Middleware {
+	return func(next Provider) Provider {
+		return NewFallbackProvider(next)
+	}
+}
+
+// Hooks are callbacks invoked around Provider calls by HooksMiddleware.
+// Nil hooks are skipped.
+type Hooks struct {
+	// OnRequest is called before each GenerateResponse or StreamResponse call and returns
+	// the request to send. Return a modified copy (e.g. for redaction) rather than changing
+	// req in place. A non-nil error aborts the call and is passed to OnError.
+	OnRequest func(ctx context.Context, req *GenerateRequest) (*GenerateRequest, error)
+
+	// OnResponse is called with each successful GenerateResponse.
+	OnResponse func(ctx context.Context, req *GenerateRequest, resp *GenerateResponse)
+
+	// OnStreamEvent is called for each stream event and returns the event to deliver.
+	// Error events have already been passed through OnError.
+	OnStreamEvent func(ctx context.Context, req *GenerateRequest, event StreamEvent) StreamEvent
+
+	// OnError is called with each error (returned by a call or carried by a stream event)
+	// and returns the error to surface in its place.
+	OnError func(ctx context.Context, req *GenerateRequest, err error) error
+}
+
+// HooksMiddleware returns a Middleware that calls hooks around every provider call.
+func HooksMiddleware(hooks Hooks) Middleware {
+	return func(next Provider) Provider {
+		return &hooksProvider{next: next, hooks: hooks}
+	}
+}
+
+// hooksProvider invokes Hooks around the wrapped provider.
+type hooksProvider struct {
+	next  Provider
+	hooks Hooks
+}
+
+func (h *hooksProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	req, err := h.request(ctx, req)
+	if err != nil {
+		return nil, h.error(ctx, req, err)
+	}
+
+	resp, err := h.next.GenerateResponse(ctx, req)
+	if err != nil {
+		return nil, h.error(ctx, req, err)
+	}
+
+	if h.hooks.OnResponse != nil {
+		h.hooks.OnResponse(ctx, req, resp)
+	}
+	return resp, nil
+}
+
+func (h *hooksProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	req, err := h.request(ctx, req)
+	if err != nil {
+		return nil, h.error(ctx, req, err)
+	}
+
+	events, err := h.next.StreamResponse(ctx, req)
+	if err != nil {
+		return nil, h.error(ctx, req, err)
+	}
+	if h.hooks.OnStreamEvent == nil && h.hooks.OnError == nil {
+		return events, nil
+	}
+
+	out := make(chan StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(out)
+
+		for event := range events {
+			if event.Error != nil {
+				event.Error = h.error(ctx, req, event.Error)
+			}
+			if h.hooks.OnStreamEvent != nil {
+				event = h.hooks.OnStreamEvent(ctx, req, event)
+			}
+
+			select {
+			case <-ctx.Done():
+				go drainStream(events)
+				return
+			case out <- event:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (h *hooksProvider) Name() ProviderID {
+	return h.next.Name()
+}
+
+func (h *hooksProvider) SupportsModel(model string) bool {
+	return h.next.SupportsModel(model)
+}
+
+// request applies OnRequest. On error the original request is returned alongside it.
+func (h *hooksProvider) request(ctx context.Context, req *GenerateRequest) (*GenerateRequest, error) {
+	if h.hooks.OnRequest == nil {
+		return req, nil
+	}
+	modified, err := h.hooks.OnRequest(ctx, req)
+	if err != nil || modified == nil {
+		return req, err
+	}
+	return modified, nil
+}
+
+// error applies OnError.
+func (h *hooksProvider) error(ctx context.Context, req *GenerateRequest, err error) error {
+	if h.hooks.OnError == nil {
+		return err
+	}
+	return h.hooks.OnError(ctx, req, err)
+}
@@ -0,0 +1,111 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return HooksMiddleware(Hooks{
+			OnRequest: func(ctx context.Context, req *GenerateRequest) (*GenerateRequest, error) {
+				order = append(order, "request:"+name)
+				return req, nil
+			},
+			OnResponse: func(ctx context.Context, req *GenerateRequest, resp *GenerateResponse) {
+				order = append(order, "response:"+name)
+			},
+		})
+	}
+
+	provider := Chain(&mockProvider{name: ProviderOpenAI, prefix: "gpt-"}, tag("outer"), tag("inner"))
+	if _, err := provider.GenerateResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"}); err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+
+	want := "request:outer,request:inner,response:inner,response:outer"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("hook order = %s, expected %s", got, want)
+	}
+	if provider.Name() != ProviderOpenAI || !provider.SupportsModel("gpt-4o") {
+		t.Errorf("expected Name and SupportsModel to be delegated")
+	}
+}
+
+func TestHooksMiddleware_Stream(t *testing.T) {
+	failing := &mockProvider{
+		name: ProviderAnthropic,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := textStreamEvents(req.Model, "secret")
+			return eventsChannel(append(events[:3:3], StreamEvent{Error: ErrProviderUnavailable})...), nil
+		},
+	}
+
+	var seenModel string
+	redacted := "[redacted]"
+	provider := Chain(failing, HooksMiddleware(Hooks{
+		OnRequest: func(ctx context.Context, req *GenerateRequest) (*GenerateRequest, error) {
+			modified := *req
+			modified.Model = "claude-haiku-4-5"
+			return &modified, nil
+		},
+		OnStreamEvent: func(ctx context.Context, req *GenerateRequest, event StreamEvent) StreamEvent {
+			seenModel = req.Model
+			if event.Delta != nil && event.Delta.TextDelta != nil {
+				delta := *event.Delta
+				delta.TextDelta = &redacted
+				event.Delta = &delta
+			}
+			return event
+		},
+		OnError: func(ctx context.Context, req *GenerateRequest, err error) error {
+			return &ProviderError{Code: ErrorCodeProviderUnavailable, Message: "wrapped", Retryable: true, Err: err}
+		},
+	}))
+
+	events, err := provider.StreamResponse(context.Background(), &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+
+	var text string
+	var streamErr error
+	for event := range events {
+		if event.Delta != nil && event.Delta.TextDelta != nil {
+			text += *event.Delta.TextDelta
+		}
+		if event.Error != nil {
+			streamErr = event.Error
+		}
+	}
+
+	if text != redacted || seenModel != "claude-haiku-4-5" || failing.calls[0] != "claude-haiku-4-5" {
+		t.Errorf("expected modified request and redacted deltas, got text %q, model %q", text, seenModel)
+	}
+	var providerErr *ProviderError
+	if !errors.As(streamErr, &providerErr) || !errors.Is(streamErr, ErrProviderUnavailable) {
+		t.Errorf("expected OnError to wrap stream error, got %v", streamErr)
+	}
+}
+
+func TestHooksMiddleware_OnRequestAborts(t *testing.T) {
+	mock := &mockProvider{name: ProviderOpenAI}
+	var handled error
+	provider := Chain(mock, HooksMiddleware(Hooks{
+		OnRequest: func(ctx context.Context, req *GenerateRequest) (*GenerateRequest, error) {
+			return nil, ErrInvalidRequest
+		},
+		OnError: func(ctx context.Context, req *GenerateRequest, err error) error {
+			handled = err
+			return err
+		},
+	}))
+
+	_, err := provider.GenerateResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if !errors.Is(err, ErrInvalidRequest) || !errors.Is(handled, ErrInvalidRequest) || mock.callCount() != 0 {
+		t.Errorf("expected aborted call reported to OnError, got %v (%d calls)", err, mock.callCount())
+	}
+}
@@ -0,0 +1,81 @@
+package llmprovider
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// mockProvider is a scriptable Provider for root package tests.
+// generate and stream default to echoing the requested model back.
+type mockProvider struct {
+	name     ProviderID
+	prefix   string // SupportsModel matches models with this prefix
+	generate func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error)
+	stream   func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error)
+
+	mu    sync.Mutex
+	calls []string // Models requested, in order
+}
+
+func (m *mockProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	m.record(req.Model)
+	if m.generate != nil {
+		return m.generate(ctx, req)
+	}
+	text := "ok"
+	return &GenerateResponse{
+		Blocks:     []*Block{{BlockType: BlockTypeText, TextContent: &text}},
+		Model:      req.Model,
+		StopReason: "end_turn",
+	}, nil
+}
+
+func (m *mockProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	m.record(req.Model)
+	if m.stream != nil {
+		return m.stream(ctx, req)
+	}
+	return eventsChannel(textStreamEvents(req.Model, "ok")...), nil
+}
+
+func (m *mockProvider) Name() ProviderID {
+	return m.name
+}
+
+func (m *mockProvider) SupportsModel(model string) bool {
+	return strings.HasPrefix(model, m.prefix)
+}
+
+func (m *mockProvider) record(model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, model)
+}
+
+func (m *mockProvider) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// textStreamEvents returns the events of a single-text-block stream.
+func textStreamEvents(model, text string) []StreamEvent {
+	blockType := BlockTypeText
+	return []StreamEvent{
+		{Delta: &BlockDelta{BlockIndex: 0, BlockType: &blockType, DeltaType: DeltaTypeText}},
+		{Delta: &BlockDelta{BlockIndex: 0, DeltaType: DeltaTypeText, TextDelta: &text}},
+		{Block: &Block{BlockType: BlockTypeText, Sequence: 0, TextContent: &text}},
+		{Metadata: &StreamMetadata{Model: model, StopReason: "end_turn"}},
+	}
+}
+
+// eventsChannel returns a closed channel pre-filled with events.
+func eventsChannel(events ...StreamEvent) <-chan StreamEvent {
+	ch := make(chan StreamEvent, len(events))
+	for _, event := range events {
+		ch <- event
+	}
+	close(ch)
+	return ch
+}
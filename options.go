@@ -0,0 +1,160 @@
+package llmprovider
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProviderOptions holds construction-time settings shared by all providers.
+// Providers build it from Option values via ApplyOptions; zero values mean "use the provider default".
+type ProviderOptions struct {
+	// BaseURL overrides the API root (corporate proxies, gateways, local httptest fakes)
+	BaseURL string
+
+	// HTTPClient replaces the provider's default client
+	HTTPClient *http.Client
+
+	// Headers are added to every request, after the provider's own headers (so they can override them)
+	Headers http.Header
+
+	// Timeout overrides the client timeout (per request, including reading a streamed body)
+	Timeout time.Duration
+
+	// UserAgent overrides the User-Agent header
+	UserAgent string
+
+	// MaxRetries sets the retry count for providers backed by an SDK with built-in retries (Anthropic).
+	// nil keeps the SDK default.
+	MaxRetries *int
+}
+
+// Option configures a provider at construction time.
+//
+// Example:
+//
+//	provider, err := openai.NewProvider(apiKey,
+//	    llmprovider.WithBaseURL("https://llm-proxy.internal/v1"),
+//	    llmprovider.WithTimeout(30*time.Second),
+//	)
+type Option func(*ProviderOptions)
+
+// WithBaseURL sets the API root URL.
+func WithBaseURL(baseURL string) Option {
+	return func(o *ProviderOptions) {
+		o.BaseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client. The client is copied, not modified,
+// when headers, a user agent or a timeout also need to be applied.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *ProviderOptions) {
+		o.HTTPClient = client
+	}
+}
+
+// WithHeaders adds headers sent with every request. Repeated calls merge.
+func WithHeaders(headers map[string]string) Option {
+	return func(o *ProviderOptions) {
+		if o.Headers == nil {
+			o.Headers = make(http.Header)
+		}
+		for key, value := range headers {
+			o.Headers.Set(key, value)
+		}
+	}
+}
+
+// WithTimeout sets the HTTP client timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *ProviderOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(o *ProviderOptions) {
+		o.UserAgent = userAgent
+	}
+}
+
+// WithMaxRetries sets the retry count for SDK-backed providers (Anthropic).
+// HTTP providers in this module don't retry on their own.
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *ProviderOptions) {
+		o.MaxRetries = &maxRetries
+	}
+}
+
+// ApplyOptions applies opts in order and returns the resulting settings.
+func ApplyOptions(opts ...Option) ProviderOptions {
+	var o ProviderOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// BaseURLOr returns the configured base URL (without trailing slash), or defaultURL if unset.
+func (o ProviderOptions) BaseURLOr(defaultURL string) string {
+	if o.BaseURL == "" {
+		return defaultURL
+	}
+	return strings.TrimSuffix(o.BaseURL, "/")
+}
+
+// NewHTTPClient returns the HTTP client a provider should use.
+//
+// It starts from HTTPClient (or a new client with defaultTimeout), applies Timeout, and wraps
+// the transport so Headers and UserAgent are set on every request. The caller's client is
+// never modified.
+func (o ProviderOptions) NewHTTPClient(defaultTimeout time.Duration) *http.Client {
+	var client http.Client
+	if o.HTTPClient != nil {
+		client = *o.HTTPClient
+	} else {
+		client.Timeout = defaultTimeout
+	}
+
+	if o.Timeout > 0 {
+		client.Timeout = o.Timeout
+	}
+
+	if len(o.Headers) > 0 || o.UserAgent != "" {
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		client.Transport = &headerTransport{
+			base:      base,
+			headers:   o.Headers,
+			userAgent: o.UserAgent,
+		}
+	}
+
+	return &client
+}
+
+// headerTransport adds fixed headers to every request.
+type headerTransport struct {
+	base      http.RoundTripper
+	headers   http.Header
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, values := range t.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
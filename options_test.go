@@ -0,0 +1,71 @@
+package llmprovider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestNewHTTPClient_HeadersAndUserAgent tests that headers and user agent are applied per request
+func TestNewHTTPClient_HeadersAndUserAgent(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	base := &http.Client{Timeout: time.Minute}
+	options := ApplyOptions(
+		WithHTTPClient(base),
+		WithHeaders(map[string]string{"X-Team": "docs"}),
+		WithHeaders(map[string]string{"Authorization": "Bearer proxy"}),
+		WithUserAgent("meridian-test/1.0"),
+		WithTimeout(5*time.Second),
+	)
+	client := options.NewHTTPClient(120 * time.Second)
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Authorization", "Bearer provider")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got.Get("X-Team") != "docs" || got.Get("User-Agent") != "meridian-test/1.0" {
+		t.Errorf("expected custom headers, got %v", got)
+	}
+	if got.Get("Authorization") != "Bearer proxy" {
+		t.Errorf("expected option headers to override provider headers, got %q", got.Get("Authorization"))
+	}
+	if req.Header.Get("Authorization") != "Bearer provider" {
+		t.Errorf("caller's request was modified")
+	}
+
+	if client.Timeout != 5*time.Second {
+		t.Errorf("expected timeout 5s, got %v", client.Timeout)
+	}
+	if base.Timeout != time.Minute || base.Transport != nil {
+		t.Errorf("caller's client was modified")
+	}
+}
+
+// TestApplyOptions_Defaults tests default base URL and timeout handling
+func TestApplyOptions_Defaults(t *testing.T) {
+	options := ApplyOptions()
+	if got := options.BaseURLOr("https://api.example.com/v1"); got != "https://api.example.com/v1" {
+		t.Errorf("expected default base URL, got %q", got)
+	}
+	if client := options.NewHTTPClient(120 * time.Second); client.Timeout != 120*time.Second || client.Transport != nil {
+		t.Errorf("expected plain client with default timeout, got %+v", client)
+	}
+
+	options = ApplyOptions(WithBaseURL("http://localhost:8080/"), WithMaxRetries(0))
+	if got := options.BaseURLOr("https://api.example.com/v1"); got != "http://localhost:8080" {
+		t.Errorf("expected overridden base URL without trailing slash, got %q", got)
+	}
+	if options.MaxRetries == nil || *options.MaxRetries != 0 {
+		t.Errorf("expected MaxRetries 0, got %v", options.MaxRetries)
+	}
+}
@@ -102,7 +102,7 @@ type RequestParams struct {
 	// Format: "anthropic/claude-haiku-4-5", "openai/gpt-4", etc.
 	Provider *string `json:"provider,omitempty"`
 
-	// FallbackModels lists alternative models if primary fails
+	// FallbackModels lists alternative models if primary fails (honored by FallbackProvider)
 	FallbackModels []string `json:"fallback_models,omitempty"`
 }
 
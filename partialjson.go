@@ -0,0 +1,212 @@
+package llmprovider
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PartialJSON parses a JSON document that arrives in fragments, such as the JSONDelta
+// deltas of a tool call, and returns a best-effort value after every fragment:
+//
+//	var input llmprovider.PartialJSON
+//	for event := range events {
+//	    if event.Delta != nil && event.Delta.JSONDelta != nil {
+//	        input.Append(*event.Delta.JSONDelta)
+//	        renderArgs(input.Object()) // {"path": "src/ma"} while the model types "src/main.go"
+//	    }
+//	}
+//
+// Unfinished strings are closed (so long string values grow as they stream), unfinished
+// objects and arrays are closed, and members whose key or value hasn't started yet
+// are left out. Numbers and literals appear once they are valid on their own ("12" but
+// not "-" or "tru"). The scanner state is kept between writes, so each fragment is
+// scanned once. The zero value is ready to use.
+type PartialJSON struct {
+	buf   strings.Builder
+	stack []jsonFrame
+
+	inString    bool
+	isKey       bool // The open string is an object key
+	inEscape    bool // An escape sequence is unfinished
+	escapeStart int  // Offset of the escape's backslash
+	unicodeLeft int  // Hex digits still expected by a \u escape
+
+	inToken    bool // A number or literal is in progress
+	tokenStart int
+
+	complete bool // The top-level value has ended
+	end      int  // Offset just past the top-level value
+}
+
+// jsonFrame is an open object or array.
+type jsonFrame struct {
+	kind      byte // '{' or '['
+	cut       int  // Offset just past the last complete member (or the opening bracket)
+	expectKey bool // The next string in this object is a key
+}
+
+// ParsePartialJSON parses a possibly incomplete JSON document. See PartialJSON.
+func ParsePartialJSON(data string) (interface{}, bool) {
+	var p PartialJSON
+	p.Append(data)
+	return p.Value()
+}
+
+// Append adds a fragment and advances the scanner.
+func (p *PartialJSON) Append(fragment string) {
+	offset := p.buf.Len()
+	p.buf.WriteString(fragment)
+	for i := 0; i < len(fragment); i++ {
+		p.scan(fragment[i], offset+i)
+	}
+}
+
+// String returns the raw JSON received so far.
+func (p *PartialJSON) String() string {
+	return p.buf.String()
+}
+
+// Complete reports whether a whole top-level value has been received.
+func (p *PartialJSON) Complete() bool {
+	return p.complete
+}
+
+// Value returns the best-effort value of the JSON received so far. It returns false
+// if nothing usable has arrived yet or the input is malformed.
+func (p *PartialJSON) Value() (interface{}, bool) {
+	data := p.buf.String()
+
+	var candidate string
+	switch {
+	case p.complete:
+		candidate = data[:p.end]
+
+	case len(p.stack) == 0:
+		// A top-level scalar in progress
+		if !p.inToken || !json.Valid([]byte(data[p.tokenStart:])) {
+			return nil, false
+		}
+		candidate = data
+
+	case p.inString && !p.isKey:
+		// Close the string value, dropping an unfinished escape sequence
+		if p.inEscape {
+			data = data[:p.escapeStart]
+		}
+		candidate = data + `"` + p.closers()
+
+	case p.inToken && json.Valid([]byte(data[p.tokenStart:])):
+		candidate = data + p.closers()
+
+	default:
+		// Cut back to the last complete member of the innermost container
+		candidate = data[:p.stack[len(p.stack)-1].cut] + p.closers()
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(candidate), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Object returns the value if it is a JSON object, or nil.
+func (p *PartialJSON) Object() map[string]interface{} {
+	value, _ := p.Value()
+	object, _ := value.(map[string]interface{})
+	return object
+}
+
+// closers returns the brackets that close every open container.
+func (p *PartialJSON) closers() string {
+	var b strings.Builder
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].kind == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
+
+// scan advances the scanner by one byte at offset i.
+func (p *PartialJSON) scan(c byte, i int) {
+	if p.complete {
+		return
+	}
+
+	if p.inString {
+		switch {
+		case p.unicodeLeft > 0:
+			p.unicodeLeft--
+			p.inEscape = p.unicodeLeft > 0
+		case p.inEscape:
+			if c == 'u' {
+				p.unicodeLeft = 4
+			} else {
+				p.inEscape = false
+			}
+		case c == '\\':
+			p.inEscape, p.escapeStart = true, i
+		case c == '"':
+			p.inString = false
+			if !p.isKey {
+				p.valueDone(i + 1)
+			}
+		}
+		return
+	}
+
+	if p.inToken {
+		if !isJSONDelimiter(c) {
+			return
+		}
+		p.inToken = false
+		p.valueDone(i)
+	}
+
+	switch c {
+	case ' ', '\t', '\n', '\r':
+	case '{', '[':
+		p.stack = append(p.stack, jsonFrame{kind: c, cut: i + 1, expectKey: c == '{'})
+	case '}', ']':
+		if len(p.stack) > 0 {
+			p.stack = p.stack[:len(p.stack)-1]
+		}
+		p.valueDone(i + 1)
+	case '"':
+		p.inString = true
+		p.isKey = len(p.stack) > 0 && p.stack[len(p.stack)-1].expectKey
+	case ':':
+		if len(p.stack) > 0 {
+			p.stack[len(p.stack)-1].expectKey = false
+		}
+	case ',':
+		if len(p.stack) > 0 && p.stack[len(p.stack)-1].kind == '{' {
+			p.stack[len(p.stack)-1].expectKey = true
+		}
+	default:
+		p.inToken = true
+		p.tokenStart = i
+	}
+}
+
+// valueDone records that a value ended just before offset end.
+func (p *PartialJSON) valueDone(end int) {
+	if len(p.stack) == 0 {
+		p.complete = true
+		p.end = end
+		return
+	}
+	p.stack[len(p.stack)-1].cut = end
+}
+
+// isJSONDelimiter reports whether c ends a number or literal.
+func isJSONDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', ',', ':', '}', ']', '{', '[', '"':
+		return true
+	}
+	return false
+}
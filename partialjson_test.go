@@ -0,0 +1,73 @@
+package llmprovider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPartialJSON_Prefixes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // JSON of the expected value; "" if none
+	}{
+		{``, ``},
+		{`{`, `{}`},
+		{`{"pa`, `{}`},
+		{`{"path"`, `{}`},
+		{`{"path": `, `{}`},
+		{`{"path": "src/ma`, `{"path":"src/ma"}`},
+		{`{"path": "a\`, `{"path":"a"}`},
+		{`{"path": "a\n`, `{"path":"a\n"}`},
+		{`{"path": "a\u00`, `{"path":"a"}`},
+		{`{"path": "aé`, `{"path":"aé"}`},
+		{`{"path": "a", "n": -`, `{"path":"a"}`},
+		{`{"path": "a", "n": 12`, `{"path":"a","n":12}`},
+		{`{"path": "a", "n": 12.`, `{"path":"a"}`},
+		{`{"ok": tru`, `{}`},
+		{`{"ok": true`, `{"ok":true}`},
+		{`{"lines": [1, 2, {"x": "y`, `{"lines":[1,2,{"x":"y"}]}`},
+		{`{"lines": [1, 2], "b": {`, `{"lines":[1,2],"b":{}}`},
+		{`{"a": "}\"]"`, `{"a":"}\"]"}`},
+		{`{"a": 1} trailing`, `{"a":1}`},
+		{`[`, `[]`},
+		{`{"a": ]`, ``},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePartialJSON(tt.input)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("ParsePartialJSON(%q) = %v, want none", tt.input, got)
+			}
+			continue
+		}
+		var want interface{}
+		_ = json.Unmarshal([]byte(tt.want), &want)
+		if !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("ParsePartialJSON(%q) = %v, %v; want %v", tt.input, got, ok, want)
+		}
+	}
+}
+
+func TestPartialJSON_Incremental(t *testing.T) {
+	// Feed the document one byte at a time; the value must never fail to parse once
+	// started, and must end up equal to the full document
+	doc := `{"path": "main.go", "code": "func main() {\n\tfmt.Println(\"hi ☺\")\n}", "opts": {"dry_run": false, "n": [1, 2.5e3]}}`
+	var p PartialJSON
+	for i := 0; i < len(doc); i++ {
+		p.Append(doc[i : i+1])
+		if _, ok := p.Value(); !ok {
+			t.Fatalf("no value after %q", doc[:i+1])
+		}
+	}
+
+	var want interface{}
+	_ = json.Unmarshal([]byte(doc), &want)
+	if got, _ := p.Value(); !p.Complete() || !reflect.DeepEqual(got, want) {
+		t.Errorf("final value = %v, want %v", got, want)
+	}
+	if p.Object()["path"] != "main.go" {
+		t.Errorf("Object() = %v", p.Object())
+	}
+}
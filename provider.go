@@ -31,6 +31,9 @@ type Provider interface {
 	//     if event.Delta != nil { process delta }
 	//     if event.Metadata != nil { streaming complete }
 	//   }
+	//
+	// A consumer that stops reading early must cancel ctx so the producer can exit.
+	// NewStream wraps this method in a Stream that does so automatically.
 	StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error)
 
 	// Name returns the provider identifier (e.g., ProviderAnthropic, ProviderOpenAI, ProviderLorem)
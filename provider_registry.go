@@ -1,5 +1,11 @@
 package llmprovider
 
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
 // ProviderID represents a unique provider identifier.
 // Using a typed constant prevents typos and provides compile-time safety.
 type ProviderID string
@@ -12,6 +18,9 @@ const (
 	// ProviderOpenAI is OpenAI's GPT API
 	ProviderOpenAI ProviderID = "openai"
 
+	// ProviderOpenAIResponses is OpenAI's Responses API; its blocks are still stamped ProviderOpenAI
+	ProviderOpenAIResponses ProviderID = "openai-responses"
+
 	// ProviderGoogle is Google's Gemini API
 	ProviderGoogle ProviderID = "google"
 
@@ -20,6 +29,12 @@ const (
 
 	// ProviderOpenRouter is OpenRouter's unified API (proxies multiple providers)
 	ProviderOpenRouter ProviderID = "openrouter"
+
+	// ProviderOllama is a local Ollama server (native /api/chat)
+	ProviderOllama ProviderID = "ollama"
+
+	// ProviderOpenAICompatible is a generic OpenAI-compatible server (vLLM, llama.cpp, LM Studio, ...)
+	ProviderOpenAICompatible ProviderID = "openai-compatible"
 )
 
 // String returns the string representation of the provider ID
@@ -30,9 +45,184 @@ func (p ProviderID) String() string {
 // IsValid returns true if the provider ID is a known provider
 func (p ProviderID) IsValid() bool {
 	switch p {
-	case ProviderAnthropic, ProviderOpenAI, ProviderGoogle, ProviderLorem, ProviderOpenRouter:
+	case ProviderAnthropic, ProviderOpenAI, ProviderOpenAIResponses, ProviderGoogle, ProviderLorem, ProviderOpenRouter, ProviderOllama, ProviderOpenAICompatible:
 		return true
 	default:
 		return false
 	}
 }
+
+// RegistryProviderID is the name reported by a Registry acting as a Provider.
+const RegistryProviderID ProviderID = "registry"
+
+// ModelAlias is the target of an alias: a registered provider and a concrete model name.
+type ModelAlias struct {
+	Provider ProviderID
+	Model    string
+}
+
+// Registry holds providers registered at runtime and routes requests by model name.
+//
+// A model resolves to a provider in this order:
+//  1. Explicit aliases (e.g., "sonnet" → {ProviderAnthropic, "claude-sonnet-4-5"})
+//  2. The first registered provider whose SupportsModel returns true (registration order)
+//
+// Registry implements Provider, so callers can hand any model name to one object:
+//
+//	registry := llmprovider.NewRegistry(anthropicProvider, openaiProvider)
+//	_ = registry.RegisterAlias("sonnet", llmprovider.ProviderAnthropic, "claude-sonnet-4-5")
+//	resp, err := registry.GenerateResponse(ctx, &llmprovider.GenerateRequest{Model: "sonnet", ...})
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[ProviderID]Provider
+	order     []ProviderID
+	aliases   map[string]ModelAlias
+}
+
+// NewRegistry creates a registry with the given providers registered in order.
+// Providers with duplicate names are ignored; use Register to get the error.
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{
+		providers: make(map[ProviderID]Provider),
+		aliases:   make(map[string]ModelAlias),
+	}
+	for _, provider := range providers {
+		_ = r.Register(provider)
+	}
+	return r
+}
+
+// Register adds a provider. Providers are consulted in registration order when resolving models.
+func (r *Registry) Register(provider Provider) error {
+	if provider == nil {
+		return fmt.Errorf("provider is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := provider.Name()
+	if _, exists := r.providers[id]; exists {
+		return fmt.Errorf("provider %s is already registered", id)
+	}
+
+	r.providers[id] = provider
+	r.order = append(r.order, id)
+	return nil
+}
+
+// RegisterAlias maps alias to a model served by a specific provider.
+// The provider doesn't need to be registered yet; it is looked up at resolve time.
+func (r *Registry) RegisterAlias(alias string, provider ProviderID, model string) error {
+	if alias == "" || model == "" {
+		return fmt.Errorf("alias and model are required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.aliases[alias]; exists {
+		return fmt.Errorf("alias %s is already registered", alias)
+	}
+
+	r.aliases[alias] = ModelAlias{Provider: provider, Model: model}
+	return nil
+}
+
+// Get returns the provider registered under id.
+func (r *Registry) Get(id ProviderID) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[id]
+	return provider, ok
+}
+
+// Providers returns the registered providers in registration order.
+func (r *Registry) Providers() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]Provider, 0, len(r.order))
+	for _, id := range r.order {
+		providers = append(providers, r.providers[id])
+	}
+	return providers
+}
+
+// Resolve returns the provider for model and the concrete model name to send to it
+// (which differs from model when model is an alias).
+func (r *Registry) Resolve(model string) (Provider, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if alias, ok := r.aliases[model]; ok {
+		provider, ok := r.providers[alias.Provider]
+		if !ok {
+			return nil, "", &ModelError{
+				Code:     ErrorCodeInvalidModel,
+				Model:    model,
+				Provider: alias.Provider.String(),
+				Reason:   fmt.Sprintf("alias targets provider %s, which is not registered", alias.Provider),
+				Err:      ErrInvalidModel,
+			}
+		}
+		return provider, alias.Model, nil
+	}
+
+	for _, id := range r.order {
+		if provider := r.providers[id]; provider.SupportsModel(model) {
+			return provider, model, nil
+		}
+	}
+
+	return nil, "", &ModelError{
+		Code:     ErrorCodeInvalidModel,
+		Model:    model,
+		Provider: RegistryProviderID.String(),
+		Reason:   "no registered provider supports this model",
+		Err:      ErrInvalidModel,
+	}
+}
+
+// GenerateResponse resolves req.Model and delegates to the matching provider.
+func (r *Registry) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	provider, resolved, err := r.resolveRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GenerateResponse(ctx, resolved)
+}
+
+// StreamResponse resolves req.Model and delegates to the matching provider.
+func (r *Registry) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	provider, resolved, err := r.resolveRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return provider.StreamResponse(ctx, resolved)
+}
+
+// Name returns RegistryProviderID.
+func (r *Registry) Name() ProviderID {
+	return RegistryProviderID
+}
+
+// SupportsModel returns true if the model resolves to a registered provider.
+func (r *Registry) SupportsModel(model string) bool {
+	_, _, err := r.Resolve(model)
+	return err == nil
+}
+
+// resolveRequest resolves the request's model and returns a shallow copy with the concrete model name.
+// The caller's request is never modified.
+func (r *Registry) resolveRequest(req *GenerateRequest) (Provider, *GenerateRequest, error) {
+	provider, model, err := r.Resolve(req.Model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved := *req
+	resolved.Model = model
+	return provider, &resolved, nil
+}
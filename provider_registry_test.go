@@ -0,0 +1,89 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	anthropic := &mockProvider{name: ProviderAnthropic, prefix: "claude-"}
+	openai := &mockProvider{name: ProviderOpenAI, prefix: "gpt-"}
+	registry := NewRegistry(anthropic, openai)
+
+	if err := registry.RegisterAlias("sonnet", ProviderAnthropic, "claude-sonnet-4-5"); err != nil {
+		t.Fatalf("RegisterAlias() error = %v", err)
+	}
+	if err := registry.RegisterAlias("gemini", ProviderGoogle, "gemini-2.5-flash"); err != nil {
+		t.Fatalf("RegisterAlias() error = %v", err)
+	}
+
+	tests := []struct {
+		model        string
+		wantProvider ProviderID
+		wantModel    string
+		wantErr      bool
+	}{
+		{"sonnet", ProviderAnthropic, "claude-sonnet-4-5", false},
+		{"claude-haiku-4-5", ProviderAnthropic, "claude-haiku-4-5", false},
+		{"gpt-4o", ProviderOpenAI, "gpt-4o", false},
+		{"gemini", "", "", true},   // alias to unregistered provider
+		{"llama3.2", "", "", true}, // no provider supports it
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			provider, model, err := registry.Resolve(tt.model)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidModel) {
+					t.Errorf("expected ErrInvalidModel, got %v", err)
+				}
+				if registry.SupportsModel(tt.model) {
+					t.Errorf("expected SupportsModel(%s) = false", tt.model)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if provider.Name() != tt.wantProvider || model != tt.wantModel {
+				t.Errorf("Resolve(%s) = (%s, %s), expected (%s, %s)", tt.model, provider.Name(), model, tt.wantProvider, tt.wantModel)
+			}
+		})
+	}
+
+	if err := registry.Register(&mockProvider{name: ProviderOpenAI}); err == nil {
+		t.Errorf("expected error registering duplicate provider")
+	}
+}
+
+func TestRegistry_ImplementsProvider(t *testing.T) {
+	anthropic := &mockProvider{name: ProviderAnthropic, prefix: "claude-"}
+	registry := NewRegistry(anthropic)
+	_ = registry.RegisterAlias("sonnet", ProviderAnthropic, "claude-sonnet-4-5")
+
+	var provider Provider = registry
+	req := &GenerateRequest{Model: "sonnet"}
+
+	resp, err := provider.GenerateResponse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+	if resp.Model != "claude-sonnet-4-5" {
+		t.Errorf("expected resolved model, got %s", resp.Model)
+	}
+	if req.Model != "sonnet" {
+		t.Errorf("caller's request was modified: %s", req.Model)
+	}
+
+	events, err := provider.StreamResponse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+	for range events {
+	}
+
+	if anthropic.callCount() != 2 || anthropic.calls[1] != "claude-sonnet-4-5" {
+		t.Errorf("unexpected calls: %v", anthropic.calls)
+	}
+}
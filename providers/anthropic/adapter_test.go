@@ -1,7 +1,12 @@
 package anthropic
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/haowjy/meridian-llm-go"
 )
@@ -984,3 +989,83 @@ func TestConvertToAnthropicMessages_WithMerging_Integration(t *testing.T) {
 		t.Fatalf("expected last message to have 2 blocks, got %d", len(lastMessage.Content))
 	}
 }
+
+func TestConvertToAnthropicMessages_ThinkingBlock_FromStream(t *testing.T) {
+	// A thinking block rebuilt from streamed deltas must replay with its signature
+	thinking := llmprovider.BlockTypeThinking
+	events := []llmprovider.StreamEvent{
+		{Delta: &llmprovider.BlockDelta{BlockIndex: 0, BlockType: &thinking, DeltaType: llmprovider.DeltaTypeThinking, TextDelta: strPtr("Let me think")}},
+		{Delta: &llmprovider.BlockDelta{BlockIndex: 0, DeltaType: llmprovider.DeltaTypeSignature, SignatureDelta: strPtr("4k_a")}},
+		{Metadata: &llmprovider.StreamMetadata{Model: "claude-sonnet-4-5", StopReason: "end_turn"}},
+	}
+
+	acc := llmprovider.NewAccumulator()
+	for _, event := range events {
+		if err := acc.Add(event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	resp, err := acc.Response()
+	if err != nil {
+		t.Fatalf("Response() error = %v", err)
+	}
+
+	result, err := convertToAnthropicMessages([]llmprovider.Message{{Role: "assistant", Blocks: resp.Blocks}})
+	if err != nil {
+		t.Fatalf("convertToAnthropicMessages() error = %v", err)
+	}
+
+	blocks := result[0].Content
+	if len(blocks) != 1 || blocks[0].OfThinking == nil {
+		t.Fatalf("expected 1 thinking block, got %+v", blocks)
+	}
+	if blocks[0].OfThinking.Signature != "4k_a" || blocks[0].OfThinking.Thinking != "Let me think" {
+		t.Errorf("unexpected thinking block: %+v", blocks[0].OfThinking)
+	}
+}
+
+// TestStream_EarlyBreakClosesConnection tests that breaking out of Stream.All aborts the HTTP request
+func TestStream_EarlyBreakClosesConnection(t *testing.T) {
+	disconnected := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(disconnected)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: message_start\ndata: "+`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-haiku-4-5","content":[],"stop_reason":null,"usage":{"input_tokens":1,"output_tokens":0}}}`+"\n\n")
+		_, _ = io.WriteString(w, "event: content_block_start\ndata: "+`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`+"\n\n")
+		for {
+			_, _ = io.WriteString(w, "event: content_block_delta\ndata: "+`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lorem "}}`+"\n\n")
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-key", llmprovider.WithBaseURL(server.URL), llmprovider.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	stream, err := llmprovider.NewStream(context.Background(), provider, &llmprovider.GenerateRequest{
+		Model:    "claude-haiku-4-5",
+		Messages: []llmprovider.Message{{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: strPtr("Hi")}}}},
+	})
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+	for _, err := range stream.All() {
+		if err != nil {
+			t.Fatalf("stream error = %v", err)
+		}
+		break
+	}
+
+	select {
+	case <-disconnected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server still streaming after the consumer stopped")
+	}
+}
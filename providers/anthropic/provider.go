@@ -2,7 +2,9 @@ package anthropic
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -17,18 +19,46 @@ type Provider struct {
 }
 
 // NewProvider creates a new Anthropic provider with the given API key.
-func NewProvider(apiKey string) (*Provider, error) {
+// Options are translated to the SDK's request options (base URL, HTTP client,
+// headers, per-request timeout and max retries).
+func NewProvider(apiKey string, opts ...llmprovider.Option) (*Provider, error) {
 	if apiKey == "" {
 		return nil, llmprovider.ErrInvalidAPIKey
 	}
 
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	client := anthropic.NewClient(sdkOptions(apiKey, llmprovider.ApplyOptions(opts...))...)
 
 	return &Provider{
 		client: &client,
 	}, nil
 }
 
+// sdkOptions converts library provider options to anthropic-sdk-go request options.
+func sdkOptions(apiKey string, options llmprovider.ProviderOptions) []option.RequestOption {
+	sdkOpts := []option.RequestOption{option.WithAPIKey(apiKey)}
+
+	if options.BaseURL != "" {
+		sdkOpts = append(sdkOpts, option.WithBaseURL(options.BaseURL))
+	}
+	if options.HTTPClient != nil {
+		sdkOpts = append(sdkOpts, option.WithHTTPClient(options.HTTPClient))
+	}
+	for key := range options.Headers {
+		sdkOpts = append(sdkOpts, option.WithHeader(key, options.Headers.Get(key)))
+	}
+	if options.UserAgent != "" {
+		sdkOpts = append(sdkOpts, option.WithHeader("User-Agent", options.UserAgent))
+	}
+	if options.Timeout > 0 {
+		sdkOpts = append(sdkOpts, option.WithRequestTimeout(options.Timeout))
+	}
+	if options.MaxRetries != nil {
+		sdkOpts = append(sdkOpts, option.WithMaxRetries(*options.MaxRetries))
+	}
+
+	return sdkOpts
+}
+
 // Name returns the provider identifier.
 func (p *Provider) Name() llmprovider.ProviderID {
 	return llmprovider.ProviderAnthropic
@@ -61,7 +91,7 @@ func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.Genera
 	// Call Anthropic API
 	message, err := p.client.Messages.New(ctx, apiParams)
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
+		return nil, convertAPIError(err, "anthropic API call failed")
 	}
 
 	// Convert response to library format with metadata
@@ -72,3 +102,35 @@ func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.Genera
 
 	return response, nil
 }
+
+// convertAPIError converts an SDK API error into a ProviderError carrying the response
+// headers (retry-after, anthropic-ratelimit-*), so retry and fallback logic can act on it.
+// Other errors are wrapped with prefix.
+func convertAPIError(err error, prefix string) error {
+	var apiErr *anthropic.Error
+	if !errors.As(err, &apiErr) {
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+
+	providerErr := llmprovider.NewProviderError(llmprovider.ProviderAnthropic.String(), apiErr.StatusCode, apiErr.Error(), nil)
+	switch {
+	case apiErr.StatusCode == 401 || apiErr.StatusCode == 403:
+		providerErr.Err = llmprovider.ErrInvalidAPIKey
+	case apiErr.StatusCode == 429:
+		providerErr.Err = llmprovider.ErrRateLimited
+	case apiErr.StatusCode >= 500:
+		// Includes 529 (overloaded)
+		providerErr.Code = llmprovider.ErrorCodeProviderUnavailable
+		providerErr.Retryable = true
+		providerErr.Err = llmprovider.ErrProviderUnavailable
+	default:
+		providerErr.Code = llmprovider.ErrorCodeInvalidRequest
+		providerErr.Err = llmprovider.ErrInvalidRequest
+	}
+
+	var header http.Header
+	if apiErr.Response != nil {
+		header = apiErr.Response.Header
+	}
+	return llmprovider.WithResponseHeaders(providerErr, header)
+}
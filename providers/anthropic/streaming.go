@@ -35,8 +35,24 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 	go func() {
 		defer close(eventChan)
 
-		// Call Anthropic streaming API
+		// Call Anthropic streaming API (closing the stream releases the HTTP body on early exit)
 		stream := p.client.Messages.NewStreaming(ctx, apiParams)
+		defer stream.Close()
+
+		// send delivers an event unless the consumer has cancelled, in which case the
+		// cancellation error is reported if there is buffer space (never blocking)
+		send := func(event llmprovider.StreamEvent) bool {
+			select {
+			case <-ctx.Done():
+				select {
+				case eventChan <- llmprovider.StreamEvent{Error: ctx.Err()}:
+				default:
+				}
+				return false
+			case eventChan <- event:
+				return true
+			}
+		}
 
 		// Accumulator for final message metadata
 		message := anthropic.Message{}
@@ -47,9 +63,9 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 
 			// Accumulate event into final message
 			if err := message.Accumulate(event); err != nil {
-				eventChan <- llmprovider.StreamEvent{
+				send(llmprovider.StreamEvent{
 					Error: fmt.Errorf("failed to accumulate message: %w", err),
-				}
+				})
 				return
 			}
 
@@ -57,26 +73,19 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 			// Pass accumulated message so we can emit complete blocks on ContentBlockStop
 			streamEvent := transformAnthropicStreamEvent(event, &message)
 
-			// Send to channel if not empty (check context in case consumer cancelled)
+			// Send to channel if not empty; exit if the consumer cancelled
 			if streamEvent.Delta != nil || streamEvent.Block != nil || streamEvent.Error != nil {
-				select {
-				case <-ctx.Done():
-					// Consumer cancelled, send error and exit
-					eventChan <- llmprovider.StreamEvent{
-						Error: ctx.Err(),
-					}
+				if !send(streamEvent) {
 					return
-				case eventChan <- streamEvent:
-					// Successfully sent
 				}
 			}
 		}
 
 		// Check for streaming errors
 		if err := stream.Err(); err != nil {
-			eventChan <- llmprovider.StreamEvent{
-				Error: fmt.Errorf("anthropic streaming error: %w", err),
-			}
+			send(llmprovider.StreamEvent{
+				Error: convertAPIError(err, "anthropic streaming error"),
+			})
 			return
 		}
 
@@ -101,9 +110,9 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 		}
 		metadata.ResponseMetadata = responseMetadata
 
-		eventChan <- llmprovider.StreamEvent{
+		send(llmprovider.StreamEvent{
 			Metadata: metadata,
-		}
+		})
 	}()
 
 	return eventChan, nil
@@ -0,0 +1,606 @@
+package gemini
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// convertToGeminiContents converts library messages to Gemini contents.
+// Assistant messages become "model" turns; tool results become functionResponse parts in "user" turns,
+// including tool results stored in assistant messages (split out after the calls they answer).
+func convertToGeminiContents(messages []llmprovider.Message) ([]Content, error) {
+	// Handle cross-provider server tools (e.g., Anthropic web_search replayed to Gemini)
+	messages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, llmprovider.ProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+
+	// functionResponse requires the function name, which tool_result blocks don't carry
+	toolNames := make(map[string]string)
+	for _, msg := range messages {
+		for _, block := range msg.Blocks {
+			if block.BlockType != llmprovider.BlockTypeToolUse {
+				continue
+			}
+			id, _ := block.GetToolUseID()
+			name, _ := block.GetToolName()
+			if id != "" && name != "" {
+				toolNames[id] = name
+			}
+		}
+	}
+
+	contents := make([]Content, 0, len(messages))
+
+	// Merge consecutive same-role turns
+	appendContent := func(role string, parts []Part) {
+		if len(parts) == 0 {
+			return
+		}
+		if n := len(contents); n > 0 && contents[n-1].Role == role {
+			contents[n-1].Parts = append(contents[n-1].Parts, parts...)
+			return
+		}
+		contents = append(contents, Content{Role: role, Parts: parts})
+	}
+
+	for i, msg := range messages {
+		role := "user"
+		if msg.Role == "assistant" {
+			role = "model"
+		}
+
+		parts := make([]Part, 0, len(msg.Blocks))
+		for j, block := range msg.Blocks {
+			switch block.BlockType {
+			case llmprovider.BlockTypeText:
+				if block.TextContent == nil {
+					return nil, fmt.Errorf("message %d, block %d: text block missing text_content", i, j)
+				}
+				parts = append(parts, Part{
+					Text:             *block.TextContent,
+					ThoughtSignature: thoughtSignature(block),
+				})
+
+			case llmprovider.BlockTypeThinking:
+				// Only Gemini thoughts can be replayed (signatures are opaque and provider-specific)
+				signature := thoughtSignature(block)
+				if signature == "" || block.TextContent == nil {
+					continue
+				}
+				parts = append(parts, Part{
+					Text:             *block.TextContent,
+					Thought:          true,
+					ThoughtSignature: signature,
+				})
+
+			case llmprovider.BlockTypeImage, llmprovider.BlockTypeDocument:
+				if msg.Role != "user" {
+					continue
+				}
+				part, err := convertMediaBlock(block)
+				if err != nil {
+					return nil, fmt.Errorf("message %d, block %d: %w", i, j, err)
+				}
+				parts = append(parts, part)
+
+			case llmprovider.BlockTypeToolUse:
+				if msg.Role != "assistant" {
+					continue
+				}
+				name, ok := block.GetToolName()
+				if !ok || name == "" {
+					return nil, fmt.Errorf("message %d, block %d: tool_use block missing tool_name", i, j)
+				}
+				input, _ := block.GetToolInput()
+				parts = append(parts, Part{
+					FunctionCall:     &FunctionCall{Name: name, Args: input},
+					ThoughtSignature: thoughtSignature(block),
+				})
+
+			case llmprovider.BlockTypeToolResult:
+				toolUseID, ok := block.GetToolUseID()
+				if !ok || toolUseID == "" {
+					return nil, fmt.Errorf("message %d, block %d: tool_result block missing tool_use_id", i, j)
+				}
+				name := toolNames[toolUseID]
+				if name == "" {
+					name, _ = block.Content["tool_name"].(string)
+				}
+				if name == "" {
+					return nil, fmt.Errorf("message %d, block %d: no tool_use found for tool_use_id %s", i, j, toolUseID)
+				}
+				text, isError := llmprovider.ToolResultText(block)
+				response := map[string]interface{}{"result": text}
+				if isError {
+					response = map[string]interface{}{"error": text}
+				}
+				// functionResponse must be in a user turn, so end the model turn holding the call
+				appendContent(role, parts)
+				parts = nil
+				appendContent("user", []Part{{
+					FunctionResponse: &FunctionResponse{Name: name, Response: response},
+				}})
+
+			default:
+				// Skip web_search blocks - grounding is not replayable (the grounded text is kept)
+			}
+		}
+
+		appendContent(role, parts)
+	}
+
+	return contents, nil
+}
+
+// thoughtSignature returns the thought signature stored in a Gemini block's ProviderData.
+// Blocks from other providers never carry a Gemini signature.
+func thoughtSignature(block *llmprovider.Block) string {
+	if !block.IsFromProvider(llmprovider.ProviderGoogle) || !block.HasProviderData() {
+		return ""
+	}
+
+	var providerData map[string]interface{}
+	if err := json.Unmarshal(block.ProviderData, &providerData); err != nil {
+		return ""
+	}
+	signature, _ := providerData["thought_signature"].(string)
+	return signature
+}
+
+// convertMediaBlock converts an image or document block to an inlineData or fileData part.
+// Supports Content["data"] (base64) with Content["mime_type"], or Content["file_uri"]/Content["url"].
+func convertMediaBlock(block *llmprovider.Block) (Part, error) {
+	mimeType, _ := block.Content["mime_type"].(string)
+
+	if data, ok := block.Content["data"].(string); ok && data != "" {
+		if mimeType == "" {
+			return Part{}, fmt.Errorf("%s block with inline data missing mime_type", block.BlockType)
+		}
+		if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+			return Part{}, fmt.Errorf("%s block has invalid base64 data: %w", block.BlockType, err)
+		}
+		return Part{InlineData: &Blob{MimeType: mimeType, Data: data}}, nil
+	}
+
+	for _, key := range []string{"file_uri", "url"} {
+		if uri, ok := block.Content[key].(string); ok && uri != "" {
+			return Part{FileData: &FileData{MimeType: mimeType, FileURI: uri}}, nil
+		}
+	}
+
+	return Part{}, fmt.Errorf("%s block missing data, file_uri or url", block.BlockType)
+}
+
+// responseAccumulator merges Gemini response chunks into library blocks.
+// A non-streaming response is a single chunk, so both paths share this conversion logic.
+//
+// Consecutive text (or thought) parts are merged into one block. Thought signatures are
+// stored in Block.ProviderData{"thought_signature"} so they replay verbatim, the same way
+// Anthropic thinking signatures do.
+type responseAccumulator struct {
+	blocks       []*pendingBlock
+	grounding    *GroundingMetadata
+	finishReason string
+	blockReason  string
+	usage        *UsageMetadata
+	modelVersion string
+	responseID   string
+}
+
+// pendingBlock is a block being accumulated from parts.
+type pendingBlock struct {
+	blockType string
+	text      strings.Builder
+	call      *FunctionCall
+	toolUseID string
+	signature string
+}
+
+// addChunk merges a response chunk and returns the deltas it produced.
+func (a *responseAccumulator) addChunk(chunk *GenerateContentResponse) []*llmprovider.BlockDelta {
+	if chunk.ResponseID != "" {
+		a.responseID = chunk.ResponseID
+	}
+	if chunk.ModelVersion != "" {
+		a.modelVersion = chunk.ModelVersion
+	}
+	if chunk.UsageMetadata != nil {
+		a.usage = chunk.UsageMetadata
+	}
+	if chunk.PromptFeedback != nil && chunk.PromptFeedback.BlockReason != "" {
+		a.blockReason = chunk.PromptFeedback.BlockReason
+	}
+
+	if len(chunk.Candidates) == 0 {
+		return nil
+	}
+
+	// Only the first candidate is used (candidateCount is never set)
+	candidate := chunk.Candidates[0]
+	if candidate.FinishReason != "" {
+		a.finishReason = candidate.FinishReason
+	}
+	if candidate.GroundingMetadata != nil {
+		a.grounding = candidate.GroundingMetadata
+	}
+
+	var deltas []*llmprovider.BlockDelta
+	for _, part := range candidate.Content.Parts {
+		deltas = append(deltas, a.addPart(part)...)
+	}
+	return deltas
+}
+
+// addPart merges a single part and returns the deltas it produced.
+func (a *responseAccumulator) addPart(part Part) []*llmprovider.BlockDelta {
+	var deltas []*llmprovider.BlockDelta
+
+	switch {
+	case part.FunctionCall != nil:
+		// Function calls arrive complete (never split across chunks)
+		toolUseID := part.FunctionCall.ID
+		if toolUseID == "" {
+			toolUseID = newToolCallID()
+		}
+		a.blocks = append(a.blocks, &pendingBlock{
+			blockType: llmprovider.BlockTypeToolUse,
+			call:      part.FunctionCall,
+			toolUseID: toolUseID,
+		})
+		blockIndex := len(a.blocks) - 1
+
+		blockType := llmprovider.BlockTypeToolUse
+		name := part.FunctionCall.Name
+		deltas = append(deltas, &llmprovider.BlockDelta{
+			BlockIndex:   blockIndex,
+			BlockType:    &blockType,
+			DeltaType:    llmprovider.DeltaTypeToolCallStart,
+			ToolCallID:   &toolUseID,
+			ToolCallName: &name,
+		})
+		if args, err := json.Marshal(argsOrEmpty(part.FunctionCall.Args)); err == nil {
+			argsJSON := string(args)
+			deltas = append(deltas, &llmprovider.BlockDelta{
+				BlockIndex: blockIndex,
+				DeltaType:  llmprovider.DeltaTypeJSON,
+				JSONDelta:  &argsJSON,
+			})
+		}
+
+	case part.Text != "" || part.Thought:
+		blockType := llmprovider.BlockTypeText
+		deltaType := llmprovider.DeltaTypeText
+		if part.Thought {
+			blockType = llmprovider.BlockTypeThinking
+			deltaType = llmprovider.DeltaTypeThinking
+		}
+
+		last := a.last()
+		if last == nil || last.blockType != blockType || (part.ThoughtSignature != "" && last.signature != "") {
+			last = &pendingBlock{blockType: blockType}
+			a.blocks = append(a.blocks, last)
+			deltas = append(deltas, &llmprovider.BlockDelta{
+				BlockIndex: len(a.blocks) - 1,
+				BlockType:  &blockType,
+				DeltaType:  deltaType,
+			})
+		}
+
+		if part.Text != "" {
+			last.text.WriteString(part.Text)
+			text := part.Text
+			deltas = append(deltas, &llmprovider.BlockDelta{
+				BlockIndex: len(a.blocks) - 1,
+				DeltaType:  deltaType,
+				TextDelta:  &text,
+			})
+		}
+	}
+
+	// Signatures can arrive on the part they belong to or on a trailing empty part
+	if part.ThoughtSignature != "" {
+		if last := a.last(); last != nil {
+			last.signature = part.ThoughtSignature
+			signature := part.ThoughtSignature
+			deltas = append(deltas, &llmprovider.BlockDelta{
+				BlockIndex:     len(a.blocks) - 1,
+				DeltaType:      llmprovider.DeltaTypeSignature,
+				SignatureDelta: &signature,
+			})
+		}
+	}
+
+	return deltas
+}
+
+// last returns the block currently being accumulated, or nil.
+func (a *responseAccumulator) last() *pendingBlock {
+	if len(a.blocks) == 0 {
+		return nil
+	}
+	return a.blocks[len(a.blocks)-1]
+}
+
+// buildBlocks builds the complete library blocks, including grounding blocks and citations.
+// Grounding is reported once for the whole response, so web_search blocks are appended last.
+func (a *responseAccumulator) buildBlocks() ([]*llmprovider.Block, error) {
+	providerIDStr := llmprovider.ProviderGoogle.String()
+	blocks := make([]*llmprovider.Block, 0, len(a.blocks)+2)
+
+	for i, pending := range a.blocks {
+		block := &llmprovider.Block{
+			BlockType: pending.blockType,
+			Sequence:  i,
+			Provider:  &providerIDStr,
+		}
+
+		switch pending.blockType {
+		case llmprovider.BlockTypeToolUse:
+			// Function tools are always executed by our backend
+			executionSide := llmprovider.ExecutionSideServer
+			block.ExecutionSide = &executionSide
+			block.Content = map[string]interface{}{
+				"tool_use_id": pending.toolUseID,
+				"tool_name":   pending.call.Name,
+				"input":       argsOrEmpty(pending.call.Args),
+			}
+		default:
+			text := pending.text.String()
+			block.TextContent = &text
+		}
+
+		// Signature is provider-specific metadata, stored in ProviderData (not Content)
+		if pending.signature != "" {
+			providerData, err := json.Marshal(map[string]interface{}{
+				"thought_signature": pending.signature,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("marshal thought signature: %w", err)
+			}
+			block.ProviderData = providerData
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	if a.grounding != nil && (len(a.grounding.WebSearchQueries) > 0 || len(a.grounding.GroundingChunks) > 0) {
+		attachGroundingCitations(blocks, a.grounding)
+		blocks = append(blocks, convertGroundingToBlocks(a.grounding, len(blocks))...)
+	}
+
+	return blocks, nil
+}
+
+// convertGroundingToBlocks converts grounding metadata into a web_search_use block (the queries)
+// and a web_search_result block (the grounding chunks). Gemini doesn't assign an ID to searches,
+// so a synthetic tool_use_id links the two blocks.
+func convertGroundingToBlocks(grounding *GroundingMetadata, sequence int) []*llmprovider.Block {
+	providerIDStr := llmprovider.ProviderGoogle.String()
+	executionSide := llmprovider.ExecutionSideProvider
+	toolUseID := newToolCallID()
+
+	input := map[string]interface{}{}
+	if len(grounding.WebSearchQueries) > 0 {
+		input["query"] = grounding.WebSearchQueries[0]
+		input["queries"] = grounding.WebSearchQueries
+	}
+
+	results := make([]map[string]interface{}, 0, len(grounding.GroundingChunks))
+	for _, chunk := range grounding.GroundingChunks {
+		if chunk.Web == nil {
+			continue
+		}
+		results = append(results, map[string]interface{}{
+			"title": chunk.Web.Title,
+			"url":   chunk.Web.URI,
+		})
+	}
+
+	return []*llmprovider.Block{
+		{
+			BlockType: llmprovider.BlockTypeWebSearch,
+			Sequence:  sequence,
+			Content: map[string]interface{}{
+				"tool_use_id": toolUseID,
+				"tool_name":   "web_search",
+				"input":       input,
+			},
+			ExecutionSide: &executionSide,
+			Provider:      &providerIDStr,
+		},
+		{
+			BlockType: llmprovider.BlockTypeWebSearchResult,
+			Sequence:  sequence + 1,
+			Content: map[string]interface{}{
+				"tool_use_id": toolUseID,
+				"results":     results,
+			},
+			ExecutionSide: &executionSide,
+			Provider:      &providerIDStr,
+		},
+	}
+}
+
+// attachGroundingCitations converts groundingSupports to citations on the text blocks they support.
+// Each support is attached to the text block containing its segment (falling back to the last text block).
+func attachGroundingCitations(blocks []*llmprovider.Block, grounding *GroundingMetadata) {
+	var textBlocks []*llmprovider.Block
+	for _, block := range blocks {
+		if block.BlockType == llmprovider.BlockTypeText && block.TextContent != nil {
+			textBlocks = append(textBlocks, block)
+		}
+	}
+	if len(textBlocks) == 0 {
+		return
+	}
+
+	for _, support := range grounding.GroundingSupports {
+		target := textBlocks[len(textBlocks)-1]
+		for _, block := range textBlocks {
+			if support.Segment.Text != "" && strings.Contains(*block.TextContent, support.Segment.Text) {
+				target = block
+				break
+			}
+		}
+
+		for k, chunkIndex := range support.GroundingChunkIndices {
+			if chunkIndex < 0 || chunkIndex >= len(grounding.GroundingChunks) || grounding.GroundingChunks[chunkIndex].Web == nil {
+				continue
+			}
+			web := grounding.GroundingChunks[chunkIndex].Web
+
+			startIndex := support.Segment.StartIndex
+			endIndex := support.Segment.EndIndex
+			resultIndex := chunkIndex
+			citation := llmprovider.Citation{
+				Type:        "grounding_support",
+				URL:         web.URI,
+				Title:       web.Title,
+				StartIndex:  &startIndex,
+				EndIndex:    &endIndex,
+				ResultIndex: &resultIndex,
+			}
+			if support.Segment.Text != "" {
+				citedText := support.Segment.Text
+				citation.CitedText = &citedText
+			}
+			if k < len(support.ConfidenceScores) {
+				citation.ProviderData, _ = json.Marshal(map[string]interface{}{
+					"confidence_score": support.ConfidenceScores[k],
+				})
+			}
+
+			target.Citations = append(target.Citations, citation)
+		}
+	}
+}
+
+// stopReason maps the Gemini finish reason to library stop_reason.
+func (a *responseAccumulator) stopReason() string {
+	if a.blockReason != "" {
+		return "refusal"
+	}
+
+	for _, pending := range a.blocks {
+		if pending.blockType == llmprovider.BlockTypeToolUse && (a.finishReason == "STOP" || a.finishReason == "") {
+			return "tool_use"
+		}
+	}
+
+	return mapFinishReason(a.finishReason)
+}
+
+// inputTokens returns the prompt token count.
+func (a *responseAccumulator) inputTokens() int {
+	if a.usage == nil {
+		return 0
+	}
+	return a.usage.PromptTokenCount
+}
+
+// outputTokens returns the output token count, including thinking tokens (billed as output).
+func (a *responseAccumulator) outputTokens() int {
+	if a.usage == nil {
+		return 0
+	}
+	return a.usage.CandidatesTokenCount + a.usage.ThoughtsTokenCount
+}
+
+// responseMetadata collects provider-specific response data shared by both paths.
+func (a *responseAccumulator) responseMetadata() map[string]interface{} {
+	metadata := make(map[string]interface{})
+
+	if a.responseID != "" {
+		metadata["response_id"] = a.responseID
+	}
+	if a.modelVersion != "" {
+		metadata["model_version"] = a.modelVersion
+	}
+	if a.finishReason != "" {
+		metadata["finish_reason"] = a.finishReason
+	}
+	if a.blockReason != "" {
+		metadata["block_reason"] = a.blockReason
+	}
+
+	if a.usage != nil {
+		metadata["total_tokens"] = a.usage.TotalTokenCount
+		if a.usage.ThoughtsTokenCount > 0 {
+			metadata["thinking_tokens"] = a.usage.ThoughtsTokenCount
+		}
+		if a.usage.CachedContentTokenCount > 0 {
+			metadata["cache_read_input_tokens"] = a.usage.CachedContentTokenCount
+		}
+	}
+
+	if a.grounding != nil {
+		if len(a.grounding.WebSearchQueries) > 0 {
+			metadata["web_search_queries"] = a.grounding.WebSearchQueries
+		}
+		// Google requires the search entry point to be displayed with grounded results
+		if a.grounding.SearchEntryPoint != nil && a.grounding.SearchEntryPoint.RenderedContent != "" {
+			metadata["search_entry_point"] = a.grounding.SearchEntryPoint.RenderedContent
+		}
+	}
+
+	return metadata
+}
+
+// convertFromGenerateContentResponse converts a Gemini response to library format.
+func convertFromGenerateContentResponse(resp *GenerateContentResponse, model string) (*llmprovider.GenerateResponse, error) {
+	acc := &responseAccumulator{}
+	acc.addChunk(resp)
+
+	blocks, err := acc.buildBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	responseModel := acc.modelVersion
+	if responseModel == "" {
+		responseModel = model
+	}
+
+	return &llmprovider.GenerateResponse{
+		Blocks:           blocks,
+		Model:            responseModel,
+		InputTokens:      acc.inputTokens(),
+		OutputTokens:     acc.outputTokens(),
+		StopReason:       acc.stopReason(),
+		ResponseMetadata: acc.responseMetadata(),
+	}, nil
+}
+
+// mapFinishReason maps Gemini finishReason to library stop_reason.
+func mapFinishReason(finishReason string) string {
+	switch finishReason {
+	case "STOP":
+		return "end_turn"
+	case "MAX_TOKENS":
+		return "max_tokens"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "refusal"
+	default:
+		return strings.ToLower(finishReason)
+	}
+}
+
+// argsOrEmpty returns an empty map for nil function call arguments.
+func argsOrEmpty(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return map[string]interface{}{}
+	}
+	return args
+}
+
+// newToolCallID generates a tool_use_id for function calls without one.
+// Gemini only returns IDs on some models; tool results are matched by ID in the library.
+func newToolCallID() string {
+	return "call_" + strings.ToLower(rand.Text())
+}
@@ -0,0 +1,294 @@
+package gemini
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/haowjy/meridian-llm-go"
+)
+
+// TestConvertToGeminiContents_ToolRoundTrip tests functionCall/functionResponse conversion
+func TestConvertToGeminiContents_ToolRoundTrip(t *testing.T) {
+	text := "What's the weather?"
+	messages := []llmprovider.Message{
+		{
+			Role:   "user",
+			Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &text}},
+		},
+		{
+			Role: "assistant",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolUse,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_1",
+						"tool_name":   "get_weather",
+						"input":       map[string]interface{}{"city": "Paris"},
+					},
+				},
+			},
+		},
+		{
+			Role: "user",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolResult,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_1",
+						"content":     "sunny",
+					},
+				},
+			},
+		},
+	}
+
+	contents, err := convertToGeminiContents(messages)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(contents) != 3 {
+		t.Fatalf("expected 3 contents, got %d", len(contents))
+	}
+
+	if contents[1].Role != "model" || contents[1].Parts[0].FunctionCall == nil {
+		t.Fatalf("expected model functionCall, got %+v", contents[1])
+	}
+
+	response := contents[2].Parts[0].FunctionResponse
+	if response == nil {
+		t.Fatalf("expected functionResponse, got %+v", contents[2].Parts[0])
+	}
+	if response.Name != "get_weather" {
+		t.Errorf("expected functionResponse name 'get_weather', got '%s'", response.Name)
+	}
+	if response.Response["result"] != "sunny" {
+		t.Errorf("expected result 'sunny', got %v", response.Response)
+	}
+}
+
+// TestConvertToGeminiContents_ToolResultInAssistantMessage tests that tool results stored
+// with their tool_use in an assistant message are split into a user turn
+func TestConvertToGeminiContents_ToolResultInAssistantMessage(t *testing.T) {
+	question, answer := "What's the weather?", "It's sunny in Paris."
+	messages := []llmprovider.Message{
+		{
+			Role:   "user",
+			Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &question}},
+		},
+		{
+			Role: "assistant",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolUse,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_1",
+						"tool_name":   "get_weather",
+						"input":       map[string]interface{}{"city": "Paris"},
+					},
+				},
+				{
+					BlockType: llmprovider.BlockTypeToolResult,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_1",
+						"content":     "sunny",
+					},
+				},
+				{BlockType: llmprovider.BlockTypeText, TextContent: &answer},
+			},
+		},
+	}
+
+	contents, err := convertToGeminiContents(messages)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(contents) != 4 {
+		t.Fatalf("expected 4 contents, got %d: %+v", len(contents), contents)
+	}
+	if contents[1].Role != "model" || len(contents[1].Parts) != 1 || contents[1].Parts[0].FunctionCall == nil {
+		t.Errorf("expected a model turn with only the functionCall, got %+v", contents[1])
+	}
+	if contents[2].Role != "user" || len(contents[2].Parts) != 1 || contents[2].Parts[0].FunctionResponse == nil {
+		t.Errorf("expected a user turn with the functionResponse, got %+v", contents[2])
+	}
+	if contents[3].Role != "model" || contents[3].Parts[0].Text != answer {
+		t.Errorf("expected the answer in a model turn, got %+v", contents[3])
+	}
+}
+
+// TestConvertFromGenerateContentResponse_ThoughtSignatures tests that signatures round-trip via ProviderData
+func TestConvertFromGenerateContentResponse_ThoughtSignatures(t *testing.T) {
+	resp := &GenerateContentResponse{
+		Candidates: []Candidate{
+			{
+				Content: Content{
+					Role: "model",
+					Parts: []Part{
+						{Text: "Let me think", Thought: true},
+						{FunctionCall: &FunctionCall{Name: "get_weather", Args: map[string]interface{}{"city": "Paris"}}, ThoughtSignature: "sig_abc"},
+					},
+				},
+				FinishReason: "STOP",
+			},
+		},
+		UsageMetadata: &UsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, ThoughtsTokenCount: 20, TotalTokenCount: 35},
+		ModelVersion:  "gemini-2.5-flash",
+	}
+
+	result, err := convertFromGenerateContentResponse(resp, "gemini-2.5-flash")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(result.Blocks))
+	}
+	if result.Blocks[0].BlockType != llmprovider.BlockTypeThinking {
+		t.Errorf("expected BlockTypeThinking, got %s", result.Blocks[0].BlockType)
+	}
+	if thoughtSignature(result.Blocks[1]) != "sig_abc" {
+		t.Errorf("expected thought signature 'sig_abc' in ProviderData, got %s", string(result.Blocks[1].ProviderData))
+	}
+
+	if result.StopReason != "tool_use" {
+		t.Errorf("expected StopReason 'tool_use', got '%s'", result.StopReason)
+	}
+	if result.OutputTokens != 25 {
+		t.Errorf("expected OutputTokens 25 (candidates + thoughts), got %d", result.OutputTokens)
+	}
+	if result.ResponseMetadata["thinking_tokens"] != 20 {
+		t.Errorf("expected thinking_tokens 20, got %v", result.ResponseMetadata["thinking_tokens"])
+	}
+
+	// Replay: the signature must be attached to the functionCall part
+	contents, err := convertToGeminiContents([]llmprovider.Message{{Role: "assistant", Blocks: result.Blocks}})
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if len(contents) != 1 || len(contents[0].Parts) != 1 {
+		t.Fatalf("expected 1 content with 1 part (unsigned thought dropped), got %+v", contents)
+	}
+	if contents[0].Parts[0].ThoughtSignature != "sig_abc" {
+		t.Errorf("expected replayed signature 'sig_abc', got '%s'", contents[0].Parts[0].ThoughtSignature)
+	}
+}
+
+// TestConvertFromGenerateContentResponse_Grounding tests Google Search grounding conversion
+func TestConvertFromGenerateContentResponse_Grounding(t *testing.T) {
+	resp := &GenerateContentResponse{
+		Candidates: []Candidate{
+			{
+				Content:      Content{Role: "model", Parts: []Part{{Text: "Spain won Euro 2024."}}},
+				FinishReason: "STOP",
+				GroundingMetadata: &GroundingMetadata{
+					WebSearchQueries: []string{"euro 2024 winner"},
+					GroundingChunks: []GroundingChunk{
+						{Web: &WebChunk{URI: "https://example.com/a", Title: "example.com"}},
+					},
+					GroundingSupports: []GroundingSupport{
+						{
+							Segment:               Segment{StartIndex: 0, EndIndex: 20, Text: "Spain won Euro 2024."},
+							GroundingChunkIndices: []int{0},
+							ConfidenceScores:      []float64{0.9},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := convertFromGenerateContentResponse(resp, "gemini-2.5-flash")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result.Blocks) != 3 {
+		t.Fatalf("expected 3 blocks (text, web_search_use, web_search_result), got %d", len(result.Blocks))
+	}
+
+	text := result.Blocks[0]
+	if len(text.Citations) != 1 || text.Citations[0].Type != "grounding_support" || text.Citations[0].URL != "https://example.com/a" {
+		t.Errorf("unexpected citations: %+v", text.Citations)
+	}
+
+	if result.Blocks[1].BlockType != llmprovider.BlockTypeWebSearch || result.Blocks[2].BlockType != llmprovider.BlockTypeWebSearchResult {
+		t.Errorf("expected web_search_use and web_search_result, got %s and %s", result.Blocks[1].BlockType, result.Blocks[2].BlockType)
+	}
+	useID, _ := result.Blocks[1].GetToolUseID()
+	resultID, _ := result.Blocks[2].GetToolUseID()
+	if useID == "" || useID != resultID {
+		t.Errorf("expected matching tool_use_id, got '%s' and '%s'", useID, resultID)
+	}
+
+	if result.StopReason != "end_turn" {
+		t.Errorf("expected StopReason 'end_turn', got '%s'", result.StopReason)
+	}
+}
+
+// TestStreamEvents tests SSE parsing and merging of streamed text parts
+func TestStreamEvents(t *testing.T) {
+	body := strings.Join([]string{
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"Hel"}]}}]}`,
+		``,
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"lo"}]}}]}`,
+		``,
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"","thoughtSignature":"sig"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":2,"totalTokenCount":5}}`,
+		``,
+	}, "\n")
+
+	eventChan := make(chan llmprovider.StreamEvent, 100)
+	if err := streamEvents(context.Background(), strings.NewReader(body), "gemini-2.5-flash", eventChan); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	close(eventChan)
+
+	var blocks []*llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	for event := range eventChan {
+		if event.Block != nil {
+			blocks = append(blocks, event.Block)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 merged block, got %d", len(blocks))
+	}
+	if *blocks[0].TextContent != "Hello" {
+		t.Errorf("expected text 'Hello', got '%s'", *blocks[0].TextContent)
+	}
+	if thoughtSignature(blocks[0]) != "sig" {
+		t.Errorf("expected trailing signature attached to text block, got %s", string(blocks[0].ProviderData))
+	}
+
+	if metadata == nil || metadata.StopReason != "end_turn" || metadata.InputTokens != 3 {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+// TestMapFinishReason tests finishReason mapping
+func TestMapFinishReason(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"STOP", "end_turn"},
+		{"MAX_TOKENS", "max_tokens"},
+		{"SAFETY", "refusal"},
+		{"MALFORMED_FUNCTION_CALL", "malformed_function_call"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := mapFinishReason(tt.input)
+			if result != tt.expected {
+				t.Errorf("mapFinishReason(%s) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
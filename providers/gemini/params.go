@@ -0,0 +1,328 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// GenerateContentRequest represents a Gemini generateContent request.
+type GenerateContentRequest struct {
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	Tools             []Tool            `json:"tools,omitempty"`
+	ToolConfig        *ToolConfig       `json:"toolConfig,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+}
+
+// Content is a turn in the conversation (role "user" or "model").
+type Content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []Part `json:"parts"`
+}
+
+// Part is a single piece of content. Exactly one data field is set.
+type Part struct {
+	Text             string            `json:"text,omitempty"`
+	Thought          bool              `json:"thought,omitempty"`          // True for thought summaries
+	ThoughtSignature string            `json:"thoughtSignature,omitempty"` // Opaque signature, must be replayed verbatim
+	InlineData       *Blob             `json:"inlineData,omitempty"`
+	FileData         *FileData         `json:"fileData,omitempty"`
+	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// Blob is inline base64-encoded media.
+type Blob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"` // Base64
+}
+
+// FileData references uploaded or remote media.
+type FileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+// FunctionCall is a function call requested by the model.
+type FunctionCall struct {
+	ID   string                 `json:"id,omitempty"`
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// FunctionResponse returns the result of a function call to the model.
+type FunctionResponse struct {
+	ID       string                 `json:"id,omitempty"`
+	Name     string                 `json:"name"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// Tool is a tool definition: function declarations or a built-in tool.
+type Tool struct {
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations,omitempty"`
+	GoogleSearch         *GoogleSearch         `json:"googleSearch,omitempty"`
+}
+
+// FunctionDeclaration declares a function the model may call.
+type FunctionDeclaration struct {
+	Name                 string                 `json:"name"`
+	Description          string                 `json:"description,omitempty"`
+	ParametersJSONSchema map[string]interface{} `json:"parametersJsonSchema,omitempty"` // JSON Schema
+}
+
+// GoogleSearch enables grounding with Google Search.
+type GoogleSearch struct{}
+
+// ToolConfig configures function calling.
+type ToolConfig struct {
+	FunctionCallingConfig *FunctionCallingConfig `json:"functionCallingConfig,omitempty"`
+}
+
+// FunctionCallingConfig controls which functions the model may call.
+type FunctionCallingConfig struct {
+	Mode                 string   `json:"mode"` // "AUTO", "ANY", "NONE"
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+// GenerationConfig contains sampling and output parameters.
+type GenerationConfig struct {
+	MaxOutputTokens    *int            `json:"maxOutputTokens,omitempty"`
+	Temperature        *float64        `json:"temperature,omitempty"`
+	TopP               *float64        `json:"topP,omitempty"`
+	TopK               *int            `json:"topK,omitempty"`
+	StopSequences      []string        `json:"stopSequences,omitempty"`
+	Seed               *int            `json:"seed,omitempty"`
+	PresencePenalty    *float64        `json:"presencePenalty,omitempty"`
+	FrequencyPenalty   *float64        `json:"frequencyPenalty,omitempty"`
+	ResponseMimeType   string          `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema interface{}     `json:"responseJsonSchema,omitempty"`
+	ThinkingConfig     *ThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+// ThinkingConfig configures thinking for Gemini 2.5+ models.
+type ThinkingConfig struct {
+	ThinkingBudget  *int `json:"thinkingBudget,omitempty"` // -1 = dynamic, 0 = off (Flash only)
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
+}
+
+// GenerateContentResponse represents a Gemini response (or a streaming chunk).
+type GenerateContentResponse struct {
+	Candidates     []Candidate     `json:"candidates"`
+	PromptFeedback *PromptFeedback `json:"promptFeedback,omitempty"`
+	UsageMetadata  *UsageMetadata  `json:"usageMetadata,omitempty"`
+	ModelVersion   string          `json:"modelVersion,omitempty"`
+	ResponseID     string          `json:"responseId,omitempty"`
+}
+
+// Candidate is a generated response candidate.
+type Candidate struct {
+	Content           Content            `json:"content"`
+	FinishReason      string             `json:"finishReason,omitempty"` // "STOP", "MAX_TOKENS", "SAFETY", ...
+	GroundingMetadata *GroundingMetadata `json:"groundingMetadata,omitempty"`
+	Index             int                `json:"index"`
+}
+
+// PromptFeedback reports why a prompt was blocked.
+type PromptFeedback struct {
+	BlockReason string `json:"blockReason,omitempty"`
+}
+
+// GroundingMetadata describes Google Search grounding for a candidate.
+type GroundingMetadata struct {
+	WebSearchQueries  []string           `json:"webSearchQueries,omitempty"`
+	GroundingChunks   []GroundingChunk   `json:"groundingChunks,omitempty"`
+	GroundingSupports []GroundingSupport `json:"groundingSupports,omitempty"`
+	SearchEntryPoint  *SearchEntryPoint  `json:"searchEntryPoint,omitempty"`
+}
+
+// GroundingChunk is a source used for grounding.
+type GroundingChunk struct {
+	Web *WebChunk `json:"web,omitempty"`
+}
+
+// WebChunk is a web source.
+type WebChunk struct {
+	URI   string `json:"uri"`
+	Title string `json:"title"`
+}
+
+// GroundingSupport links a segment of the response text to grounding chunks.
+type GroundingSupport struct {
+	Segment               Segment   `json:"segment"`
+	GroundingChunkIndices []int     `json:"groundingChunkIndices"`
+	ConfidenceScores      []float64 `json:"confidenceScores,omitempty"`
+}
+
+// Segment is a span of response text.
+type Segment struct {
+	PartIndex  int    `json:"partIndex,omitempty"`
+	StartIndex int    `json:"startIndex,omitempty"`
+	EndIndex   int    `json:"endIndex"`
+	Text       string `json:"text"`
+}
+
+// SearchEntryPoint is the Google Search suggestion chip that must be displayed with grounded results.
+type SearchEntryPoint struct {
+	RenderedContent string `json:"renderedContent,omitempty"`
+}
+
+// UsageMetadata reports token usage.
+type UsageMetadata struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	ThoughtsTokenCount      int `json:"thoughtsTokenCount,omitempty"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+}
+
+// buildGenerateContentRequest constructs a Gemini request from a GenerateRequest.
+// This function is shared between GenerateResponse and StreamResponse to avoid duplication.
+func buildGenerateContentRequest(req *llmprovider.GenerateRequest) (*GenerateContentRequest, error) {
+	// Extract params or use defaults
+	params := req.Params
+	if params == nil {
+		params = &llmprovider.RequestParams{}
+	}
+
+	contents, err := convertToGeminiContents(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert messages: %w", err)
+	}
+
+	geminiReq := &GenerateContentRequest{
+		Contents: contents,
+	}
+
+	// System prompt goes in systemInstruction
+	if params.System != nil && *params.System != "" {
+		geminiReq.SystemInstruction = &Content{Parts: []Part{{Text: *params.System}}}
+	}
+
+	config := &GenerationConfig{
+		MaxOutputTokens:  params.MaxTokens,
+		Temperature:      params.Temperature,
+		TopP:             params.TopP,
+		TopK:             params.TopK,
+		Seed:             params.Seed,
+		PresencePenalty:  params.PresencePenalty,
+		FrequencyPenalty: params.FrequencyPenalty,
+	}
+
+	// Stop sequences
+	if len(params.Stop) > 0 {
+		config.StopSequences = params.Stop
+	}
+
+	// Structured outputs
+	if params.ResponseFormat != nil {
+		switch params.ResponseFormat.Type {
+		case "json_object":
+			config.ResponseMimeType = "application/json"
+		case "json_schema":
+			config.ResponseMimeType = "application/json"
+			// Accept both {"name": ..., "schema": {...}} (OpenAI style) and a bare schema
+			if schema, ok := params.ResponseFormat.JSONSchema.(map[string]interface{}); ok {
+				if inner, ok := schema["schema"]; ok {
+					config.ResponseJSONSchema = inner
+				} else {
+					config.ResponseJSONSchema = schema
+				}
+			}
+		}
+	}
+
+	// Thinking - convert user-friendly level to token budget (ThinkingBudget overrides)
+	if params.ThinkingEnabled != nil && *params.ThinkingEnabled {
+		thinking := &ThinkingConfig{IncludeThoughts: true}
+		if params.ThinkingBudget != nil {
+			thinking.ThinkingBudget = params.ThinkingBudget
+		} else if params.ThinkingLevel != nil {
+			budget, err := params.GetThinkingBudgetTokens()
+			if err != nil {
+				return nil, fmt.Errorf("invalid thinking level: %w", err)
+			}
+			thinking.ThinkingBudget = &budget
+		}
+		config.ThinkingConfig = thinking
+	}
+
+	geminiReq.GenerationConfig = config
+
+	// Tools
+	if len(params.Tools) > 0 {
+		tools, err := convertToGeminiTools(params.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tools: %w", err)
+		}
+		geminiReq.Tools = tools
+	}
+
+	// Tool choice (only meaningful when function declarations are present)
+	if params.ToolChoice != nil && hasFunctionDeclarations(geminiReq.Tools) {
+		toolConfig, err := convertToolChoice(params.ToolChoice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool choice: %w", err)
+		}
+		geminiReq.ToolConfig = toolConfig
+	}
+
+	return geminiReq, nil
+}
+
+// BuildGenerateContentRequestDebug builds the Gemini request payload for debugging.
+// It converts the library GenerateRequest to Gemini's GenerateContentRequest format
+// and returns it as a map[string]interface{} for inspection. No network calls are made.
+func BuildGenerateContentRequestDebug(req *llmprovider.GenerateRequest) (map[string]interface{}, error) {
+	geminiReq, err := buildGenerateContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(geminiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gemini request: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gemini request: %w", err)
+	}
+
+	return result, nil
+}
+
+// convertToolChoice converts library tool choice to Gemini's function calling config.
+func convertToolChoice(choice interface{}) (*ToolConfig, error) {
+	tc, ok := choice.(*llmprovider.ToolChoice)
+	if !ok {
+		return nil, fmt.Errorf("tool_choice must be *llmprovider.ToolChoice")
+	}
+
+	// Typed nil pointer means provider default
+	if tc == nil {
+		return nil, nil
+	}
+
+	if err := tc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tool choice: %w", err)
+	}
+
+	config := &FunctionCallingConfig{}
+	switch tc.Mode {
+	case llmprovider.ToolChoiceModeAuto:
+		config.Mode = "AUTO"
+	case llmprovider.ToolChoiceModeRequired:
+		config.Mode = "ANY"
+	case llmprovider.ToolChoiceModeNone:
+		config.Mode = "NONE"
+	case llmprovider.ToolChoiceModeSpecific:
+		config.Mode = "ANY"
+		config.AllowedFunctionNames = []string{*tc.ToolName}
+	default:
+		return nil, fmt.Errorf("unsupported tool choice mode: %s", tc.Mode)
+	}
+
+	return &ToolConfig{FunctionCallingConfig: config}, nil
+}
@@ -0,0 +1,208 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// Provider implements the llmprovider.Provider interface for the Google Gemini API.
+//
+// Supported features:
+// - Function calling (functionDeclarations, functionCallingConfig)
+// - Thinking budgets (ThinkingLevel/ThinkingBudget → thinkingConfig) with thought summaries
+// - Thought signatures (stored in Block.ProviderData["thought_signature"] and replayed)
+// - Google Search grounding (provider-side search tool → googleSearch) → web_search blocks + citations
+// - Structured outputs (ResponseFormat → responseMimeType/responseJsonSchema)
+type Provider struct {
+	apiKey     string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewProvider creates a new Gemini provider with the given API key.
+// Options override the default base URL, HTTP client, headers and timeout.
+func NewProvider(apiKey string, opts ...llmprovider.Option) (*Provider, error) {
+	if apiKey == "" {
+		return nil, llmprovider.ErrInvalidAPIKey
+	}
+
+	options := llmprovider.ApplyOptions(opts...)
+
+	return &Provider{
+		apiKey:     apiKey,
+		httpClient: options.NewHTTPClient(120 * time.Second),
+		baseURL:    options.BaseURLOr("https://generativelanguage.googleapis.com/v1beta"),
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() llmprovider.ProviderID {
+	return llmprovider.ProviderGoogle
+}
+
+// SupportsModel returns true if this provider supports the given model.
+// Gemini models start with "gemini-" (optionally prefixed with "models/").
+func (p *Provider) SupportsModel(model string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(model, "models/"), "gemini-")
+}
+
+// validateModel returns a ModelError if the model is not supported.
+func (p *Provider) validateModel(model string) error {
+	if !p.SupportsModel(model) {
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: p.Name().String(),
+			Reason:   "model not supported by Gemini (must start with 'gemini-')",
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	}
+	return nil
+}
+
+// GenerateResponse generates a non-streaming response from Gemini.
+func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if err := p.validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Gemini API request (shared logic)
+	geminiReq, err := buildGenerateContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := p.buildHTTPRequest(ctx, req.Model, "generateContent", nil, geminiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var geminiResp GenerateContentResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	response, err := convertFromGenerateContentResponse(&geminiResp, req.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert response: %w", err)
+	}
+
+	return response, nil
+}
+
+// buildHTTPRequest creates a JSON POST request for a model method (e.g., "generateContent").
+func (p *Provider) buildHTTPRequest(ctx context.Context, model, method string, query url.Values, payload interface{}) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:%s", p.baseURL, url.PathEscape(strings.TrimPrefix(model, "models/")), method)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("x-goog-api-key", p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return httpReq, nil
+}
+
+// errorResponse is the error envelope returned by the Gemini API.
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"` // "INVALID_ARGUMENT", "RESOURCE_EXHAUSTED", ...
+	} `json:"error"`
+}
+
+// handleErrorResponse parses error responses from Gemini.
+func (p *Provider) handleErrorResponse(resp *http.Response, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Message == "" {
+		// Fallback to plain text error
+		return llmprovider.NewProviderError(p.Name().String(), resp.StatusCode, strings.TrimSpace(string(body)), llmprovider.ErrProviderUnavailable)
+	}
+
+	message := errResp.Error.Message
+
+	switch {
+	case resp.StatusCode == 401 || resp.StatusCode == 403 || strings.Contains(message, "API key not valid"):
+		// Invalid keys are reported as 400 INVALID_ARGUMENT
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidAPIKey,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidAPIKey,
+		}
+	case resp.StatusCode == 429:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeRateLimited,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  true,
+			Err:        llmprovider.ErrRateLimited,
+		}
+	case resp.StatusCode == 404:
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: p.Name().String(),
+			Reason:   message,
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	case resp.StatusCode == 400:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidRequest,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidRequest,
+		}
+	default:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeProviderUnavailable,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  resp.StatusCode >= 500,
+			Err:        llmprovider.ErrProviderUnavailable,
+		}
+	}
+}
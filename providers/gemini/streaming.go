@@ -0,0 +1,144 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/sse"
+)
+
+// StreamResponse generates a streaming response from Gemini.
+func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	if err := p.validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Gemini API request (shared logic)
+	geminiReq, err := buildGenerateContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// alt=sse switches streamGenerateContent from a JSON array to server-sent events
+	httpReq, err := p.buildHTTPRequest(ctx, req.Model, "streamGenerateContent", url.Values{"alt": {"sse"}}, geminiReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini HTTP request failed: %w", err)
+	}
+
+	// Check for immediate errors
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	eventChan := make(chan llmprovider.StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+		defer resp.Body.Close()
+
+		if err := streamEvents(ctx, resp.Body, req.Model, eventChan); err != nil {
+			select {
+			case eventChan <- llmprovider.StreamEvent{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
+
+// streamEvents reads SSE chunks, emits deltas as parts arrive and complete blocks at the end.
+// Complete blocks are emitted last because grounding metadata (citations) arrives with the final chunk.
+func streamEvents(ctx context.Context, body io.Reader, model string, eventChan chan<- llmprovider.StreamEvent) error {
+	// Thought signatures and inline data can make single chunks large; the reader has no line limit
+	reader := sse.NewReader(body)
+
+	acc := &responseAccumulator{}
+
+	send := func(event llmprovider.StreamEvent) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case eventChan <- event:
+			return nil
+		}
+	}
+
+	for {
+		sseEvent, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+
+		data := strings.TrimSpace(sseEvent.Data)
+		if data == "" {
+			continue
+		}
+
+		var chunk GenerateContentResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			var errResp errorResponse
+			if json.Unmarshal([]byte(data), &errResp) == nil && errResp.Error.Message != "" {
+				return &llmprovider.ProviderError{
+					Code:       llmprovider.ErrorCodeProviderUnavailable,
+					Provider:   llmprovider.ProviderGoogle.String(),
+					StatusCode: errResp.Error.Code,
+					Message:    errResp.Error.Message,
+					Retryable:  errResp.Error.Code >= 500 || errResp.Error.Code == 429,
+					Err:        llmprovider.ErrProviderUnavailable,
+				}
+			}
+			// Ignore unparseable chunks
+			continue
+		}
+
+		for _, delta := range acc.addChunk(&chunk) {
+			if err := send(llmprovider.StreamEvent{Delta: delta}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Emit complete blocks (for persistence)
+	blocks, err := acc.buildBlocks()
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if err := send(llmprovider.StreamEvent{Block: block}); err != nil {
+			return err
+		}
+	}
+
+	responseModel := acc.modelVersion
+	if responseModel == "" {
+		responseModel = model
+	}
+
+	return send(llmprovider.StreamEvent{
+		Metadata: &llmprovider.StreamMetadata{
+			Model:            responseModel,
+			InputTokens:      acc.inputTokens(),
+			OutputTokens:     acc.outputTokens(),
+			StopReason:       acc.stopReason(),
+			ResponseMetadata: acc.responseMetadata(),
+		},
+	})
+}
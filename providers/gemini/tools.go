@@ -0,0 +1,70 @@
+package gemini
+
+import (
+	"fmt"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// convertToGeminiTools converts library Tool format to Gemini format.
+// Function tools are grouped into a single functionDeclarations tool; a provider-side
+// search tool becomes the built-in googleSearch tool (grounding).
+func convertToGeminiTools(tools []llmprovider.Tool) ([]Tool, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	var declarations []FunctionDeclaration
+	var result []Tool
+
+	for i, tool := range tools {
+		// Route based on function name (OpenAI format uses tool.Function.Name)
+		switch {
+		case isSearchTool(&tool) && tool.ExecutionSide == llmprovider.ExecutionSideProvider:
+			result = append(result, Tool{GoogleSearch: &GoogleSearch{}})
+
+		default:
+			// All other tools are function declarations (executed by our backend)
+			declaration, err := convertCustomTool(&tool)
+			if err != nil {
+				return nil, fmt.Errorf("tool %d (%s): %w", i, tool.Function.Name, err)
+			}
+			declarations = append(declarations, declaration)
+		}
+	}
+
+	if len(declarations) > 0 {
+		result = append([]Tool{{FunctionDeclarations: declarations}}, result...)
+	}
+
+	return result, nil
+}
+
+// isSearchTool returns true if the tool is the built-in search tool.
+func isSearchTool(tool *llmprovider.Tool) bool {
+	return tool.Function.Name == "search" || tool.Function.Name == "web_search"
+}
+
+// convertCustomTool converts a custom function tool to a Gemini function declaration.
+// Flatten and rename (parameters → parametersJsonSchema).
+func convertCustomTool(tool *llmprovider.Tool) (FunctionDeclaration, error) {
+	if tool.Function.Name == "" {
+		return FunctionDeclaration{}, fmt.Errorf("function name is required")
+	}
+
+	return FunctionDeclaration{
+		Name:                 tool.Function.Name,
+		Description:          tool.Function.Description,
+		ParametersJSONSchema: tool.Function.Parameters,
+	}, nil
+}
+
+// hasFunctionDeclarations reports whether any tool declares functions.
+func hasFunctionDeclarations(tools []Tool) bool {
+	for _, tool := range tools {
+		if len(tool.FunctionDeclarations) > 0 {
+			return true
+		}
+	}
+	return false
+}
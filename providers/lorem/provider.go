@@ -37,60 +37,10 @@ func (p *Provider) SupportsModel(model string) bool {
 	return strings.HasPrefix(model, "lorem-")
 }
 
-// GenerateResponse generates a complete lorem ipsum response with a 10-second delay.
-// This simulates a blocking API call to a real LLM provider.
+// GenerateResponse generates a complete lorem ipsum response by collecting StreamResponse,
+// so both paths produce the same rotating blocks, usage and stop reason.
 func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
-	// Validate model
-	if !p.SupportsModel(req.Model) {
-		return nil, &llmprovider.ModelError{
-			Model:    req.Model,
-			Provider: p.Name().String(),
-			Reason:   "model not supported by Lorem provider (must start with 'lorem-')",
-			Err:      llmprovider.ErrInvalidModel,
-		}
-	}
-
-	// Extract parameters
-	params := req.Params
-	if params == nil {
-		params = &llmprovider.RequestParams{}
-	}
-	maxTokens := params.GetMaxTokens(4096)
-
-	// Simulate 10-second processing delay
-	select {
-	case <-time.After(10 * time.Second):
-		// Continue after delay
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
-
-	// Generate lorem ipsum text
-	// Estimate: 1 token ≈ 4 characters
-	targetChars := maxTokens * 4
-	text := p.generateText(targetChars)
-
-	// Estimate token counts (rough approximation)
-	inputTokens := p.estimateTokens(req.Messages)
-	outputTokens := len(strings.Fields(text)) // Word count as proxy
-
-	// Create response
-	return &llmprovider.GenerateResponse{
-		Blocks: []*llmprovider.Block{
-			{
-				BlockType:   llmprovider.BlockTypeText,
-				TextContent: &text,
-			},
-		},
-		Model:        req.Model,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		StopReason:   "end_turn",
-		ResponseMetadata: map[string]interface{}{
-			"mock":     true,
-			"provider": "lorem",
-		},
-	}, nil
+	return llmprovider.GenerateFromStream(ctx, p.StreamResponse, req)
 }
 
 // getStreamDelay returns the delay between words based on the model name.
@@ -208,7 +158,7 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 
 				outputTokens, cutoff, err := p.streamTextBlock(ctx, eventChan, blockIndex, targetWords, req.Model)
 				if err != nil {
-					eventChan <- llmprovider.StreamEvent{Error: err}
+					send(ctx, eventChan, llmprovider.StreamEvent{Error: err})
 					return
 				}
 				totalOutputTokens += outputTokens
@@ -230,7 +180,7 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 
 				outputTokens, cutoff, err := p.streamThinkingBlock(ctx, eventChan, blockIndex, targetWords, req.Model)
 				if err != nil {
-					eventChan <- llmprovider.StreamEvent{Error: err}
+					send(ctx, eventChan, llmprovider.StreamEvent{Error: err})
 					return
 				}
 				totalOutputTokens += outputTokens
@@ -255,7 +205,7 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 				builtInTool := params.Tools[toolIndex%len(params.Tools)]
 				outputTokens, err := p.streamToolUseBlockFromBuiltIn(ctx, eventChan, blockIndex, &builtInTool, req.Model)
 				if err != nil {
-					eventChan <- llmprovider.StreamEvent{Error: err}
+					send(ctx, eventChan, llmprovider.StreamEvent{Error: err})
 					return
 				}
 				totalOutputTokens += outputTokens
@@ -278,14 +228,15 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 		log.Printf("[LOREM] Loop exited: totalOutputTokens=%d, maxTokens=%d, blockIndex=%d",
 			totalOutputTokens, maxTokens, blockIndex)
 
-		// If we exhausted token budget, mark as cutoff
-		if totalOutputTokens >= maxTokens {
+		// Exhausting the token budget is only a cutoff for cutoff models;
+		// other models are written to fit the budget and finish normally
+		if totalOutputTokens >= maxTokens && isCutoffModel(req.Model) {
 			stopReason = "max_tokens"
 		}
 
 		// Send final metadata
 		inputTokens := p.estimateTokens(req.Messages)
-		eventChan <- llmprovider.StreamEvent{
+		send(ctx, eventChan, llmprovider.StreamEvent{
 			Metadata: &llmprovider.StreamMetadata{
 				Model:        req.Model,
 				InputTokens:  inputTokens,
@@ -296,7 +247,7 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 					"provider": "lorem",
 				},
 			},
-		}
+		})
 	}()
 
 	return eventChan, nil
@@ -308,11 +259,13 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 func (p *Provider) streamThinkingBlock(ctx context.Context, eventChan chan<- llmprovider.StreamEvent, blockIndex int, targetWords int, model string) (int, bool, error) {
 	// Send block start WITHOUT signature (signature comes at the end)
 	thinkingType := llmprovider.BlockTypeThinking
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Delta: &llmprovider.BlockDelta{
 			BlockIndex: blockIndex,
 			BlockType:  &thinkingType,
 		},
+	}); err != nil {
+		return 0, false, err
 	}
 
 	// Generate thinking text
@@ -325,33 +278,33 @@ func (p *Provider) streamThinkingBlock(ctx context.Context, eventChan chan<- llm
 	// Stream words with model-specific delay
 	wordsSent := 0
 	for _, word := range words {
-		select {
-		case <-ctx.Done():
-			return wordsSent, false, ctx.Err()
-		default:
-		}
-
 		delta := word + " "
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: blockIndex,
 				DeltaType:  llmprovider.DeltaTypeThinking,
 				TextDelta:  &delta,
 			},
+		}); err != nil {
+			return wordsSent, false, err
 		}
 
-		time.Sleep(delay)
+		if err := sleep(ctx, delay); err != nil {
+			return wordsSent, false, err
+		}
 		wordsSent++
 	}
 
 	// AFTER all thinking text, send signature as final delta
 	signature := "4k_a" // Mock Anthropic signature
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Delta: &llmprovider.BlockDelta{
 			BlockIndex:     blockIndex,
 			DeltaType:      llmprovider.DeltaTypeSignature,
 			SignatureDelta: &signature,
 		},
+	}); err != nil {
+		return wordsSent, false, err
 	}
 
 	return wordsSent, false, nil
@@ -363,7 +316,7 @@ func (p *Provider) streamToolUseBlock(ctx context.Context, eventChan chan<- llmp
 	// Send block start with tool metadata
 	toolUseType := llmprovider.BlockTypeToolUse
 	toolID := fmt.Sprintf("toolu_%s_%d", tool.name, blockIndex)
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Delta: &llmprovider.BlockDelta{
 			BlockIndex:   blockIndex,
 			BlockType:    &toolUseType,
@@ -371,6 +324,8 @@ func (p *Provider) streamToolUseBlock(ctx context.Context, eventChan chan<- llmp
 			ToolCallID:   &toolID,
 			ToolCallName: &tool.name,
 		},
+	}); err != nil {
+		return 0, err
 	}
 
 	// Serialize tool input to JSON
@@ -385,22 +340,20 @@ func (p *Provider) streamToolUseBlock(ctx context.Context, eventChan chan<- llmp
 
 	// Stream JSON character by character (simulating incremental JSON building)
 	for i, char := range jsonStr {
-		select {
-		case <-ctx.Done():
-			return i, ctx.Err()
-		default:
-		}
-
 		delta := string(char)
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: blockIndex,
 				DeltaType:  llmprovider.DeltaTypeJSON,
 				JSONDelta:  &delta,
 			},
+		}); err != nil {
+			return i, err
 		}
 
-		time.Sleep(delay / 10) // JSON streams faster than words
+		if err := sleep(ctx, delay/10); err != nil { // JSON streams faster than words
+			return i, err
+		}
 	}
 
 	// Estimate tokens (rough: 1 token per 4 chars in JSON)
@@ -414,11 +367,13 @@ func (p *Provider) streamToolUseBlock(ctx context.Context, eventChan chan<- llmp
 func (p *Provider) streamTextBlock(ctx context.Context, eventChan chan<- llmprovider.StreamEvent, blockIndex int, maxTokens int, model string) (int, bool, error) {
 	// Send block start
 	textType := llmprovider.BlockTypeText
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Delta: &llmprovider.BlockDelta{
 			BlockIndex: blockIndex,
 			BlockType:  &textType,
 		},
+	}); err != nil {
+		return 0, false, err
 	}
 
 	// Determine target words
@@ -440,12 +395,6 @@ func (p *Provider) streamTextBlock(ctx context.Context, eventChan chan<- llmprov
 	// Stream words with potential cutoff
 	wordsSent := 0
 	for _, word := range words {
-		select {
-		case <-ctx.Done():
-			return wordsSent, false, ctx.Err()
-		default:
-		}
-
 		// Check if we hit max_tokens limit (only for cutoff models)
 		if cutoffModel && wordsSent >= maxTokens {
 			// Cut off streaming
@@ -453,15 +402,19 @@ func (p *Provider) streamTextBlock(ctx context.Context, eventChan chan<- llmprov
 		}
 
 		delta := word + " "
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: blockIndex,
 				DeltaType:  llmprovider.DeltaTypeTextDelta,
 				TextDelta:  &delta,
 			},
+		}); err != nil {
+			return wordsSent, false, err
 		}
 
-		time.Sleep(delay)
+		if err := sleep(ctx, delay); err != nil {
+			return wordsSent, false, err
+		}
 		wordsSent++
 	}
 
@@ -469,17 +422,6 @@ func (p *Provider) streamTextBlock(ctx context.Context, eventChan chan<- llmprov
 	return wordsSent, false, nil
 }
 
-// generateText generates lorem ipsum text with approximately targetChars characters.
-func (p *Provider) generateText(targetChars int) string {
-	var sb strings.Builder
-	for sb.Len() < targetChars {
-		paragraph := p.generator.Paragraph(3, 5)
-		sb.WriteString(paragraph)
-		sb.WriteString("\n\n")
-	}
-	return strings.TrimSpace(sb.String())
-}
-
 // generateTextWords generates lorem ipsum text with approximately targetWords words.
 func (p *Provider) generateTextWords(targetWords int) string {
 	var sb strings.Builder
@@ -543,7 +485,7 @@ func (p *Provider) streamToolUseBlockFromBuiltIn(ctx context.Context, eventChan
 	toolUseType := llmprovider.BlockTypeToolUse
 	toolID := fmt.Sprintf("toolu_%s_%d", tool.Function.Name, blockIndex)
 
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Delta: &llmprovider.BlockDelta{
 			BlockIndex:   blockIndex,
 			BlockType:    &toolUseType,
@@ -551,6 +493,8 @@ func (p *Provider) streamToolUseBlockFromBuiltIn(ctx context.Context, eventChan
 			ToolCallID:   &toolID,
 			ToolCallName: &tool.Function.Name,
 		},
+	}); err != nil {
+		return 0, err
 	}
 
 	// Note: ExecutionSide is set at the Block level, not in Delta
@@ -568,22 +512,20 @@ func (p *Provider) streamToolUseBlockFromBuiltIn(ctx context.Context, eventChan
 
 	// Stream JSON character by character (simulating incremental JSON building)
 	for i, char := range jsonStr {
-		select {
-		case <-ctx.Done():
-			return i, ctx.Err()
-		default:
-		}
-
 		delta := string(char)
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: blockIndex,
 				DeltaType:  llmprovider.DeltaTypeJSON,
 				JSONDelta:  &delta,
 			},
+		}); err != nil {
+			return i, err
 		}
 
-		time.Sleep(delay / 10) // JSON streams faster than words
+		if err := sleep(ctx, delay/10); err != nil { // JSON streams faster than words
+			return i, err
+		}
 	}
 
 	// Estimate tokens (rough: 1 token per 4 chars in JSON)
@@ -605,3 +547,26 @@ func (p *Provider) estimateTokens(messages []llmprovider.Message) int {
 	}
 	return totalWords
 }
+
+// send delivers event unless ctx is done, so the streaming goroutine exits instead of
+// blocking when the consumer stops reading (see llmprovider.Stream).
+func send(ctx context.Context, eventChan chan<- llmprovider.StreamEvent, event llmprovider.StreamEvent) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case eventChan <- event:
+		return nil
+	}
+}
+
+// sleep waits for d, returning early with ctx.Err() if ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
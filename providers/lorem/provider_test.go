@@ -94,6 +94,31 @@ func TestProvider_GenerateResponse(t *testing.T) {
 	}
 }
 
+// TestProvider_GenerateResponse_Thinking tests that thinking signatures land in ProviderData,
+// where the Anthropic adapter reads them when replaying
+func TestProvider_GenerateResponse_Thinking(t *testing.T) {
+	provider := NewProvider()
+	thinking := true
+
+	resp, err := provider.GenerateResponse(context.Background(), &llmprovider.GenerateRequest{
+		Model:  "lorem-fast",
+		Params: &llmprovider.RequestParams{MaxTokens: intPtr(40), ThinkingEnabled: &thinking},
+	})
+	if err != nil {
+		t.Fatalf("GenerateResponse failed: %v", err)
+	}
+
+	if len(resp.Blocks) < 2 || resp.Blocks[1].BlockType != llmprovider.BlockTypeThinking {
+		t.Fatalf("expected a thinking block second, got %+v", resp.Blocks)
+	}
+	if got := string(resp.Blocks[1].ProviderData); got != `{"signature":"4k_a"}` {
+		t.Errorf("expected signature in ProviderData, got %q", got)
+	}
+	if resp.Blocks[1].Content != nil {
+		t.Errorf("expected no Content on thinking block, got %v", resp.Blocks[1].Content)
+	}
+}
+
 func TestProvider_GenerateResponse_Cutoff(t *testing.T) {
 	provider := NewProvider()
 	ctx := context.Background()
@@ -321,3 +346,33 @@ func stringPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+// TestProvider_StreamResponse_Cancel tests that the stream stops promptly when the consumer cancels
+func TestProvider_StreamResponse_Cancel(t *testing.T) {
+	provider := NewProvider()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventChan, err := provider.StreamResponse(ctx, &llmprovider.GenerateRequest{
+		Model:  "lorem-slow",
+		Params: &llmprovider.RequestParams{MaxTokens: intPtr(2000)},
+	})
+	if err != nil {
+		t.Fatalf("StreamResponse failed: %v", err)
+	}
+
+	<-eventChan
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-eventChan:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("stream still producing after cancellation")
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package ollama
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// convertToOllamaMessages converts library messages to Ollama format.
+//
+// Blocks are walked in order and tool_result blocks are split out into role:"tool" messages,
+// so both proper user-turn tool results and the naive "one assistant message per turn"
+// layout (tool_use, tool_result, text, ...) produce valid alternation.
+func convertToOllamaMessages(messages []llmprovider.Message) ([]Message, error) {
+	// Handle cross-provider server tools (e.g., Anthropic web_search replayed to Ollama)
+	messages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, llmprovider.ProviderOllama)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tool messages carry the function name, which tool_result blocks don't
+	toolNames := make(map[string]string)
+	for _, msg := range messages {
+		for _, block := range msg.Blocks {
+			if block.BlockType != llmprovider.BlockTypeToolUse {
+				continue
+			}
+			id, _ := block.GetToolUseID()
+			name, _ := block.GetToolName()
+			if id != "" && name != "" {
+				toolNames[id] = name
+			}
+		}
+	}
+
+	result := make([]Message, 0, len(messages))
+
+	for i, msg := range messages {
+		current := Message{Role: msg.Role}
+		var texts []string
+
+		flush := func() {
+			current.Content = strings.Join(texts, "\n\n")
+			if current.Content != "" || current.Thinking != "" || len(current.Images) > 0 || len(current.ToolCalls) > 0 {
+				result = append(result, current)
+			}
+			current = Message{Role: msg.Role}
+			texts = nil
+		}
+
+		for j, block := range msg.Blocks {
+			switch block.BlockType {
+			case llmprovider.BlockTypeText:
+				if block.TextContent == nil {
+					return nil, fmt.Errorf("message %d, block %d: text block missing text_content", i, j)
+				}
+				texts = append(texts, *block.TextContent)
+
+			case llmprovider.BlockTypeThinking:
+				// Thinking is plain text in Ollama (no signatures), so any provider's thinking replays
+				if msg.Role == "assistant" && block.TextContent != nil {
+					if current.Thinking != "" {
+						current.Thinking += "\n\n"
+					}
+					current.Thinking += *block.TextContent
+				}
+
+			case llmprovider.BlockTypeImage:
+				if msg.Role != "user" {
+					continue
+				}
+				image, err := imageFromBlock(block)
+				if err != nil {
+					return nil, fmt.Errorf("message %d, block %d: %w", i, j, err)
+				}
+				current.Images = append(current.Images, image)
+
+			case llmprovider.BlockTypeToolUse:
+				if msg.Role != "assistant" {
+					continue
+				}
+				toolCall, err := convertToolUseToToolCall(block)
+				if err != nil {
+					return nil, fmt.Errorf("message %d, block %d: %w", i, j, err)
+				}
+				current.ToolCalls = append(current.ToolCalls, toolCall)
+
+			case llmprovider.BlockTypeToolResult:
+				toolUseID, ok := block.GetToolUseID()
+				if !ok || toolUseID == "" {
+					return nil, fmt.Errorf("message %d, block %d: tool_result block missing tool_use_id", i, j)
+				}
+				name := toolNames[toolUseID]
+				if name == "" {
+					name, _ = block.Content["tool_name"].(string)
+				}
+
+				flush()
+				text, _ := llmprovider.ToolResultText(block)
+				result = append(result, Message{
+					Role:     "tool",
+					Content:  text,
+					ToolName: name,
+				})
+
+			default:
+				// Skip documents and web_search blocks - Ollama has no equivalent
+			}
+		}
+
+		flush()
+	}
+
+	return result, nil
+}
+
+// imageFromBlock extracts base64 image data from an image block.
+// Ollama only accepts inline base64 (no URLs).
+func imageFromBlock(block *llmprovider.Block) (string, error) {
+	data, ok := block.Content["data"].(string)
+	if !ok || data == "" {
+		return "", fmt.Errorf("image block missing base64 data (Ollama does not fetch image URLs)")
+	}
+	if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+		return "", fmt.Errorf("image block has invalid base64 data: %w", err)
+	}
+	return data, nil
+}
+
+// convertToolUseToToolCall converts a tool_use block to an Ollama ToolCall.
+func convertToolUseToToolCall(block *llmprovider.Block) (ToolCall, error) {
+	toolUseID, _ := block.GetToolUseID()
+
+	name, ok := block.GetToolName()
+	if !ok || name == "" {
+		return ToolCall{}, fmt.Errorf("tool_use block missing tool_name")
+	}
+
+	input, _ := block.GetToolInput()
+
+	return ToolCall{
+		ID: toolUseID,
+		Function: FunctionCall{
+			Name:      name,
+			Arguments: argsOrEmpty(input),
+		},
+	}, nil
+}
+
+// responseAccumulator merges Ollama response chunks into library blocks.
+// A non-streaming response is a single chunk, so both paths share this conversion logic.
+//
+// Consecutive thinking (or content) fragments are merged into one block; a new block starts
+// whenever the kind changes. Tool calls arrive complete and become one block each.
+type responseAccumulator struct {
+	blocks []*pendingBlock
+	final  *ChatResponse // The chunk with done=true (carries done_reason and token counts)
+	model  string
+}
+
+// pendingBlock is a block being accumulated from chunks.
+type pendingBlock struct {
+	blockType string
+	text      strings.Builder
+	call      *ToolCall
+}
+
+// addChunk merges a response chunk and returns the deltas it produced.
+func (a *responseAccumulator) addChunk(chunk *ChatResponse) []*llmprovider.BlockDelta {
+	var deltas []*llmprovider.BlockDelta
+
+	if chunk.Model != "" {
+		a.model = chunk.Model
+	}
+
+	if chunk.Message.Thinking != "" {
+		deltas = append(deltas, a.addText(llmprovider.BlockTypeThinking, llmprovider.DeltaTypeThinking, chunk.Message.Thinking)...)
+	}
+	if chunk.Message.Content != "" {
+		deltas = append(deltas, a.addText(llmprovider.BlockTypeText, llmprovider.DeltaTypeText, chunk.Message.Content)...)
+	}
+
+	for i := range chunk.Message.ToolCalls {
+		call := chunk.Message.ToolCalls[i]
+		if call.ID == "" {
+			call.ID = newToolCallID()
+		}
+		a.blocks = append(a.blocks, &pendingBlock{
+			blockType: llmprovider.BlockTypeToolUse,
+			call:      &call,
+		})
+		blockIndex := len(a.blocks) - 1
+
+		blockType := llmprovider.BlockTypeToolUse
+		id := call.ID
+		name := call.Function.Name
+		deltas = append(deltas, &llmprovider.BlockDelta{
+			BlockIndex:   blockIndex,
+			BlockType:    &blockType,
+			DeltaType:    llmprovider.DeltaTypeToolCallStart,
+			ToolCallID:   &id,
+			ToolCallName: &name,
+		})
+		if args, err := json.Marshal(argsOrEmpty(call.Function.Arguments)); err == nil {
+			argsJSON := string(args)
+			deltas = append(deltas, &llmprovider.BlockDelta{
+				BlockIndex: blockIndex,
+				DeltaType:  llmprovider.DeltaTypeJSON,
+				JSONDelta:  &argsJSON,
+			})
+		}
+	}
+
+	if chunk.Done {
+		a.final = chunk
+	}
+
+	return deltas
+}
+
+// addText appends a text or thinking fragment, starting a new block when the kind changes.
+func (a *responseAccumulator) addText(blockType, deltaType, text string) []*llmprovider.BlockDelta {
+	var deltas []*llmprovider.BlockDelta
+
+	var last *pendingBlock
+	if n := len(a.blocks); n > 0 {
+		last = a.blocks[n-1]
+	}
+	if last == nil || last.blockType != blockType {
+		last = &pendingBlock{blockType: blockType}
+		a.blocks = append(a.blocks, last)
+		deltas = append(deltas, &llmprovider.BlockDelta{
+			BlockIndex: len(a.blocks) - 1,
+			BlockType:  &blockType,
+			DeltaType:  deltaType,
+		})
+	}
+
+	last.text.WriteString(text)
+	deltas = append(deltas, &llmprovider.BlockDelta{
+		BlockIndex: len(a.blocks) - 1,
+		DeltaType:  deltaType,
+		TextDelta:  &text,
+	})
+
+	return deltas
+}
+
+// buildBlocks converts the accumulated state into library blocks.
+func (a *responseAccumulator) buildBlocks() []*llmprovider.Block {
+	providerIDStr := llmprovider.ProviderOllama.String()
+	blocks := make([]*llmprovider.Block, 0, len(a.blocks))
+
+	for i, pending := range a.blocks {
+		block := &llmprovider.Block{
+			BlockType: pending.blockType,
+			Sequence:  i,
+			Provider:  &providerIDStr,
+		}
+
+		switch pending.blockType {
+		case llmprovider.BlockTypeToolUse:
+			// Ollama has no built-in tools, so tool calls are always executed by our backend
+			executionSide := llmprovider.ExecutionSideServer
+			block.ExecutionSide = &executionSide
+			block.Content = map[string]interface{}{
+				"tool_use_id": pending.call.ID,
+				"tool_name":   pending.call.Function.Name,
+				"input":       argsOrEmpty(pending.call.Function.Arguments),
+			}
+		default:
+			text := pending.text.String()
+			block.TextContent = &text
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// stopReason maps done_reason to the library stop_reason.
+// Ollama reports "stop" even when the model called tools, so tool calls take precedence.
+func (a *responseAccumulator) stopReason() string {
+	for _, pending := range a.blocks {
+		if pending.blockType == llmprovider.BlockTypeToolUse {
+			return "tool_use"
+		}
+	}
+	if a.final == nil {
+		return ""
+	}
+	return mapDoneReason(a.final.DoneReason)
+}
+
+// inputTokens returns prompt_eval_count from the final chunk.
+func (a *responseAccumulator) inputTokens() int {
+	if a.final == nil {
+		return 0
+	}
+	return a.final.PromptEvalCount
+}
+
+// outputTokens returns eval_count from the final chunk.
+func (a *responseAccumulator) outputTokens() int {
+	if a.final == nil {
+		return 0
+	}
+	return a.final.EvalCount
+}
+
+// responseMetadata returns Ollama-specific metadata (durations are in nanoseconds).
+func (a *responseAccumulator) responseMetadata() map[string]interface{} {
+	metadata := make(map[string]interface{})
+	if a.final == nil {
+		return metadata
+	}
+
+	if a.final.DoneReason != "" {
+		metadata["done_reason"] = a.final.DoneReason
+	}
+	metadata["total_tokens"] = a.final.PromptEvalCount + a.final.EvalCount
+	metadata["total_duration_ns"] = a.final.TotalDuration
+	metadata["load_duration_ns"] = a.final.LoadDuration
+	metadata["prompt_eval_duration_ns"] = a.final.PromptEvalDuration
+	metadata["eval_duration_ns"] = a.final.EvalDuration
+
+	return metadata
+}
+
+// convertFromChatResponse converts a non-streaming Ollama response to library format.
+func convertFromChatResponse(resp *ChatResponse, model string) *llmprovider.GenerateResponse {
+	acc := &responseAccumulator{}
+	acc.addChunk(resp)
+
+	responseModel := acc.model
+	if responseModel == "" {
+		responseModel = model
+	}
+
+	return &llmprovider.GenerateResponse{
+		Blocks:           acc.buildBlocks(),
+		Model:            responseModel,
+		InputTokens:      acc.inputTokens(),
+		OutputTokens:     acc.outputTokens(),
+		StopReason:       acc.stopReason(),
+		ResponseMetadata: acc.responseMetadata(),
+	}
+}
+
+// mapDoneReason maps Ollama done_reason to library stop_reason.
+func mapDoneReason(doneReason string) string {
+	switch doneReason {
+	case "stop":
+		return "end_turn"
+	case "length":
+		return "max_tokens"
+	default:
+		return doneReason
+	}
+}
+
+// argsOrEmpty returns args, or an empty map if nil (tool inputs are always objects).
+func argsOrEmpty(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return map[string]interface{}{}
+	}
+	return args
+}
+
+// newToolCallID generates a tool_use_id for tool calls without one.
+// Older Ollama versions don't return IDs; tool results are matched by ID in the library.
+func newToolCallID() string {
+	return "call_" + strings.ToLower(rand.Text())
+}
@@ -0,0 +1,203 @@
+package ollama
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/haowjy/meridian-llm-go"
+)
+
+// TestConvertToOllamaMessages_ToolRoundTrip tests tool_calls/tool message conversion and images
+func TestConvertToOllamaMessages_ToolRoundTrip(t *testing.T) {
+	text := "What's the weather here?"
+	thinking := "Need the weather tool"
+	answer := "It's sunny."
+	messages := []llmprovider.Message{
+		{
+			Role: "user",
+			Blocks: []*llmprovider.Block{
+				{BlockType: llmprovider.BlockTypeText, TextContent: &text},
+				{BlockType: llmprovider.BlockTypeImage, Content: map[string]interface{}{"data": "aGVsbG8=", "mime_type": "image/png"}},
+			},
+		},
+		{
+			// Naive layout: tool_result inside the assistant turn
+			Role: "assistant",
+			Blocks: []*llmprovider.Block{
+				{BlockType: llmprovider.BlockTypeThinking, TextContent: &thinking},
+				{
+					BlockType: llmprovider.BlockTypeToolUse,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_1",
+						"tool_name":   "get_weather",
+						"input":       map[string]interface{}{"city": "Paris"},
+					},
+				},
+				{
+					BlockType: llmprovider.BlockTypeToolResult,
+					Content:   map[string]interface{}{"tool_use_id": "call_1", "content": "sunny"},
+				},
+				{BlockType: llmprovider.BlockTypeText, TextContent: &answer},
+			},
+		},
+	}
+
+	result, err := convertToOllamaMessages(messages)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result) != 4 {
+		t.Fatalf("expected 4 messages (user, assistant, tool, assistant), got %d: %+v", len(result), result)
+	}
+
+	if len(result[0].Images) != 1 || result[0].Content != text {
+		t.Errorf("expected user text with 1 image, got %+v", result[0])
+	}
+
+	call := result[1]
+	if call.Thinking != thinking || len(call.ToolCalls) != 1 || call.ToolCalls[0].Function.Arguments["city"] != "Paris" {
+		t.Errorf("unexpected assistant tool call message: %+v", call)
+	}
+
+	if result[2].Role != "tool" || result[2].ToolName != "get_weather" || result[2].Content != "sunny" {
+		t.Errorf("unexpected tool message: %+v", result[2])
+	}
+
+	if result[3].Role != "assistant" || result[3].Content != answer {
+		t.Errorf("unexpected final assistant message: %+v", result[3])
+	}
+}
+
+// TestBuildChatRequest tests options, format and think mapping
+func TestBuildChatRequest(t *testing.T) {
+	maxTokens := 256
+	enabled := true
+	level := "high"
+	system := "Be brief."
+
+	req, err := buildChatRequest(&llmprovider.GenerateRequest{
+		Model: "gpt-oss:20b",
+		Params: &llmprovider.RequestParams{
+			MaxTokens:       &maxTokens,
+			System:          &system,
+			ThinkingEnabled: &enabled,
+			ThinkingLevel:   &level,
+			ResponseFormat:  &llmprovider.ResponseFormat{Type: "json_object"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if req.Options == nil || *req.Options.NumPredict != 256 {
+		t.Errorf("expected num_predict 256, got %+v", req.Options)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != "system" {
+		t.Errorf("expected leading system message, got %+v", req.Messages)
+	}
+	if req.Think != "high" {
+		t.Errorf("expected think 'high' for gpt-oss, got %v", req.Think)
+	}
+	if req.Format != "json" {
+		t.Errorf("expected format 'json', got %v", req.Format)
+	}
+
+	plain, err := buildChatRequest(&llmprovider.GenerateRequest{
+		Model:  "qwen3",
+		Params: &llmprovider.RequestParams{ThinkingEnabled: &enabled, ThinkingLevel: &level},
+	})
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if plain.Think != true || plain.Options != nil {
+		t.Errorf("expected think=true and no options, got think=%v options=%+v", plain.Think, plain.Options)
+	}
+}
+
+// TestStreamEvents tests NDJSON parsing of thinking, text and tool calls
+func TestStreamEvents(t *testing.T) {
+	body := strings.Join([]string{
+		`{"model":"qwen3","message":{"role":"assistant","content":"","thinking":"Let me "},"done":false}`,
+		`{"model":"qwen3","message":{"role":"assistant","content":"","thinking":"check."},"done":false}`,
+		`{"model":"qwen3","message":{"role":"assistant","content":"Checking."},"done":false}`,
+		`{"model":"qwen3","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":false}`,
+		`{"model":"qwen3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":30}`,
+	}, "\n")
+
+	eventChan := make(chan llmprovider.StreamEvent, 100)
+	if err := streamEvents(context.Background(), strings.NewReader(body), "qwen3", eventChan); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	close(eventChan)
+
+	var blocks []*llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	var thinkingDeltas int
+	for event := range eventChan {
+		if event.Delta != nil && event.Delta.DeltaType == llmprovider.DeltaTypeThinking && event.Delta.TextDelta != nil {
+			thinkingDeltas++
+		}
+		if event.Block != nil {
+			blocks = append(blocks, event.Block)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if thinkingDeltas != 2 {
+		t.Errorf("expected 2 thinking deltas, got %d", thinkingDeltas)
+	}
+
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks (thinking, text, tool_use), got %d", len(blocks))
+	}
+	if blocks[0].BlockType != llmprovider.BlockTypeThinking || *blocks[0].TextContent != "Let me check." {
+		t.Errorf("unexpected thinking block: %+v", blocks[0])
+	}
+	if id, _ := blocks[2].GetToolUseID(); !strings.HasPrefix(id, "call_") {
+		t.Errorf("expected generated tool_use_id, got '%s'", id)
+	}
+
+	if metadata == nil || metadata.InputTokens != 12 || metadata.OutputTokens != 30 || metadata.StopReason != "tool_use" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+// TestStreamEvents_Error tests in-stream error objects
+func TestStreamEvents_Error(t *testing.T) {
+	eventChan := make(chan llmprovider.StreamEvent, 10)
+	err := streamEvents(context.Background(), strings.NewReader(`{"error":"model runner has unexpectedly stopped"}`+"\n"), "qwen3", eventChan)
+	if err == nil || !strings.Contains(err.Error(), "unexpectedly stopped") {
+		t.Errorf("expected stream error, got %v", err)
+	}
+}
+
+// TestNewProvider tests base URL precedence: argument, then WithBaseURL, then the default
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		opts    []llmprovider.Option
+		want    string
+	}{
+		{"", nil, DefaultBaseURL},
+		{"http://gpu-box:11434/", nil, "http://gpu-box:11434"},
+		{"", []llmprovider.Option{llmprovider.WithBaseURL("http://remote:11434")}, "http://remote:11434"},
+		{"http://gpu-box:11434", []llmprovider.Option{llmprovider.WithBaseURL("http://remote:11434")}, "http://gpu-box:11434"},
+	}
+	for _, tt := range tests {
+		provider, err := NewProvider(tt.baseURL, tt.opts...)
+		if err != nil {
+			t.Fatalf("NewProvider(%q) error = %v", tt.baseURL, err)
+		}
+		if provider.baseURL != tt.want {
+			t.Errorf("NewProvider(%q).baseURL = %q, want %q", tt.baseURL, provider.baseURL, tt.want)
+		}
+	}
+
+	if _, err := NewProvider("not a url"); err == nil {
+		t.Error("NewProvider(invalid) error = nil")
+	}
+}
@@ -0,0 +1,199 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// ChatRequest represents an Ollama /api/chat request.
+// See: https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-chat-completion
+type ChatRequest struct {
+	Model    string      `json:"model"`
+	Messages []Message   `json:"messages"`
+	Tools    []Tool      `json:"tools,omitempty"`
+	Format   interface{} `json:"format,omitempty"` // "json" or a JSON Schema object
+	Options  *Options    `json:"options,omitempty"`
+	Stream   bool        `json:"stream"`          // Ollama streams by default, so this is always sent
+	Think    interface{} `json:"think,omitempty"` // true/false, or "low"/"medium"/"high" for gpt-oss
+}
+
+// Message represents a message in the conversation.
+type Message struct {
+	Role      string     `json:"role"` // "system", "user", "assistant", "tool"
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	Images    []string   `json:"images,omitempty"` // Base64-encoded images (no data: prefix)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"` // For role:"tool" messages
+}
+
+// ToolCall represents a function call in assistant messages.
+// Ollama returns arguments as a JSON object (not a string like OpenAI).
+type ToolCall struct {
+	ID       string       `json:"id,omitempty"` // Only returned by newer Ollama versions
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall represents the function details of a tool call.
+type FunctionCall struct {
+	Index     *int                   `json:"index,omitempty"`
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// Tool represents a function tool definition.
+type Tool struct {
+	Type     string             `json:"type"` // "function"
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition represents a function tool definition.
+type FunctionDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON Schema
+}
+
+// Options are model runtime parameters (Modelfile PARAMETERs).
+type Options struct {
+	NumPredict       *int     `json:"num_predict,omitempty"` // Max tokens
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	TopK             *int     `json:"top_k,omitempty"`
+	MinP             *float64 `json:"min_p,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+}
+
+// empty reports whether no option is set.
+func (o *Options) empty() bool {
+	return o.NumPredict == nil && o.Temperature == nil && o.TopP == nil && o.TopK == nil &&
+		o.MinP == nil && o.Seed == nil && len(o.Stop) == 0 && o.RepeatPenalty == nil &&
+		o.FrequencyPenalty == nil && o.PresencePenalty == nil
+}
+
+// ChatResponse represents an Ollama /api/chat response.
+// Non-streaming responses are a single object; streaming responses are newline-delimited
+// objects of the same shape, the last one having Done=true and the token counts.
+type ChatResponse struct {
+	Model              string  `json:"model"`
+	CreatedAt          string  `json:"created_at"`
+	Message            Message `json:"message"`
+	Done               bool    `json:"done"`
+	DoneReason         string  `json:"done_reason,omitempty"`    // "stop", "length", "load", "unload"
+	TotalDuration      int64   `json:"total_duration,omitempty"` // Nanoseconds
+	LoadDuration       int64   `json:"load_duration,omitempty"`
+	PromptEvalCount    int     `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64   `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int     `json:"eval_count,omitempty"`
+	EvalDuration       int64   `json:"eval_duration,omitempty"`
+	Error              string  `json:"error,omitempty"` // Set instead of the above when a stream fails
+}
+
+// buildChatRequest constructs an Ollama request from a GenerateRequest.
+// This function is shared between GenerateResponse and StreamResponse to avoid duplication.
+func buildChatRequest(req *llmprovider.GenerateRequest) (*ChatRequest, error) {
+	// Extract params or use defaults
+	params := req.Params
+	if params == nil {
+		params = &llmprovider.RequestParams{}
+	}
+
+	messages, err := convertToOllamaMessages(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert messages: %w", err)
+	}
+
+	// System prompt is a leading system message
+	if params.System != nil && *params.System != "" {
+		messages = append([]Message{{Role: "system", Content: *params.System}}, messages...)
+	}
+
+	ollamaReq := &ChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   false,
+	}
+
+	options := &Options{
+		NumPredict:       params.MaxTokens,
+		Temperature:      params.Temperature,
+		TopP:             params.TopP,
+		TopK:             params.TopK,
+		MinP:             params.MinP,
+		Seed:             params.Seed,
+		Stop:             params.Stop,
+		RepeatPenalty:    params.RepetitionPenalty,
+		FrequencyPenalty: params.FrequencyPenalty,
+		PresencePenalty:  params.PresencePenalty,
+	}
+	// Only send options that were set so the Modelfile defaults apply
+	if !options.empty() {
+		ollamaReq.Options = options
+	}
+
+	// Tools
+	if len(params.Tools) > 0 {
+		tools, err := convertToOllamaTools(params.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tools: %w", err)
+		}
+		ollamaReq.Tools = tools
+	}
+
+	// Structured outputs
+	if params.ResponseFormat != nil {
+		switch params.ResponseFormat.Type {
+		case "json_object":
+			ollamaReq.Format = "json"
+		case "json_schema":
+			// Accept both {"name": ..., "schema": {...}} (OpenAI style) and a bare schema
+			if schema, ok := params.ResponseFormat.JSONSchema.(map[string]interface{}); ok {
+				if inner, ok := schema["schema"]; ok {
+					ollamaReq.Format = inner
+				} else {
+					ollamaReq.Format = schema
+				}
+			}
+		}
+	}
+
+	// Thinking - gpt-oss takes an effort level, other thinking models a boolean
+	if params.ThinkingEnabled != nil {
+		if *params.ThinkingEnabled && params.ThinkingLevel != nil && strings.HasPrefix(req.Model, "gpt-oss") {
+			ollamaReq.Think = *params.ThinkingLevel
+		} else {
+			ollamaReq.Think = *params.ThinkingEnabled
+		}
+	}
+
+	return ollamaReq, nil
+}
+
+// BuildChatRequestDebug builds the Ollama request payload for debugging.
+// It converts the library GenerateRequest to Ollama's ChatRequest format
+// and returns it as a map[string]interface{} for inspection.
+func BuildChatRequestDebug(req *llmprovider.GenerateRequest) (map[string]interface{}, error) {
+	chatReq, err := buildChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ollama request: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ollama request: %w", err)
+	}
+
+	return result, nil
+}
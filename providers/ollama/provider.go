@@ -0,0 +1,182 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// DefaultBaseURL is the address of a local Ollama server.
+const DefaultBaseURL = "http://localhost:11434"
+
+// Provider implements the llmprovider.Provider interface for Ollama's native /api/chat endpoint.
+//
+// Supported features:
+// - Thinking ("thinking" field → BlockTypeThinking; ThinkingEnabled/ThinkingLevel → "think")
+// - Function calling (tools / tool_calls; IDs are generated when Ollama doesn't return them)
+// - Images (base64 only)
+// - Structured outputs (ResponseFormat → "format")
+// - NDJSON streaming
+//
+// Ollama serves whatever models are pulled locally, so SupportsModel accepts any model name.
+type Provider struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewProvider creates a new Ollama provider for the server at baseURL.
+// An empty baseURL uses llmprovider.WithBaseURL if given, else DefaultBaseURL.
+// Ollama doesn't use API keys.
+func NewProvider(baseURL string, opts ...llmprovider.Option) (*Provider, error) {
+	options := llmprovider.ApplyOptions(opts...)
+
+	if baseURL == "" {
+		baseURL = options.BaseURLOr(DefaultBaseURL)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid ollama base URL %q: %w", baseURL, err)
+	}
+
+	return &Provider{
+		// Local models can take a while to load on first use
+		httpClient: options.NewHTTPClient(300 * time.Second),
+		baseURL:    baseURL,
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() llmprovider.ProviderID {
+	return llmprovider.ProviderOllama
+}
+
+// SupportsModel returns true for any non-empty model name.
+// Whether the model is pulled is only known to the server (404 → ModelError).
+func (p *Provider) SupportsModel(model string) bool {
+	return model != ""
+}
+
+// validateModel returns a ModelError if the model is not supported.
+func (p *Provider) validateModel(model string) error {
+	if !p.SupportsModel(model) {
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: p.Name().String(),
+			Reason:   "model name is required",
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	}
+	return nil
+}
+
+// GenerateResponse generates a non-streaming response from Ollama.
+func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if err := p.validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Ollama API request (shared logic)
+	ollamaReq, err := buildChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure streaming is disabled for this call
+	ollamaReq.Stream = false
+
+	httpReq, err := p.buildHTTPRequest(ctx, ollamaReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var chatResp ChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return convertFromChatResponse(&chatResp, req.Model), nil
+}
+
+// buildHTTPRequest creates a POST request to /api/chat.
+func (p *Provider) buildHTTPRequest(ctx context.Context, req *ChatRequest) (*http.Request, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return httpReq, nil
+}
+
+// handleErrorResponse parses error responses from Ollama ({"error": "..."}).
+func (p *Provider) handleErrorResponse(resp *http.Response, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	message := strings.TrimSpace(string(body))
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		message = errResp.Error
+	}
+
+	switch resp.StatusCode {
+	case 404:
+		// Model not pulled
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: p.Name().String(),
+			Reason:   message,
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	case 400:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidRequest,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidRequest,
+		}
+	default:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeProviderUnavailable,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  resp.StatusCode >= 500 || resp.StatusCode == 429,
+			Err:        llmprovider.ErrProviderUnavailable,
+		}
+	}
+}
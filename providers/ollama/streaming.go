@@ -0,0 +1,144 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// StreamResponse generates a streaming response from Ollama.
+func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	if err := p.validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Ollama API request (shared logic)
+	ollamaReq, err := buildChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enable streaming
+	ollamaReq.Stream = true
+
+	httpReq, err := p.buildHTTPRequest(ctx, ollamaReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama HTTP request failed: %w", err)
+	}
+
+	// Check for immediate errors
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	eventChan := make(chan llmprovider.StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+		defer resp.Body.Close()
+
+		if err := streamEvents(ctx, resp.Body, req.Model, eventChan); err != nil {
+			select {
+			case eventChan <- llmprovider.StreamEvent{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
+
+// streamEvents reads newline-delimited JSON chunks, emits deltas as they arrive and
+// complete blocks once the final (done=true) chunk has been read.
+func streamEvents(ctx context.Context, body io.Reader, model string, eventChan chan<- llmprovider.StreamEvent) error {
+	scanner := bufio.NewScanner(body)
+	// Tool call arguments arrive in a single line
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	acc := &responseAccumulator{}
+
+	send := func(event llmprovider.StreamEvent) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case eventChan <- event:
+			return nil
+		}
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk ChatResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("failed to parse ollama stream chunk: %w", err)
+		}
+
+		// Errors after the 200 response arrive as {"error": "..."}
+		if chunk.Error != "" {
+			return &llmprovider.ProviderError{
+				Code:      llmprovider.ErrorCodeProviderUnavailable,
+				Provider:  llmprovider.ProviderOllama.String(),
+				Message:   chunk.Error,
+				Retryable: false,
+				Err:       llmprovider.ErrProviderUnavailable,
+			}
+		}
+
+		for _, delta := range acc.addChunk(&chunk) {
+			if err := send(llmprovider.StreamEvent{Delta: delta}); err != nil {
+				return err
+			}
+		}
+
+		if chunk.Done {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading stream: %w", err)
+	}
+
+	if acc.final == nil {
+		return fmt.Errorf("ollama stream ended before the final chunk")
+	}
+
+	// Emit complete blocks (for persistence)
+	for _, block := range acc.buildBlocks() {
+		if err := send(llmprovider.StreamEvent{Block: block}); err != nil {
+			return err
+		}
+	}
+
+	responseModel := acc.model
+	if responseModel == "" {
+		responseModel = model
+	}
+
+	return send(llmprovider.StreamEvent{
+		Metadata: &llmprovider.StreamMetadata{
+			Model:            responseModel,
+			InputTokens:      acc.inputTokens(),
+			OutputTokens:     acc.outputTokens(),
+			StopReason:       acc.stopReason(),
+			ResponseMetadata: acc.responseMetadata(),
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package ollama
+
+import (
+	"fmt"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// convertToOllamaTools converts library Tool format to Ollama format.
+// Ollama has no built-in tools, so every tool is a function tool executed by our backend.
+func convertToOllamaTools(tools []llmprovider.Tool) ([]Tool, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	result := make([]Tool, 0, len(tools))
+
+	for i, tool := range tools {
+		if tool.ExecutionSide == llmprovider.ExecutionSideProvider {
+			return nil, fmt.Errorf("tool %d (%s): provider-side tools are not supported by Ollama", i, tool.Function.Name)
+		}
+
+		ollamaTool, err := convertCustomTool(&tool)
+		if err != nil {
+			return nil, fmt.Errorf("tool %d (%s): %w", i, tool.Function.Name, err)
+		}
+
+		result = append(result, ollamaTool)
+	}
+
+	return result, nil
+}
+
+// convertCustomTool converts a custom function tool to Ollama format (OpenAI-style function tool).
+func convertCustomTool(tool *llmprovider.Tool) (Tool, error) {
+	if tool.Function.Name == "" {
+		return Tool{}, fmt.Errorf("function name is required")
+	}
+
+	// Extract parameters (already in JSON Schema format)
+	parameters := tool.Function.Parameters
+	if parameters == nil {
+		parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	return Tool{
+		Type: "function",
+		Function: FunctionDefinition{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			Parameters:  parameters,
+		},
+	}, nil
+}
@@ -0,0 +1,425 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// splitMessagesAtToolResults splits assistant messages at each tool_result boundary
+// to create proper alternating assistant/tool message pairs (required by the Chat Completions API).
+//
+// Example transformation:
+//
+//	Input:  [Msg(assistant, [text, tool_use, tool_result, tool_use, tool_result])]
+//	Output: [
+//	  Msg(assistant, [text, tool_use]),
+//	  Msg(tool, [tool_result]),
+//	  Msg(assistant, [tool_use]),
+//	  Msg(tool, [tool_result])
+//	]
+//
+// tool_result messages are emitted with role:"tool" so that mergeConsecutiveSameRoleMessages
+// does not merge them back into adjacent assistant messages.
+func splitMessagesAtToolResults(messages []llmprovider.Message) []llmprovider.Message {
+	result := make([]llmprovider.Message, 0, len(messages))
+
+	for _, msg := range messages {
+		if msg.Role != "assistant" {
+			// User messages pass through unchanged
+			result = append(result, msg)
+			continue
+		}
+
+		var currentBlocks []*llmprovider.Block
+
+		for _, block := range msg.Blocks {
+			if block.BlockType == llmprovider.BlockTypeToolResult {
+				// Emit accumulated assistant blocks (if any)
+				if len(currentBlocks) > 0 {
+					result = append(result, llmprovider.Message{
+						Role:   "assistant",
+						Blocks: currentBlocks,
+					})
+					currentBlocks = nil
+				}
+
+				result = append(result, llmprovider.Message{
+					Role:   "tool",
+					Blocks: []*llmprovider.Block{block},
+				})
+			} else {
+				currentBlocks = append(currentBlocks, block)
+			}
+		}
+
+		// Emit remaining assistant blocks (if any)
+		if len(currentBlocks) > 0 {
+			result = append(result, llmprovider.Message{
+				Role:   "assistant",
+				Blocks: currentBlocks,
+			})
+		}
+	}
+
+	return result
+}
+
+// mergeConsecutiveSameRoleMessages combines consecutive messages with the same role.
+// This keeps the message array well-formed after splitting.
+func mergeConsecutiveSameRoleMessages(messages []llmprovider.Message) []llmprovider.Message {
+	if len(messages) <= 1 {
+		return messages
+	}
+
+	merged := make([]llmprovider.Message, 0, len(messages))
+	current := messages[0]
+
+	for i := 1; i < len(messages); i++ {
+		if messages[i].Role == current.Role {
+			// Copy before appending so we never mutate the caller's slice
+			blocks := make([]*llmprovider.Block, 0, len(current.Blocks)+len(messages[i].Blocks))
+			blocks = append(blocks, current.Blocks...)
+			current.Blocks = append(blocks, messages[i].Blocks...)
+		} else {
+			merged = append(merged, current)
+			current = messages[i]
+		}
+	}
+
+	merged = append(merged, current)
+
+	return merged
+}
+
+// convertToOpenAIMessages converts library messages to Chat Completions format.
+func convertToOpenAIMessages(messages []llmprovider.Message) ([]Message, error) {
+	// Phase 1: Handle cross-provider server tools by splitting messages
+	processedMessages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, llmprovider.ProviderOpenAI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process cross-provider tools: %w", err)
+	}
+
+	// Phase 2: Split assistant messages at tool_result boundaries
+	splitMessages := splitMessagesAtToolResults(processedMessages)
+
+	// Phase 3: Merge consecutive same-role messages
+	mergedMessages := mergeConsecutiveSameRoleMessages(splitMessages)
+
+	result := make([]Message, 0, len(mergedMessages))
+
+	for i, msg := range mergedMessages {
+		openaiMsgs, err := convertMessageToOpenAI(msg, i)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, openaiMsgs...)
+	}
+
+	return result, nil
+}
+
+// convertMessageToOpenAI converts a single library message to Chat Completions format.
+// May return multiple messages (tool_result blocks each become a role:"tool" message).
+func convertMessageToOpenAI(msg llmprovider.Message, msgIndex int) ([]Message, error) {
+	var result []Message
+
+	var contentParts []ContentPart
+	var toolCalls []ToolCall
+	hasImage := false
+
+	for j, block := range msg.Blocks {
+		switch block.BlockType {
+		case llmprovider.BlockTypeText:
+			if block.TextContent == nil {
+				return nil, fmt.Errorf("message %d, block %d: text block missing text_content", msgIndex, j)
+			}
+			text := *block.TextContent
+			contentParts = append(contentParts, ContentPart{Type: "text", Text: &text})
+
+		case llmprovider.BlockTypeImage:
+			if msg.Role != "user" {
+				// Chat Completions only accepts images in user messages
+				continue
+			}
+			imageURL, err := imageURLFromBlock(block)
+			if err != nil {
+				return nil, fmt.Errorf("message %d, block %d: %w", msgIndex, j, err)
+			}
+			contentParts = append(contentParts, ContentPart{Type: "image_url", ImageURL: imageURL})
+			hasImage = true
+
+		case llmprovider.BlockTypeToolUse:
+			if msg.Role != "assistant" {
+				continue
+			}
+			toolCall, err := convertToolUseToToolCall(block, msgIndex, j)
+			if err != nil {
+				return nil, err
+			}
+			toolCalls = append(toolCalls, toolCall)
+
+		case llmprovider.BlockTypeToolResult:
+			toolUseID, ok := block.GetToolUseID()
+			if !ok || toolUseID == "" {
+				return nil, fmt.Errorf("message %d, block %d: tool_result block missing tool_use_id", msgIndex, j)
+			}
+			resultContent, _ := llmprovider.ToolResultText(block)
+			result = append(result, Message{
+				Role:       "tool",
+				Content:    resultContent,
+				ToolCallID: &toolUseID,
+			})
+
+		default:
+			// Skip thinking blocks - Chat Completions has no reasoning input (reasoning is stateless)
+			// Skip web_search blocks - search results are not replayable through Chat Completions
+			// Skip document blocks - not supported by Chat Completions
+		}
+	}
+
+	if msg.Role != "user" && msg.Role != "assistant" {
+		return result, nil
+	}
+
+	openaiMsg := Message{Role: msg.Role}
+
+	if hasImage {
+		// Multimodal content must use the parts array
+		openaiMsg.Content = contentParts
+	} else if len(contentParts) > 0 {
+		texts := make([]string, 0, len(contentParts))
+		for _, part := range contentParts {
+			texts = append(texts, *part.Text)
+		}
+		openaiMsg.Content = strings.Join(texts, "\n\n")
+	}
+
+	if len(toolCalls) > 0 {
+		openaiMsg.ToolCalls = toolCalls
+	}
+
+	// Only add message if it has content or tool calls
+	if openaiMsg.Content != nil || len(openaiMsg.ToolCalls) > 0 {
+		result = append(result, openaiMsg)
+	}
+
+	return result, nil
+}
+
+// imageURLFromBlock builds an image_url part from an image block.
+// Supports Content["url"] (http(s) or data URL) and Content["data"] (base64) with Content["mime_type"].
+func imageURLFromBlock(block *llmprovider.Block) (*ImageURL, error) {
+	if url, ok := block.Content["url"].(string); ok && url != "" {
+		return &ImageURL{URL: url}, nil
+	}
+
+	if data, ok := block.Content["data"].(string); ok && data != "" {
+		mimeType, _ := block.Content["mime_type"].(string)
+		if mimeType == "" {
+			mimeType = "image/png"
+		}
+		// Validate base64 early so the API doesn't reject the whole request with a vague error
+		if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+			return nil, fmt.Errorf("image block has invalid base64 data: %w", err)
+		}
+		return &ImageURL{URL: "data:" + mimeType + ";base64," + data}, nil
+	}
+
+	return nil, fmt.Errorf("image block missing url or data")
+}
+
+// convertToolUseToToolCall converts a tool_use block to OpenAI ToolCall format.
+func convertToolUseToToolCall(block *llmprovider.Block, msgIndex, blockIndex int) (ToolCall, error) {
+	if block.Content == nil {
+		return ToolCall{}, fmt.Errorf("message %d, block %d: tool_use block missing content", msgIndex, blockIndex)
+	}
+
+	toolUseID, ok := block.Content["tool_use_id"].(string)
+	if !ok || toolUseID == "" {
+		return ToolCall{}, fmt.Errorf("message %d, block %d: tool_use block missing tool_use_id", msgIndex, blockIndex)
+	}
+
+	toolName, ok := block.Content["tool_name"].(string)
+	if !ok || toolName == "" {
+		return ToolCall{}, fmt.Errorf("message %d, block %d: tool_use block missing tool_name", msgIndex, blockIndex)
+	}
+
+	input, ok := block.Content["input"]
+	if !ok {
+		return ToolCall{}, fmt.Errorf("message %d, block %d: tool_use block missing input", msgIndex, blockIndex)
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return ToolCall{}, fmt.Errorf("message %d, block %d: failed to marshal tool input: %w", msgIndex, blockIndex, err)
+	}
+
+	return ToolCall{
+		ID:   toolUseID,
+		Type: "function",
+		Function: FunctionCall{
+			Name:      toolName,
+			Arguments: string(inputJSON),
+		},
+	}, nil
+}
+
+// convertFromChatCompletionResponse converts an OpenAI response to library format.
+func convertFromChatCompletionResponse(resp *ChatCompletionResponse) (*llmprovider.GenerateResponse, error) {
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	choice := resp.Choices[0]
+	blocks := make([]*llmprovider.Block, 0)
+	providerIDStr := llmprovider.ProviderOpenAI.String()
+
+	// Text content (with url_citation annotations from search models)
+	if contentStr, ok := choice.Message.Content.(string); ok && contentStr != "" {
+		blocks = append(blocks, &llmprovider.Block{
+			BlockType:   llmprovider.BlockTypeText,
+			Sequence:    len(blocks),
+			TextContent: &contentStr,
+			Provider:    &providerIDStr,
+			Citations:   convertAnnotationsToCitations(choice.Message.Annotations),
+		})
+	}
+
+	// Refusals are surfaced as text so the caller sees why the model declined
+	if choice.Message.Refusal != nil && *choice.Message.Refusal != "" {
+		refusal := *choice.Message.Refusal
+		blocks = append(blocks, &llmprovider.Block{
+			BlockType:   llmprovider.BlockTypeText,
+			Sequence:    len(blocks),
+			TextContent: &refusal,
+			Provider:    &providerIDStr,
+		})
+	}
+
+	// Convert tool_calls to tool_use blocks
+	for i, toolCall := range choice.Message.ToolCalls {
+		block, err := convertToolCallToBlock(toolCall, len(blocks))
+		if err != nil {
+			return nil, fmt.Errorf("tool call %d: %w", i, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	stopReason := ""
+	if choice.FinishReason != nil {
+		stopReason = mapFinishReason(*choice.FinishReason)
+	}
+
+	return &llmprovider.GenerateResponse{
+		Blocks:           blocks,
+		Model:            resp.Model,
+		InputTokens:      resp.Usage.PromptTokens,
+		OutputTokens:     resp.Usage.CompletionTokens,
+		StopReason:       stopReason,
+		ResponseMetadata: buildResponseMetadata(resp.ID, resp.SystemFingerprint, &resp.Usage, choice.LogProbs, choice.Message.Refusal),
+	}, nil
+}
+
+// buildResponseMetadata collects provider-specific response data shared by both paths.
+func buildResponseMetadata(responseID, systemFingerprint string, usage *Usage, logProbs *LogProbs, refusal *string) map[string]interface{} {
+	metadata := make(map[string]interface{})
+
+	if responseID != "" {
+		metadata["response_id"] = responseID
+	}
+	if systemFingerprint != "" {
+		metadata["system_fingerprint"] = systemFingerprint
+	}
+
+	if usage != nil {
+		metadata["total_tokens"] = usage.TotalTokens
+		if usage.PromptTokensDetails != nil && usage.PromptTokensDetails.CachedTokens > 0 {
+			metadata["cache_read_input_tokens"] = usage.PromptTokensDetails.CachedTokens
+		}
+		if usage.CompletionTokensDetails != nil && usage.CompletionTokensDetails.ReasoningTokens > 0 {
+			metadata["reasoning_tokens"] = usage.CompletionTokensDetails.ReasoningTokens
+		}
+	}
+
+	if logProbs != nil && (len(logProbs.Content) > 0 || len(logProbs.Refusal) > 0) {
+		metadata["logprobs"] = logProbs
+	}
+
+	if refusal != nil && *refusal != "" {
+		metadata["refusal"] = *refusal
+	}
+
+	return metadata
+}
+
+// convertToolCallToBlock converts an OpenAI ToolCall to a library Block.
+func convertToolCallToBlock(toolCall ToolCall, sequence int) (*llmprovider.Block, error) {
+	input := make(map[string]interface{})
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &input); err != nil {
+			return nil, fmt.Errorf("invalid tool call arguments %q: %w", toolCall.Function.Arguments, err)
+		}
+	}
+
+	// Function tools are always executed by our backend
+	executionSide := llmprovider.ExecutionSideServer
+	providerIDStr := llmprovider.ProviderOpenAI.String()
+
+	return &llmprovider.Block{
+		BlockType: llmprovider.BlockTypeToolUse,
+		Sequence:  sequence,
+		Content: map[string]interface{}{
+			"tool_use_id": toolCall.ID,
+			"tool_name":   toolCall.Function.Name,
+			"input":       input,
+		},
+		ExecutionSide: &executionSide,
+		Provider:      &providerIDStr,
+	}, nil
+}
+
+// convertAnnotationsToCitations converts url_citation annotations to library Citation format.
+// Returns nil if there are no annotations.
+func convertAnnotationsToCitations(annotations []Annotation) []llmprovider.Citation {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	citations := make([]llmprovider.Citation, 0, len(annotations))
+	for _, annotation := range annotations {
+		if annotation.URLCitation == nil {
+			continue
+		}
+		startIndex := annotation.URLCitation.StartIndex
+		endIndex := annotation.URLCitation.EndIndex
+		citations = append(citations, llmprovider.Citation{
+			Type:       "url_citation",
+			URL:        annotation.URLCitation.URL,
+			Title:      annotation.URLCitation.Title,
+			StartIndex: &startIndex,
+			EndIndex:   &endIndex,
+		})
+	}
+
+	return citations
+}
+
+// mapFinishReason maps OpenAI finish_reason to library stop_reason.
+func mapFinishReason(finishReason string) string {
+	switch finishReason {
+	case "stop":
+		return "end_turn"
+	case "length":
+		return "max_tokens"
+	case "tool_calls", "function_call":
+		return "tool_use"
+	case "content_filter":
+		return "refusal"
+	default:
+		return finishReason
+	}
+}
@@ -0,0 +1,485 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/haowjy/meridian-llm-go"
+)
+
+// TestConvertToOpenAIMessages_SimpleText tests basic text message conversion
+func TestConvertToOpenAIMessages_SimpleText(t *testing.T) {
+	text := "Hello, world!"
+	messages := []llmprovider.Message{
+		{
+			Role: "user",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType:   llmprovider.BlockTypeText,
+					Sequence:    0,
+					TextContent: &text,
+				},
+			},
+		},
+	}
+
+	result, err := convertToOpenAIMessages(messages)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(result))
+	}
+
+	if result[0].Role != "user" {
+		t.Errorf("expected role 'user', got '%s'", result[0].Role)
+	}
+
+	if content, ok := result[0].Content.(string); !ok || content != text {
+		t.Errorf("expected content '%s', got %v", text, result[0].Content)
+	}
+}
+
+// TestConvertToOpenAIMessages_ToolRoundTrip tests tool_use → tool_calls and tool_result → role:"tool"
+func TestConvertToOpenAIMessages_ToolRoundTrip(t *testing.T) {
+	messages := []llmprovider.Message{
+		{
+			Role: "assistant",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolUse,
+					Sequence:  0,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_123",
+						"tool_name":   "get_weather",
+						"input": map[string]interface{}{
+							"city": "Paris",
+						},
+					},
+				},
+			},
+		},
+		{
+			Role: "user",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolResult,
+					Sequence:  0,
+					Content: map[string]interface{}{
+						"tool_use_id": "call_123",
+						"content":     "sunny, 22C",
+					},
+				},
+			},
+		},
+	}
+
+	result, err := convertToOpenAIMessages(messages)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(result))
+	}
+
+	if len(result[0].ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(result[0].ToolCalls))
+	}
+	toolCall := result[0].ToolCalls[0]
+	if toolCall.ID != "call_123" || toolCall.Function.Name != "get_weather" {
+		t.Errorf("unexpected tool call: %+v", toolCall)
+	}
+	if toolCall.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("expected arguments '{\"city\":\"Paris\"}', got '%s'", toolCall.Function.Arguments)
+	}
+
+	if result[1].Role != "tool" {
+		t.Errorf("expected role 'tool', got '%s'", result[1].Role)
+	}
+	if result[1].ToolCallID == nil || *result[1].ToolCallID != "call_123" {
+		t.Errorf("expected tool_call_id 'call_123', got %v", result[1].ToolCallID)
+	}
+	if content, ok := result[1].Content.(string); !ok || content != "sunny, 22C" {
+		t.Errorf("expected tool content 'sunny, 22C', got %v", result[1].Content)
+	}
+}
+
+// TestConvertToOpenAIMessages_MissingToolUseID tests error handling for missing tool_use_id
+func TestConvertToOpenAIMessages_MissingToolUseID(t *testing.T) {
+	messages := []llmprovider.Message{
+		{
+			Role: "assistant",
+			Blocks: []*llmprovider.Block{
+				{
+					BlockType: llmprovider.BlockTypeToolUse,
+					Sequence:  0,
+					Content: map[string]interface{}{
+						// Missing tool_use_id
+						"tool_name": "search",
+					},
+				},
+			},
+		},
+	}
+
+	_, err := convertToOpenAIMessages(messages)
+	if err == nil {
+		t.Error("expected error for missing tool_use_id, got nil")
+	}
+}
+
+// TestBuildChatCompletionRequest tests parameter mapping
+func TestBuildChatCompletionRequest(t *testing.T) {
+	text := "Hi"
+	system := "Be brief"
+	maxTokens := 256
+	thinking := true
+	level := "high"
+
+	req := &llmprovider.GenerateRequest{
+		Model: "o4-mini",
+		Messages: []llmprovider.Message{
+			{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &text}}},
+		},
+		Params: &llmprovider.RequestParams{
+			System:          &system,
+			MaxTokens:       &maxTokens,
+			ThinkingEnabled: &thinking,
+			ThinkingLevel:   &level,
+		},
+	}
+
+	openaiReq, err := buildChatCompletionRequest(req)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(openaiReq.Messages) != 2 || openaiReq.Messages[0].Role != "system" {
+		t.Fatalf("expected system message first, got %+v", openaiReq.Messages)
+	}
+	if openaiReq.MaxCompletionTokens == nil || *openaiReq.MaxCompletionTokens != 256 {
+		t.Errorf("expected max_completion_tokens 256, got %v", openaiReq.MaxCompletionTokens)
+	}
+	if openaiReq.ReasoningEffort == nil || *openaiReq.ReasoningEffort != "high" {
+		t.Errorf("expected reasoning_effort 'high', got %v", openaiReq.ReasoningEffort)
+	}
+}
+
+// TestConvertFromChatCompletionResponse tests response conversion
+func TestConvertFromChatCompletionResponse(t *testing.T) {
+	finishReason := "tool_calls"
+	content := "Let me check"
+
+	resp := &ChatCompletionResponse{
+		ID:    "chatcmpl-123",
+		Model: "gpt-4o",
+		Choices: []Choice{
+			{
+				Message: Message{
+					Content: content,
+					ToolCalls: []ToolCall{
+						{
+							ID:       "call_1",
+							Type:     "function",
+							Function: FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+						},
+					},
+				},
+				FinishReason: &finishReason,
+			},
+		},
+		Usage: Usage{
+			PromptTokens:     10,
+			CompletionTokens: 15,
+			TotalTokens:      25,
+		},
+	}
+
+	result, err := convertFromChatCompletionResponse(resp)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(result.Blocks))
+	}
+
+	if result.Blocks[0].TextContent == nil || *result.Blocks[0].TextContent != content {
+		t.Errorf("expected text content '%s', got %v", content, result.Blocks[0].TextContent)
+	}
+
+	toolBlock := result.Blocks[1]
+	if toolBlock.BlockType != llmprovider.BlockTypeToolUse {
+		t.Errorf("expected BlockTypeToolUse, got %s", toolBlock.BlockType)
+	}
+	if id, _ := toolBlock.GetToolUseID(); id != "call_1" {
+		t.Errorf("expected tool_use_id 'call_1', got '%s'", id)
+	}
+
+	if result.InputTokens != 10 || result.OutputTokens != 15 {
+		t.Errorf("expected tokens 10/15, got %d/%d", result.InputTokens, result.OutputTokens)
+	}
+
+	if result.StopReason != "tool_use" {
+		t.Errorf("expected StopReason 'tool_use', got '%s'", result.StopReason)
+	}
+
+	if result.ResponseMetadata["response_id"] != "chatcmpl-123" {
+		t.Errorf("expected response_id 'chatcmpl-123', got %v", result.ResponseMetadata["response_id"])
+	}
+}
+
+// TestStreamEvents tests SSE parsing, delta emission and final block assembly
+func TestStreamEvents(t *testing.T) {
+	body := strings.Join([]string{
+		`data: {"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"},"finish_reason":null}]}`,
+		``,
+		`data: {"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":null}]}`,
+		``,
+		`data: {"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]},"finish_reason":null}]}`,
+		``,
+		`data: {"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		``,
+		`data: {"id":"chatcmpl-1","model":"gpt-4o","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+		``,
+		`data: [DONE]`,
+		``,
+	}, "\n")
+
+	eventChan := make(chan llmprovider.StreamEvent, 100)
+	if err := streamEvents(context.Background(), strings.NewReader(body), eventChan); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	close(eventChan)
+
+	var text strings.Builder
+	var blocks []*llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	for event := range eventChan {
+		switch {
+		case event.Delta != nil && event.Delta.TextDelta != nil:
+			text.WriteString(*event.Delta.TextDelta)
+		case event.Block != nil:
+			blocks = append(blocks, event.Block)
+		case event.Metadata != nil:
+			metadata = event.Metadata
+		}
+	}
+
+	if text.String() != "Hello" {
+		t.Errorf("expected streamed text 'Hello', got '%s'", text.String())
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+	if blocks[1].BlockType != llmprovider.BlockTypeToolUse || blocks[1].Sequence != 1 {
+		t.Errorf("expected tool_use block at sequence 1, got %s at %d", blocks[1].BlockType, blocks[1].Sequence)
+	}
+	input, ok := blocks[1].Content["input"].(map[string]interface{})
+	if !ok || input["city"] != "Paris" {
+		t.Errorf("expected input city 'Paris', got %v", blocks[1].Content["input"])
+	}
+
+	if metadata == nil {
+		t.Fatal("expected metadata event")
+	}
+	if metadata.StopReason != "tool_use" {
+		t.Errorf("expected StopReason 'tool_use', got '%s'", metadata.StopReason)
+	}
+	if metadata.InputTokens != 5 || metadata.OutputTokens != 7 {
+		t.Errorf("expected tokens 5/7, got %d/%d", metadata.InputTokens, metadata.OutputTokens)
+	}
+}
+
+// TestMapFinishReason tests finish_reason mapping
+func TestMapFinishReason(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"stop", "end_turn"},
+		{"length", "max_tokens"},
+		{"tool_calls", "tool_use"},
+		{"content_filter", "refusal"},
+		{"unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := mapFinishReason(tt.input)
+			if result != tt.expected {
+				t.Errorf("mapFinishReason(%s) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestResponsesReasoningRoundTrip tests that reasoning items replay verbatim from ProviderData
+func TestResponsesReasoningRoundTrip(t *testing.T) {
+	encrypted := "gAAAA-encrypted"
+	resp := &ResponsesResponse{
+		ID:     "resp_1",
+		Model:  "o4-mini",
+		Status: "completed",
+		Output: []OutputItem{
+			{
+				Type:             "reasoning",
+				ID:               "rs_1",
+				Summary:          []ReasoningSummary{{Type: "summary_text", Text: "Thinking about weather"}},
+				EncryptedContent: &encrypted,
+			},
+			{
+				Type:      "function_call",
+				ID:        "fc_1",
+				CallID:    "call_1",
+				Name:      "get_weather",
+				Arguments: `{"city":"Paris"}`,
+			},
+		},
+	}
+
+	result, err := convertFromResponsesResponse(resp)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(result.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(result.Blocks))
+	}
+	if result.Blocks[0].BlockType != llmprovider.BlockTypeThinking {
+		t.Errorf("expected BlockTypeThinking, got %s", result.Blocks[0].BlockType)
+	}
+	if result.StopReason != "tool_use" {
+		t.Errorf("expected StopReason 'tool_use', got '%s'", result.StopReason)
+	}
+
+	// Replay the assistant turn followed by the tool result
+	output := "sunny"
+	input, err := convertToResponsesInput([]llmprovider.Message{
+		{Role: "assistant", Blocks: result.Blocks},
+		{Role: "user", Blocks: []*llmprovider.Block{
+			{BlockType: llmprovider.BlockTypeToolResult, TextContent: &output, Content: map[string]interface{}{"tool_use_id": "call_1"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if len(input) != 3 {
+		t.Fatalf("expected 3 input items, got %d", len(input))
+	}
+
+	raw, ok := input[0].(json.RawMessage)
+	if !ok {
+		t.Fatalf("expected reasoning item replayed as raw JSON, got %T", input[0])
+	}
+	var reasoning map[string]interface{}
+	if err := json.Unmarshal(raw, &reasoning); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if reasoning["type"] != "reasoning" || reasoning["id"] != "rs_1" || reasoning["encrypted_content"] != encrypted {
+		t.Errorf("unexpected reasoning item: %v", reasoning)
+	}
+
+	call, ok := input[1].(FunctionCallItem)
+	if !ok || call.ID != "fc_1" || call.CallID != "call_1" {
+		t.Errorf("unexpected function_call item: %+v", input[1])
+	}
+
+	callOutput, ok := input[2].(FunctionCallOutputItem)
+	if !ok || callOutput.CallID != "call_1" || callOutput.Output != "sunny" {
+		t.Errorf("unexpected function_call_output item: %+v", input[2])
+	}
+}
+
+// TestStreamResponsesEvents tests Responses API event handling and block indexing
+func TestStreamResponsesEvents(t *testing.T) {
+	body := strings.Join([]string{
+		`event: response.output_item.added`,
+		`data: {"type":"response.output_item.added","output_index":0,"item":{"type":"reasoning","id":"rs_1","summary":[]}}`,
+		``,
+		`data: {"type":"response.reasoning_summary_text.delta","output_index":0,"summary_index":0,"delta":"Hmm"}`,
+		``,
+		`data: {"type":"response.output_item.done","output_index":0,"item":{"type":"reasoning","id":"rs_1","summary":[{"type":"summary_text","text":"Hmm"}],"encrypted_content":"enc"}}`,
+		``,
+		`data: {"type":"response.output_item.added","output_index":1,"item":{"type":"message","id":"msg_1","role":"assistant","content":[]}}`,
+		``,
+		`data: {"type":"response.content_part.added","output_index":1,"content_index":0,"part":{"type":"output_text","text":""}}`,
+		``,
+		`data: {"type":"response.output_text.delta","output_index":1,"content_index":0,"delta":"Hi"}`,
+		``,
+		`data: {"type":"response.output_item.done","output_index":1,"item":{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Hi","annotations":[]}]}}`,
+		``,
+		`data: {"type":"response.completed","response":{"id":"resp_1","model":"o4-mini","status":"completed","output":[],"usage":{"input_tokens":3,"output_tokens":4,"total_tokens":7}}}`,
+		``,
+	}, "\n")
+
+	eventChan := make(chan llmprovider.StreamEvent, 100)
+	if err := streamResponsesEvents(context.Background(), strings.NewReader(body), eventChan); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	close(eventChan)
+
+	var blocks []*llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	for event := range eventChan {
+		if event.Block != nil {
+			blocks = append(blocks, event.Block)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+	if blocks[0].BlockType != llmprovider.BlockTypeThinking || blocks[0].Sequence != 0 {
+		t.Errorf("expected thinking block at sequence 0, got %s at %d", blocks[0].BlockType, blocks[0].Sequence)
+	}
+	if !blocks[0].HasProviderData() {
+		t.Error("expected reasoning item in ProviderData")
+	}
+	if blocks[1].BlockType != llmprovider.BlockTypeText || blocks[1].Sequence != 1 {
+		t.Errorf("expected text block at sequence 1, got %s at %d", blocks[1].BlockType, blocks[1].Sequence)
+	}
+
+	if metadata == nil || metadata.StopReason != "end_turn" || metadata.OutputTokens != 4 {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+// TestRegistry_ChatAndResponses tests that both OpenAI providers can share a Registry
+func TestRegistry_ChatAndResponses(t *testing.T) {
+	chat, err := NewProvider("test-key")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	responses, err := NewResponsesProvider("test-key")
+	if err != nil {
+		t.Fatalf("NewResponsesProvider() error = %v", err)
+	}
+
+	registry := llmprovider.NewRegistry()
+	if err := registry.Register(chat); err != nil {
+		t.Fatalf("Register(chat) error = %v", err)
+	}
+	if err := registry.Register(responses); err != nil {
+		t.Fatalf("Register(responses) error = %v", err)
+	}
+	if err := registry.RegisterAlias("o4-mini-responses", llmprovider.ProviderOpenAIResponses, "o4-mini"); err != nil {
+		t.Fatalf("RegisterAlias() error = %v", err)
+	}
+
+	if provider, model, err := registry.Resolve("o4-mini-responses"); err != nil || provider != responses || model != "o4-mini" {
+		t.Errorf("Resolve(alias) = %v, %q, %v; want the Responses provider", provider, model, err)
+	}
+	if provider, _, err := registry.Resolve("gpt-4o"); err != nil || provider != chat {
+		t.Errorf("Resolve(gpt-4o) = %v, %v; want the Chat Completions provider", provider, err)
+	}
+}
@@ -0,0 +1,134 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// client holds the HTTP configuration shared by the Chat Completions and Responses providers.
+type client struct {
+	apiKey     string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// newClient creates a client for the OpenAI API, applying construction options over the defaults.
+func newClient(apiKey string, opts []llmprovider.Option) *client {
+	options := llmprovider.ApplyOptions(opts...)
+	return &client{
+		apiKey:     apiKey,
+		httpClient: options.NewHTTPClient(120 * time.Second),
+		baseURL:    options.BaseURLOr("https://api.openai.com/v1"),
+	}
+}
+
+// buildHTTPRequest creates a JSON POST request for the OpenAI API.
+func (c *client) buildHTTPRequest(ctx context.Context, path string, payload interface{}) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return httpReq, nil
+}
+
+// errorResponse is the error envelope returned by the OpenAI API.
+type errorResponse struct {
+	Error struct {
+		Message string      `json:"message"`
+		Type    string      `json:"type"`
+		Param   *string     `json:"param"`
+		Code    interface{} `json:"code"` // string or null
+	} `json:"error"`
+}
+
+// handleErrorResponse parses error responses from OpenAI.
+func (c *client) handleErrorResponse(resp *http.Response, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Message == "" {
+		// Fallback to plain text error
+		return llmprovider.NewProviderError(llmprovider.ProviderOpenAI.String(), resp.StatusCode, strings.TrimSpace(string(body)), llmprovider.ErrProviderUnavailable)
+	}
+
+	message := errResp.Error.Message
+	code, _ := errResp.Error.Code.(string)
+
+	switch resp.StatusCode {
+	case 401, 403:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidAPIKey,
+			Provider:   llmprovider.ProviderOpenAI.String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidAPIKey,
+		}
+	case 429:
+		// insufficient_quota is also reported as 429 but retrying won't help
+		if code == "insufficient_quota" {
+			return &llmprovider.ProviderError{
+				Code:       llmprovider.ErrorCodeProviderUnavailable,
+				Provider:   llmprovider.ProviderOpenAI.String(),
+				StatusCode: resp.StatusCode,
+				Message:    "insufficient quota: " + message,
+				Retryable:  false,
+				Err:        llmprovider.ErrProviderUnavailable,
+			}
+		}
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeRateLimited,
+			Provider:   llmprovider.ProviderOpenAI.String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  true,
+			Err:        llmprovider.ErrRateLimited,
+		}
+	case 404:
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: llmprovider.ProviderOpenAI.String(),
+			Reason:   message,
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	case 400, 422:
+		if errResp.Error.Param != nil && *errResp.Error.Param != "" {
+			message = fmt.Sprintf("%s (param: %s)", message, *errResp.Error.Param)
+		}
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidRequest,
+			Provider:   llmprovider.ProviderOpenAI.String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidRequest,
+		}
+	default:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeProviderUnavailable,
+			Provider:   llmprovider.ProviderOpenAI.String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  resp.StatusCode >= 500,
+			Err:        llmprovider.ErrProviderUnavailable,
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// ChatCompletionRequest represents an OpenAI Chat Completions request.
+// See: https://platform.openai.com/docs/api-reference/chat/create
+type ChatCompletionRequest struct {
+	Model               string             `json:"model"`
+	Messages            []Message          `json:"messages"`
+	MaxCompletionTokens *int               `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64           `json:"temperature,omitempty"`
+	TopP                *float64           `json:"top_p,omitempty"`
+	Stop                []string           `json:"stop,omitempty"`
+	Seed                *int               `json:"seed,omitempty"`
+	FrequencyPenalty    *float64           `json:"frequency_penalty,omitempty"`
+	PresencePenalty     *float64           `json:"presence_penalty,omitempty"`
+	LogitBias           map[string]float64 `json:"logit_bias,omitempty"`
+	LogProbs            *bool              `json:"logprobs,omitempty"`
+	TopLogProbs         *int               `json:"top_logprobs,omitempty"`
+	ResponseFormat      *ResponseFormat    `json:"response_format,omitempty"`
+	Tools               []Tool             `json:"tools,omitempty"`
+	ToolChoice          interface{}        `json:"tool_choice,omitempty"` // "auto", "none", "required", or {"type": "function", "function": {"name": "..."}}
+	ParallelToolCalls   *bool              `json:"parallel_tool_calls,omitempty"`
+	ReasoningEffort     *string            `json:"reasoning_effort,omitempty"`   // o-series / gpt-5 reasoning models: "minimal", "low", "medium", "high"
+	WebSearchOptions    *WebSearchOptions  `json:"web_search_options,omitempty"` // search-preview models only
+	Stream              bool               `json:"stream"`
+	StreamOptions       *StreamOptions     `json:"stream_options,omitempty"`
+}
+
+// StreamOptions configures streaming behavior.
+type StreamOptions struct {
+	// IncludeUsage requests a final chunk containing token usage
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// WebSearchOptions enables built-in web search for search-preview models.
+type WebSearchOptions struct {
+	SearchContextSize *string `json:"search_context_size,omitempty"` // "low", "medium", "high"
+}
+
+// ResponseFormat specifies the structured output format.
+type ResponseFormat struct {
+	Type       string      `json:"type"`                  // "text", "json_object", "json_schema"
+	JSONSchema interface{} `json:"json_schema,omitempty"` // {"name": "...", "schema": {...}, "strict": true}
+}
+
+// Message represents a message in the conversation.
+type Message struct {
+	Role        string       `json:"role"`              // "system", "developer", "user", "assistant", "tool"
+	Content     interface{}  `json:"content,omitempty"` // string or []ContentPart
+	Name        *string      `json:"name,omitempty"`
+	ToolCalls   []ToolCall   `json:"tool_calls,omitempty"`
+	ToolCallID  *string      `json:"tool_call_id,omitempty"` // For role:"tool" messages
+	Refusal     *string      `json:"refusal,omitempty"`      // Assistant refusal message (responses only)
+	Annotations []Annotation `json:"annotations,omitempty"`  // url_citation annotations (search models)
+}
+
+// ContentPart represents a part of multimodal content.
+type ContentPart struct {
+	Type     string    `json:"type"` // "text", "image_url"
+	Text     *string   `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL represents an image URL (or data URL) in content.
+type ImageURL struct {
+	URL    string  `json:"url"`
+	Detail *string `json:"detail,omitempty"` // "auto", "low", "high"
+}
+
+// ToolCall represents a function call in assistant messages.
+type ToolCall struct {
+	Index    *int         `json:"index,omitempty"` // Streaming only - index of this tool call in the array
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"` // "function"
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall represents the function details of a tool call.
+type FunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"` // JSON string
+}
+
+// Annotation represents a citation attached to assistant content.
+type Annotation struct {
+	Type        string       `json:"type"` // "url_citation"
+	URLCitation *URLCitation `json:"url_citation,omitempty"`
+}
+
+// URLCitation represents a web citation returned by search models.
+type URLCitation struct {
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+}
+
+// Tool represents a function tool definition.
+type Tool struct {
+	Type     string             `json:"type"` // "function"
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition represents a function tool definition.
+type FunctionDefinition struct {
+	Name        string                 `json:"name"`
+	Description *string                `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON Schema
+}
+
+// ChatCompletionResponse represents a Chat Completions response (non-streaming).
+type ChatCompletionResponse struct {
+	ID                string   `json:"id"`
+	Object            string   `json:"object"` // "chat.completion"
+	Created           int64    `json:"created"`
+	Model             string   `json:"model"`
+	SystemFingerprint string   `json:"system_fingerprint,omitempty"`
+	Choices           []Choice `json:"choices"`
+	Usage             Usage    `json:"usage"`
+}
+
+// Choice represents a completion choice in the response.
+type Choice struct {
+	Index        int       `json:"index"`
+	Message      Message   `json:"message"`
+	LogProbs     *LogProbs `json:"logprobs,omitempty"`
+	FinishReason *string   `json:"finish_reason"` // "stop", "length", "tool_calls", "content_filter"
+}
+
+// LogProbs contains per-token log probabilities (when requested).
+type LogProbs struct {
+	Content []TokenLogProb `json:"content,omitempty"`
+	Refusal []TokenLogProb `json:"refusal,omitempty"`
+}
+
+// TokenLogProb is the log probability information for a single token.
+type TokenLogProb struct {
+	Token       string       `json:"token"`
+	LogProb     float64      `json:"logprob"`
+	Bytes       []int        `json:"bytes,omitempty"`
+	TopLogProbs []TopLogProb `json:"top_logprobs,omitempty"`
+}
+
+// TopLogProb is one of the most likely alternatives for a token position.
+type TopLogProb struct {
+	Token   string  `json:"token"`
+	LogProb float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
+// Usage represents token usage in the response.
+type Usage struct {
+	PromptTokens            int                      `json:"prompt_tokens"`
+	CompletionTokens        int                      `json:"completion_tokens"`
+	TotalTokens             int                      `json:"total_tokens"`
+	PromptTokensDetails     *PromptTokensDetails     `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails breaks down prompt token usage.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CompletionTokensDetails breaks down completion token usage.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// buildChatCompletionRequest constructs an OpenAI API request from a GenerateRequest.
+// This function is shared between GenerateResponse and StreamResponse to avoid duplication.
+func buildChatCompletionRequest(req *llmprovider.GenerateRequest) (*ChatCompletionRequest, error) {
+	// Extract params or use defaults
+	params := req.Params
+	if params == nil {
+		params = &llmprovider.RequestParams{}
+	}
+
+	// Convert library messages to OpenAI format
+	messages, err := convertToOpenAIMessages(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert messages: %w", err)
+	}
+
+	// System prompt goes first in the messages array
+	if params.System != nil && *params.System != "" {
+		messages = append([]Message{{Role: "system", Content: *params.System}}, messages...)
+	}
+
+	openaiReq := &ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   false,
+	}
+
+	// MaxTokens (max_tokens is deprecated and rejected by reasoning models)
+	if params.MaxTokens != nil {
+		openaiReq.MaxCompletionTokens = params.MaxTokens
+	}
+
+	// Sampling parameters
+	openaiReq.Temperature = params.Temperature
+	openaiReq.TopP = params.TopP
+	openaiReq.Seed = params.Seed
+	openaiReq.FrequencyPenalty = params.FrequencyPenalty
+	openaiReq.PresencePenalty = params.PresencePenalty
+
+	// Stop sequences
+	if len(params.Stop) > 0 {
+		openaiReq.Stop = params.Stop
+	}
+
+	// Logit bias
+	if len(params.LogitBias) > 0 {
+		openaiReq.LogitBias = params.LogitBias
+	}
+
+	// Log probabilities
+	openaiReq.LogProbs = params.LogProbs
+	if params.TopLogProbs != nil {
+		openaiReq.TopLogProbs = params.TopLogProbs
+		// top_logprobs requires logprobs=true
+		if openaiReq.LogProbs == nil {
+			enabled := true
+			openaiReq.LogProbs = &enabled
+		}
+	}
+
+	// Structured outputs
+	if params.ResponseFormat != nil {
+		openaiReq.ResponseFormat = &ResponseFormat{
+			Type:       params.ResponseFormat.Type,
+			JSONSchema: params.ResponseFormat.JSONSchema,
+		}
+	}
+
+	// Tools - provider-side search is not a function; it enables web_search_options
+	if len(params.Tools) > 0 {
+		openaiTools, webSearch, err := convertToOpenAITools(params.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tools: %w", err)
+		}
+		openaiReq.Tools = openaiTools
+		openaiReq.WebSearchOptions = webSearch
+	}
+
+	// Tool choice (only meaningful when function tools are present)
+	if params.ToolChoice != nil && len(openaiReq.Tools) > 0 {
+		toolChoice, err := convertToolChoice(params.ToolChoice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool choice: %w", err)
+		}
+		openaiReq.ToolChoice = toolChoice
+	}
+
+	// Parallel tool calls (only valid when tools are present)
+	if params.ParallelToolCalls != nil && len(openaiReq.Tools) > 0 {
+		openaiReq.ParallelToolCalls = params.ParallelToolCalls
+	}
+
+	// Reasoning effort - map ThinkingEnabled/ThinkingLevel to reasoning_effort
+	// Only reasoning models (o-series, gpt-5) accept this parameter
+	if params.ThinkingEnabled != nil && *params.ThinkingEnabled && params.ThinkingLevel != nil {
+		effort := *params.ThinkingLevel // "low", "medium", "high" map directly
+		openaiReq.ReasoningEffort = &effort
+	}
+
+	return openaiReq, nil
+}
+
+// BuildChatCompletionRequestDebug builds the OpenAI request payload for debugging.
+// It converts the library GenerateRequest to OpenAI's ChatCompletionRequest format
+// and returns it as a map[string]interface{} for inspection. No network calls are made.
+func BuildChatCompletionRequestDebug(req *llmprovider.GenerateRequest) (map[string]interface{}, error) {
+	chatReq, err := buildChatCompletionRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openai request: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal openai request: %w", err)
+	}
+
+	return result, nil
+}
+
+// convertToolChoice converts library tool choice to OpenAI format.
+func convertToolChoice(choice interface{}) (interface{}, error) {
+	tc, ok := choice.(*llmprovider.ToolChoice)
+	if !ok {
+		return nil, fmt.Errorf("tool_choice must be *llmprovider.ToolChoice")
+	}
+
+	// Typed nil pointer means provider default
+	if tc == nil {
+		return nil, nil
+	}
+
+	if err := tc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tool choice: %w", err)
+	}
+
+	switch tc.Mode {
+	case llmprovider.ToolChoiceModeAuto:
+		return "auto", nil
+	case llmprovider.ToolChoiceModeRequired:
+		return "required", nil
+	case llmprovider.ToolChoiceModeNone:
+		return "none", nil
+	case llmprovider.ToolChoiceModeSpecific:
+		return map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name": *tc.ToolName,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported tool choice mode: %s", tc.Mode)
+	}
+}
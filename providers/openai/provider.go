@@ -0,0 +1,116 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// Provider implements the llmprovider.Provider interface for OpenAI's Chat Completions API.
+//
+// Supported features:
+// - Function calling (tools, tool_choice, parallel_tool_calls)
+// - Structured outputs (response_format: json_object, json_schema)
+// - Log probabilities (logprobs, top_logprobs → ResponseMetadata["logprobs"])
+// - Reasoning effort for reasoning models (ThinkingLevel → reasoning_effort)
+// - Built-in web search for search-preview models (provider-side search tool → web_search_options)
+//
+// Chat Completions does not return reasoning content, so no thinking blocks are produced.
+type Provider struct {
+	*client
+}
+
+// NewProvider creates a new OpenAI provider with the given API key.
+func NewProvider(apiKey string, opts ...llmprovider.Option) (*Provider, error) {
+	if apiKey == "" {
+		return nil, llmprovider.ErrInvalidAPIKey
+	}
+
+	return &Provider{client: newClient(apiKey, opts)}, nil
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() llmprovider.ProviderID {
+	return llmprovider.ProviderOpenAI
+}
+
+// SupportsModel returns true if this provider supports the given model.
+// OpenAI models start with "gpt-", "chatgpt-", "o1", "o3", "o4" or are fine-tunes ("ft:").
+func (p *Provider) SupportsModel(model string) bool {
+	return supportsModel(model)
+}
+
+// supportsModel reports whether model looks like an OpenAI model identifier.
+func supportsModel(model string) bool {
+	for _, prefix := range []string{"gpt-", "chatgpt-", "o1", "o3", "o4", "ft:"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModel returns a ModelError if the model is not supported.
+func validateModel(model string) error {
+	if !supportsModel(model) {
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: llmprovider.ProviderOpenAI.String(),
+			Reason:   "model not supported by OpenAI (must start with 'gpt-', 'chatgpt-', 'o1', 'o3', 'o4' or 'ft:')",
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	}
+	return nil
+}
+
+// GenerateResponse generates a non-streaming response from OpenAI.
+func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if err := validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build OpenAI API request (shared logic)
+	openaiReq, err := buildChatCompletionRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	openaiReq.Stream = false
+
+	httpReq, err := p.buildHTTPRequest(ctx, "/chat/completions", openaiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var chatResp ChatCompletionResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	response, err := convertFromChatCompletionResponse(&chatResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert response: %w", err)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,427 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// responsesProviderData is the sparse item JSON stored in Block.ProviderData for replay.
+// Reasoning items keep their encrypted content so multi-turn reasoning replays losslessly,
+// the same way Anthropic thinking blocks keep their signature.
+type responsesProviderData struct {
+	Type   string           `json:"type"`
+	ID     string           `json:"id,omitempty"`
+	Status string           `json:"status,omitempty"`
+	Action *WebSearchAction `json:"action,omitempty"`
+}
+
+// reasoningItem is the stored form of a reasoning item (summary is required on input, even when empty).
+type reasoningItem struct {
+	Type             string             `json:"type"` // "reasoning"
+	ID               string             `json:"id"`
+	Summary          []ReasoningSummary `json:"summary"`
+	EncryptedContent *string            `json:"encrypted_content,omitempty"`
+}
+
+// convertToResponsesInput converts library messages to Responses API input items.
+// Unlike Chat Completions, input is a flat item list, so tool results and tool calls
+// don't need to be split into separate messages.
+func convertToResponsesInput(messages []llmprovider.Message) ([]interface{}, error) {
+	// Handle cross-provider server tools (e.g., Anthropic web_search replayed to OpenAI)
+	messages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, llmprovider.ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
+
+	input := make([]interface{}, 0, len(messages))
+
+	for i, msg := range messages {
+		for j, block := range msg.Blocks {
+			switch block.BlockType {
+			case llmprovider.BlockTypeText:
+				if block.TextContent == nil {
+					return nil, fmt.Errorf("message %d, block %d: text block missing text_content", i, j)
+				}
+				partType := "input_text"
+				if msg.Role == "assistant" {
+					partType = "output_text"
+				}
+				input = appendMessageContent(input, msg.Role, InputContent{Type: partType, Text: *block.TextContent})
+
+			case llmprovider.BlockTypeImage:
+				if msg.Role != "user" {
+					continue
+				}
+				imageURL, err := imageURLFromBlock(block)
+				if err != nil {
+					return nil, fmt.Errorf("message %d, block %d: %w", i, j, err)
+				}
+				input = appendMessageContent(input, msg.Role, InputContent{Type: "input_image", ImageURL: imageURL.URL})
+
+			case llmprovider.BlockTypeThinking:
+				// Reasoning can only be replayed from our own reasoning items (encrypted content)
+				if raw, ok := replayItem(block, "reasoning"); ok {
+					input = append(input, raw)
+				}
+
+			case llmprovider.BlockTypeWebSearch:
+				// Built-in web search calls replay verbatim; results are part of the call item
+				if raw, ok := replayItem(block, "web_search_call"); ok {
+					input = append(input, raw)
+				}
+
+			case llmprovider.BlockTypeToolUse:
+				if msg.Role != "assistant" {
+					continue
+				}
+				toolCall, err := convertToolUseToToolCall(block, i, j)
+				if err != nil {
+					return nil, err
+				}
+				input = append(input, FunctionCallItem{
+					Type:      "function_call",
+					ID:        replayItemID(block),
+					CallID:    toolCall.ID,
+					Name:      toolCall.Function.Name,
+					Arguments: toolCall.Function.Arguments,
+				})
+
+			case llmprovider.BlockTypeToolResult:
+				toolUseID, ok := block.GetToolUseID()
+				if !ok || toolUseID == "" {
+					return nil, fmt.Errorf("message %d, block %d: tool_result block missing tool_use_id", i, j)
+				}
+				output, _ := llmprovider.ToolResultText(block)
+				input = append(input, FunctionCallOutputItem{
+					Type:   "function_call_output",
+					CallID: toolUseID,
+					Output: output,
+				})
+
+			default:
+				// Skip web_search_result blocks - sources are carried by the web_search_call item
+				// Skip document blocks - not supported by this provider
+			}
+		}
+	}
+
+	return input, nil
+}
+
+// appendMessageContent adds a content part to the trailing message item when it has the same
+// role, otherwise starts a new message item.
+func appendMessageContent(input []interface{}, role string, part InputContent) []interface{} {
+	if len(input) > 0 {
+		if last, ok := input[len(input)-1].(*InputMessage); ok && last.Role == role {
+			last.Content = append(last.Content, part)
+			return input
+		}
+	}
+
+	return append(input, &InputMessage{
+		Type:    "message",
+		Role:    role,
+		Content: []InputContent{part},
+	})
+}
+
+// replayItem returns the stored Responses item for a block produced by this provider.
+// Returns false for blocks from other providers or without a stored item of the expected type.
+func replayItem(block *llmprovider.Block, itemType string) (json.RawMessage, bool) {
+	if !block.IsFromProvider(llmprovider.ProviderOpenAI) || !block.HasProviderData() {
+		return nil, false
+	}
+
+	var data responsesProviderData
+	if err := json.Unmarshal(block.ProviderData, &data); err != nil || data.Type != itemType {
+		return nil, false
+	}
+
+	return block.ProviderData, true
+}
+
+// replayItemID returns the original item ID of a block produced by this provider, if known.
+func replayItemID(block *llmprovider.Block) string {
+	if !block.IsFromProvider(llmprovider.ProviderOpenAI) || !block.HasProviderData() {
+		return ""
+	}
+
+	var data responsesProviderData
+	if err := json.Unmarshal(block.ProviderData, &data); err != nil {
+		return ""
+	}
+
+	return data.ID
+}
+
+// convertFromResponsesResponse converts a Responses API response to library format.
+func convertFromResponsesResponse(resp *ResponsesResponse) (*llmprovider.GenerateResponse, error) {
+	blocks := make([]*llmprovider.Block, 0, len(resp.Output))
+
+	for i, item := range resp.Output {
+		itemBlocks, err := convertOutputItem(item, len(blocks))
+		if err != nil {
+			return nil, fmt.Errorf("output item %d: %w", i, err)
+		}
+		blocks = append(blocks, itemBlocks...)
+	}
+
+	response := &llmprovider.GenerateResponse{
+		Blocks:           blocks,
+		Model:            resp.Model,
+		StopReason:       mapResponsesStopReason(resp, blocks),
+		ResponseMetadata: buildResponsesMetadata(resp),
+	}
+	if resp.Usage != nil {
+		response.InputTokens = resp.Usage.InputTokens
+		response.OutputTokens = resp.Usage.OutputTokens
+	}
+
+	return response, nil
+}
+
+// convertOutputItem converts a single output item to library blocks.
+// This is the shared conversion logic used by both streaming and non-streaming paths.
+// Most items produce one block; web_search_call produces a web_search_use and a web_search_result block.
+func convertOutputItem(item OutputItem, sequence int) ([]*llmprovider.Block, error) {
+	providerIDStr := llmprovider.ProviderOpenAI.String()
+
+	switch item.Type {
+	case "message":
+		blocks := make([]*llmprovider.Block, 0, len(item.Content))
+		for _, content := range item.Content {
+			var text string
+			var citations []llmprovider.Citation
+			switch content.Type {
+			case "output_text":
+				text = content.Text
+				citations = convertResponsesAnnotations(content.Annotations)
+			case "refusal":
+				// Refusals are surfaced as text so the caller sees why the model declined
+				text = content.Refusal
+			default:
+				continue
+			}
+			blocks = append(blocks, &llmprovider.Block{
+				BlockType:   llmprovider.BlockTypeText,
+				Sequence:    sequence + len(blocks),
+				TextContent: &text,
+				Provider:    &providerIDStr,
+				Citations:   citations,
+			})
+		}
+		return blocks, nil
+
+	case "reasoning":
+		// Summary text is the readable reasoning; the raw item (with encrypted content) is kept for replay
+		summaries := make([]string, 0, len(item.Summary))
+		for _, summary := range item.Summary {
+			summaries = append(summaries, summary.Text)
+		}
+		thinking := strings.Join(summaries, "\n\n")
+
+		itemData, err := json.Marshal(reasoningItem{
+			Type:             item.Type,
+			ID:               item.ID,
+			Summary:          nonNilSummary(item.Summary),
+			EncryptedContent: item.EncryptedContent,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshal reasoning provider data: %w", err)
+		}
+
+		return []*llmprovider.Block{{
+			BlockType:    llmprovider.BlockTypeThinking,
+			Sequence:     sequence,
+			TextContent:  &thinking,
+			Provider:     &providerIDStr,
+			ProviderData: itemData,
+		}}, nil
+
+	case "function_call":
+		block, err := convertToolCallToBlock(ToolCall{
+			ID:       item.CallID,
+			Type:     "function",
+			Function: FunctionCall{Name: item.Name, Arguments: item.Arguments},
+		}, sequence)
+		if err != nil {
+			return nil, err
+		}
+		block.ProviderData, err = json.Marshal(responsesProviderData{Type: item.Type, ID: item.ID})
+		if err != nil {
+			return nil, fmt.Errorf("marshal function_call provider data: %w", err)
+		}
+		return []*llmprovider.Block{block}, nil
+
+	case "web_search_call":
+		return convertWebSearchCall(item, sequence)
+
+	default:
+		// Unknown item types (e.g., future built-in tools) are skipped
+		return nil, nil
+	}
+}
+
+// convertWebSearchCall converts a built-in web_search_call item into a web_search_use block
+// (the invocation) and a web_search_result block (the consulted sources).
+func convertWebSearchCall(item OutputItem, sequence int) ([]*llmprovider.Block, error) {
+	providerIDStr := llmprovider.ProviderOpenAI.String()
+	executionSide := llmprovider.ExecutionSideProvider
+
+	input := map[string]interface{}{}
+	var sources []WebSearchSource
+	var replayAction *WebSearchAction
+	if item.Action != nil {
+		if item.Action.Query != "" {
+			input["query"] = item.Action.Query
+		}
+		if item.Action.URL != "" {
+			input["url"] = item.Action.URL
+		}
+		sources = item.Action.Sources
+		// Sources are output-only; keep the action itself for replay
+		replayAction = &WebSearchAction{Type: item.Action.Type, Query: item.Action.Query, URL: item.Action.URL}
+	}
+
+	itemData, err := json.Marshal(responsesProviderData{
+		Type:   item.Type,
+		ID:     item.ID,
+		Status: item.Status,
+		Action: replayAction,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal web_search_call provider data: %w", err)
+	}
+
+	useBlock := &llmprovider.Block{
+		BlockType: llmprovider.BlockTypeWebSearch,
+		Sequence:  sequence,
+		Content: map[string]interface{}{
+			"tool_use_id": item.ID,
+			"tool_name":   "web_search",
+			"input":       input,
+		},
+		ExecutionSide: &executionSide,
+		Provider:      &providerIDStr,
+		ProviderData:  itemData,
+	}
+
+	resultContent := map[string]interface{}{
+		"tool_use_id": item.ID,
+	}
+	if item.Status == "failed" {
+		resultContent["is_error"] = true
+		resultContent["error_code"] = "search_failed"
+	} else {
+		results := make([]map[string]interface{}, 0, len(sources))
+		for _, source := range sources {
+			result := map[string]interface{}{"url": source.URL}
+			if source.Title != "" {
+				result["title"] = source.Title
+			}
+			results = append(results, result)
+		}
+		resultContent["results"] = results
+	}
+
+	resultBlock := &llmprovider.Block{
+		BlockType:     llmprovider.BlockTypeWebSearchResult,
+		Sequence:      sequence + 1,
+		Content:       resultContent,
+		ExecutionSide: &executionSide,
+		Provider:      &providerIDStr,
+	}
+
+	return []*llmprovider.Block{useBlock, resultBlock}, nil
+}
+
+// nonNilSummary returns an empty slice for nil summaries.
+// Reasoning input items require the summary field, even when empty.
+func nonNilSummary(summary []ReasoningSummary) []ReasoningSummary {
+	if summary == nil {
+		return []ReasoningSummary{}
+	}
+	return summary
+}
+
+// convertResponsesAnnotations converts url_citation annotations to library Citation format.
+// Returns nil if there are no annotations.
+func convertResponsesAnnotations(annotations []ResponsesAnnotation) []llmprovider.Citation {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	citations := make([]llmprovider.Citation, 0, len(annotations))
+	for _, annotation := range annotations {
+		if annotation.Type != "url_citation" {
+			continue
+		}
+		startIndex := annotation.StartIndex
+		endIndex := annotation.EndIndex
+		citations = append(citations, llmprovider.Citation{
+			Type:       "url_citation",
+			URL:        annotation.URL,
+			Title:      annotation.Title,
+			StartIndex: &startIndex,
+			EndIndex:   &endIndex,
+		})
+	}
+
+	return citations
+}
+
+// buildResponsesMetadata collects provider-specific response data shared by both paths.
+func buildResponsesMetadata(resp *ResponsesResponse) map[string]interface{} {
+	metadata := make(map[string]interface{})
+
+	if resp.ID != "" {
+		metadata["response_id"] = resp.ID
+	}
+	if resp.Status != "" {
+		metadata["status"] = resp.Status
+	}
+	if resp.IncompleteDetails != nil && resp.IncompleteDetails.Reason != "" {
+		metadata["incomplete_reason"] = resp.IncompleteDetails.Reason
+	}
+
+	if resp.Usage != nil {
+		metadata["total_tokens"] = resp.Usage.TotalTokens
+		if resp.Usage.InputTokensDetails != nil && resp.Usage.InputTokensDetails.CachedTokens > 0 {
+			metadata["cache_read_input_tokens"] = resp.Usage.InputTokensDetails.CachedTokens
+		}
+		if resp.Usage.OutputTokensDetails != nil && resp.Usage.OutputTokensDetails.ReasoningTokens > 0 {
+			metadata["reasoning_tokens"] = resp.Usage.OutputTokensDetails.ReasoningTokens
+		}
+	}
+
+	return metadata
+}
+
+// mapResponsesStopReason derives the library stop_reason from response status.
+// The Responses API has no finish_reason: tool calls are detected from the output.
+func mapResponsesStopReason(resp *ResponsesResponse, blocks []*llmprovider.Block) string {
+	if resp.Status == "incomplete" && resp.IncompleteDetails != nil {
+		switch resp.IncompleteDetails.Reason {
+		case "max_output_tokens":
+			return "max_tokens"
+		case "content_filter":
+			return "refusal"
+		default:
+			return resp.IncompleteDetails.Reason
+		}
+	}
+
+	for _, block := range blocks {
+		if block.BlockType == llmprovider.BlockTypeToolUse {
+			return "tool_use"
+		}
+	}
+
+	if resp.Status == "completed" {
+		return "end_turn"
+	}
+	return resp.Status
+}
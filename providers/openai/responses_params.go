@@ -0,0 +1,347 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// ResponsesRequest represents a request to the OpenAI Responses API.
+type ResponsesRequest struct {
+	Model             string           `json:"model"`
+	Input             []interface{}    `json:"input"` // InputMessage, FunctionCallItem, FunctionCallOutputItem or json.RawMessage (replayed items)
+	Instructions      *string          `json:"instructions,omitempty"`
+	MaxOutputTokens   *int             `json:"max_output_tokens,omitempty"`
+	Temperature       *float64         `json:"temperature,omitempty"`
+	TopP              *float64         `json:"top_p,omitempty"`
+	Tools             []ResponsesTool  `json:"tools,omitempty"`
+	ToolChoice        interface{}      `json:"tool_choice,omitempty"` // "auto", "required", "none", or {"type": "function", "name": "..."}
+	ParallelToolCalls *bool            `json:"parallel_tool_calls,omitempty"`
+	Reasoning         *ReasoningConfig `json:"reasoning,omitempty"`
+	Text              *TextConfig      `json:"text,omitempty"`
+	Include           []string         `json:"include,omitempty"`
+	Store             *bool            `json:"store,omitempty"`
+	Stream            bool             `json:"stream,omitempty"`
+}
+
+// ReasoningConfig configures reasoning for o-series and gpt-5 models.
+type ReasoningConfig struct {
+	Effort  *string `json:"effort,omitempty"`  // "minimal", "low", "medium", "high"
+	Summary *string `json:"summary,omitempty"` // "auto", "concise", "detailed"
+}
+
+// TextConfig configures the text output format.
+type TextConfig struct {
+	Format map[string]interface{} `json:"format,omitempty"` // {"type": "json_schema", "name": "...", "schema": {...}, "strict": true}
+}
+
+// ResponsesTool represents a tool definition in the Responses API.
+// Function tools are flattened (no nested "function" object); built-in tools only set Type.
+type ResponsesTool struct {
+	Type        string                 `json:"type"` // "function", "web_search"
+	Name        string                 `json:"name,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// InputMessage is a message item in the Responses API input.
+type InputMessage struct {
+	Type    string         `json:"type"` // "message"
+	Role    string         `json:"role"` // "user", "assistant"
+	Content []InputContent `json:"content"`
+}
+
+// InputContent is a content part of an input message.
+type InputContent struct {
+	Type     string `json:"type"` // "input_text", "input_image", "output_text"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// FunctionCallItem is a function_call item (model output, replayed as input).
+type FunctionCallItem struct {
+	Type      string `json:"type"` // "function_call"
+	ID        string `json:"id,omitempty"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// FunctionCallOutputItem returns the result of a function call to the model.
+type FunctionCallOutputItem struct {
+	Type   string `json:"type"` // "function_call_output"
+	CallID string `json:"call_id"`
+	Output string `json:"output"`
+}
+
+// ResponsesResponse represents a Responses API response object.
+type ResponsesResponse struct {
+	ID                string             `json:"id"`
+	Object            string             `json:"object"` // "response"
+	CreatedAt         int64              `json:"created_at"`
+	Model             string             `json:"model"`
+	Status            string             `json:"status"` // "completed", "incomplete", "failed", "in_progress"
+	IncompleteDetails *IncompleteDetails `json:"incomplete_details,omitempty"`
+	Error             *ResponseError     `json:"error,omitempty"`
+	Output            []OutputItem       `json:"output"`
+	Usage             *ResponsesUsage    `json:"usage,omitempty"`
+}
+
+// IncompleteDetails explains why a response is incomplete.
+type IncompleteDetails struct {
+	Reason string `json:"reason"` // "max_output_tokens", "content_filter"
+}
+
+// ResponseError is the error object of a failed response.
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// OutputItem is an item in the response output array.
+type OutputItem struct {
+	Type   string `json:"type"` // "message", "reasoning", "function_call", "web_search_call"
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+
+	// message
+	Role    string          `json:"role,omitempty"`
+	Content []OutputContent `json:"content,omitempty"`
+
+	// reasoning
+	Summary          []ReasoningSummary `json:"summary,omitempty"`
+	EncryptedContent *string            `json:"encrypted_content,omitempty"`
+
+	// function_call
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+
+	// web_search_call
+	Action *WebSearchAction `json:"action,omitempty"`
+}
+
+// OutputContent is a content part of an output message.
+type OutputContent struct {
+	Type        string                `json:"type"` // "output_text", "refusal"
+	Text        string                `json:"text,omitempty"`
+	Refusal     string                `json:"refusal,omitempty"`
+	Annotations []ResponsesAnnotation `json:"annotations,omitempty"`
+}
+
+// ResponsesAnnotation is a citation attached to output text (flattened, unlike Chat Completions).
+type ResponsesAnnotation struct {
+	Type       string `json:"type"` // "url_citation"
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+}
+
+// ReasoningSummary is a summary part of a reasoning item.
+type ReasoningSummary struct {
+	Type string `json:"type"` // "summary_text"
+	Text string `json:"text"`
+}
+
+// WebSearchAction describes what a web_search_call did.
+type WebSearchAction struct {
+	Type    string            `json:"type"` // "search", "open_page", "find"
+	Query   string            `json:"query,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Sources []WebSearchSource `json:"sources,omitempty"` // Requires include "web_search_call.action.sources"
+}
+
+// WebSearchSource is a source consulted by a web search.
+type WebSearchSource struct {
+	Type  string `json:"type"` // "url"
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+// ResponsesUsage reports token usage for a response.
+type ResponsesUsage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details,omitempty"`
+	OutputTokensDetails *struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"output_tokens_details,omitempty"`
+}
+
+// buildResponsesRequest constructs a Responses API request from a GenerateRequest.
+// This function is shared between GenerateResponse and StreamResponse to avoid duplication.
+//
+// Requests are stateless (store=false): the full conversation is sent every turn, and reasoning
+// items are replayed from Block.ProviderData with their encrypted content.
+func buildResponsesRequest(req *llmprovider.GenerateRequest) (*ResponsesRequest, error) {
+	// Extract params or use defaults
+	params := req.Params
+	if params == nil {
+		params = &llmprovider.RequestParams{}
+	}
+
+	input, err := convertToResponsesInput(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert messages: %w", err)
+	}
+
+	store := false
+	responsesReq := &ResponsesRequest{
+		Model:           req.Model,
+		Input:           input,
+		MaxOutputTokens: params.MaxTokens,
+		Temperature:     params.Temperature,
+		TopP:            params.TopP,
+		Store:           &store,
+	}
+
+	// System prompt goes in instructions
+	if params.System != nil && *params.System != "" {
+		responsesReq.Instructions = params.System
+	}
+
+	// Structured outputs (text.format is the flattened json_schema object)
+	if params.ResponseFormat != nil {
+		format := map[string]interface{}{"type": params.ResponseFormat.Type}
+		if schema, ok := params.ResponseFormat.JSONSchema.(map[string]interface{}); ok {
+			for k, v := range schema {
+				format[k] = v
+			}
+		}
+		responsesReq.Text = &TextConfig{Format: format}
+	}
+
+	// Tools
+	if len(params.Tools) > 0 {
+		tools, err := convertToResponsesTools(params.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tools: %w", err)
+		}
+		responsesReq.Tools = tools
+
+		for _, tool := range tools {
+			if tool.Type == "web_search" {
+				responsesReq.Include = append(responsesReq.Include, "web_search_call.action.sources")
+				break
+			}
+		}
+	}
+
+	// Tool choice (only meaningful when tools are present)
+	if params.ToolChoice != nil && len(responsesReq.Tools) > 0 {
+		toolChoice, err := convertResponsesToolChoice(params.ToolChoice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool choice: %w", err)
+		}
+		responsesReq.ToolChoice = toolChoice
+	}
+
+	// Parallel tool calls (only valid when tools are present)
+	if params.ParallelToolCalls != nil && len(responsesReq.Tools) > 0 {
+		responsesReq.ParallelToolCalls = params.ParallelToolCalls
+	}
+
+	// Reasoning - only reasoning models accept the reasoning config and encrypted content
+	if isReasoningModel(req.Model) {
+		reasoning := &ReasoningConfig{}
+		if params.ThinkingEnabled != nil && *params.ThinkingEnabled {
+			summary := "auto"
+			reasoning.Summary = &summary
+			if params.ThinkingLevel != nil {
+				effort := *params.ThinkingLevel // "low", "medium", "high" map directly
+				reasoning.Effort = &effort
+			}
+		}
+		if reasoning.Effort != nil || reasoning.Summary != nil {
+			responsesReq.Reasoning = reasoning
+		}
+
+		// Encrypted reasoning is required to replay reasoning items with store=false
+		responsesReq.Include = append(responsesReq.Include, "reasoning.encrypted_content")
+	}
+
+	return responsesReq, nil
+}
+
+// BuildResponsesRequestDebug builds the Responses API request payload for debugging.
+// It converts the library GenerateRequest to OpenAI's ResponsesRequest format
+// and returns it as a map[string]interface{} for inspection. No network calls are made.
+func BuildResponsesRequestDebug(req *llmprovider.GenerateRequest) (map[string]interface{}, error) {
+	responsesReq, err := buildResponsesRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(responsesReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal responses request: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal responses request: %w", err)
+	}
+
+	return result, nil
+}
+
+// isReasoningModel reports whether model is a reasoning model (o-series or gpt-5).
+func isReasoningModel(model string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4", "gpt-5"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertToResponsesTools converts library tools to Responses API format.
+// A provider-side search tool becomes the built-in web_search tool.
+func convertToResponsesTools(tools []llmprovider.Tool) ([]ResponsesTool, error) {
+	result := make([]ResponsesTool, 0, len(tools))
+
+	for i, tool := range tools {
+		if isSearchTool(&tool) && tool.ExecutionSide == llmprovider.ExecutionSideProvider {
+			result = append(result, ResponsesTool{Type: "web_search"})
+			continue
+		}
+
+		chatTool, err := convertCustomTool(&tool)
+		if err != nil {
+			return nil, fmt.Errorf("tool %d (%s): %w", i, tool.Function.Name, err)
+		}
+		result = append(result, ResponsesTool{
+			Type:        "function",
+			Name:        chatTool.Function.Name,
+			Description: chatTool.Function.Description,
+			Parameters:  chatTool.Function.Parameters,
+		})
+	}
+
+	return result, nil
+}
+
+// convertResponsesToolChoice converts library tool choice to Responses API format.
+// Identical to Chat Completions except the specific-function form is flattened.
+func convertResponsesToolChoice(choice interface{}) (interface{}, error) {
+	toolChoice, err := convertToolChoice(choice)
+	if err != nil {
+		return nil, err
+	}
+
+	if specific, ok := toolChoice.(map[string]interface{}); ok {
+		function, _ := specific["function"].(map[string]interface{})
+		return map[string]interface{}{
+			"type": "function",
+			"name": function["name"],
+		}, nil
+	}
+
+	return toolChoice, nil
+}
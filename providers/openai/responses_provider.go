@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// ResponsesProvider implements the llmprovider.Provider interface for OpenAI's Responses API.
+//
+// Unlike the Chat Completions provider, the Responses API returns reasoning items, which are
+// mapped to thinking blocks. Requests are stateless (store=false): each reasoning item is kept
+// in Block.ProviderData with its encrypted content and replayed verbatim on the next turn.
+//
+// Mappings:
+// - reasoning → thinking (summary text in TextContent, item in ProviderData)
+// - function_call → tool_use, function_call_output ← tool_result
+// - web_search_call → web_search_use + web_search_result (provider-side search tool → web_search)
+// - url_citation annotations → Citations
+type ResponsesProvider struct {
+	*client
+}
+
+// NewResponsesProvider creates a new OpenAI Responses API provider with the given API key.
+func NewResponsesProvider(apiKey string, opts ...llmprovider.Option) (*ResponsesProvider, error) {
+	if apiKey == "" {
+		return nil, llmprovider.ErrInvalidAPIKey
+	}
+
+	return &ResponsesProvider{client: newClient(apiKey, opts)}, nil
+}
+
+// Name returns the provider identifier, distinct from the Chat Completions provider's so
+// both can be registered in one Registry. Blocks are stamped ProviderOpenAI either way.
+func (p *ResponsesProvider) Name() llmprovider.ProviderID {
+	return llmprovider.ProviderOpenAIResponses
+}
+
+// SupportsModel returns true if this provider supports the given model.
+// Accepts the same model identifiers as the Chat Completions provider.
+func (p *ResponsesProvider) SupportsModel(model string) bool {
+	return supportsModel(model)
+}
+
+// GenerateResponse generates a non-streaming response from the Responses API.
+func (p *ResponsesProvider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if err := validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Responses API request (shared logic)
+	responsesReq, err := buildResponsesRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	responsesReq.Stream = false
+
+	httpReq, err := p.buildHTTPRequest(ctx, "/responses", responsesReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var responsesResp ResponsesResponse
+	if err := json.Unmarshal(body, &responsesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if responsesResp.Status == "failed" {
+		return nil, responseFailedError(responsesResp.Error)
+	}
+
+	response, err := convertFromResponsesResponse(&responsesResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert response: %w", err)
+	}
+
+	return response, nil
+}
+
+// responseFailedError converts the error object of a failed response to a ProviderError.
+func responseFailedError(respErr *ResponseError) error {
+	message := "response failed"
+	code := ""
+	if respErr != nil {
+		message = respErr.Message
+		code = respErr.Code
+	}
+
+	if code == "rate_limit_exceeded" {
+		return &llmprovider.ProviderError{
+			Code:      llmprovider.ErrorCodeRateLimited,
+			Provider:  llmprovider.ProviderOpenAI.String(),
+			Message:   message,
+			Retryable: true,
+			Err:       llmprovider.ErrRateLimited,
+		}
+	}
+
+	return &llmprovider.ProviderError{
+		Code:      llmprovider.ErrorCodeProviderUnavailable,
+		Provider:  llmprovider.ProviderOpenAI.String(),
+		Message:   message,
+		Retryable: code == "server_error",
+		Err:       llmprovider.ErrProviderUnavailable,
+	}
+}
@@ -0,0 +1,279 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/sse"
+)
+
+// ResponsesStreamEvent is a server-sent event from the Responses API.
+// Only the fields used by this provider are decoded.
+type ResponsesStreamEvent struct {
+	Type         string             `json:"type"`
+	OutputIndex  int                `json:"output_index"`
+	ContentIndex int                `json:"content_index"`
+	SummaryIndex int                `json:"summary_index"`
+	ItemID       string             `json:"item_id,omitempty"`
+	Delta        string             `json:"delta,omitempty"`
+	Item         *OutputItem        `json:"item,omitempty"`
+	Part         *OutputContent     `json:"part,omitempty"`
+	Response     *ResponsesResponse `json:"response,omitempty"`
+
+	// "error" events
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// StreamResponse generates a streaming response from the Responses API.
+func (p *ResponsesProvider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	if err := validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build Responses API request (shared logic)
+	responsesReq, err := buildResponsesRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	responsesReq.Stream = true
+
+	httpReq, err := p.buildHTTPRequest(ctx, "/responses", responsesReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai HTTP request failed: %w", err)
+	}
+
+	// Check for immediate errors
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	eventChan := make(chan llmprovider.StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+		defer resp.Body.Close()
+
+		if err := streamResponsesEvents(ctx, resp.Body, eventChan); err != nil {
+			select {
+			case eventChan <- llmprovider.StreamEvent{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
+
+// responsesStreamState tracks block assignment while a Responses stream is consumed.
+// Output items and message content parts get block indices in order of appearance.
+type responsesStreamState struct {
+	nextIndex    int
+	blockIndices map[[2]int]int // (output_index, content_index) -> block index
+	blocks       []*llmprovider.Block
+	response     *ResponsesResponse
+}
+
+// blockIndex returns the block index for an output position, assigning one if needed.
+func (s *responsesStreamState) blockIndex(outputIndex, contentIndex int) (int, bool) {
+	key := [2]int{outputIndex, contentIndex}
+	if idx, ok := s.blockIndices[key]; ok {
+		return idx, false
+	}
+	idx := s.nextIndex
+	s.nextIndex++
+	s.blockIndices[key] = idx
+	return idx, true
+}
+
+// streamResponsesEvents reads Responses API SSE events and emits library StreamEvents.
+func streamResponsesEvents(ctx context.Context, body io.Reader, eventChan chan<- llmprovider.StreamEvent) error {
+	// Output items (encrypted reasoning, completed responses) can be large; the reader has no line limit
+	reader := sse.NewReader(body)
+
+	state := &responsesStreamState{blockIndices: make(map[[2]int]int)}
+
+	send := func(event llmprovider.StreamEvent) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case eventChan <- event:
+			return nil
+		}
+	}
+
+	for {
+		sseEvent, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+
+		// The event type is repeated in the data payload, so only the data is read
+		data := strings.TrimSpace(sseEvent.Data)
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+
+		var event ResponsesStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			// Ignore unparseable events (keep-alives, etc.)
+			continue
+		}
+
+		done, err := state.processEvent(&event, send)
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+	}
+
+	if state.response == nil {
+		return fmt.Errorf("stream ended before response completed")
+	}
+
+	metadata := &llmprovider.StreamMetadata{
+		Model:            state.response.Model,
+		StopReason:       mapResponsesStopReason(state.response, state.blocks),
+		ResponseMetadata: buildResponsesMetadata(state.response),
+	}
+	if state.response.Usage != nil {
+		metadata.InputTokens = state.response.Usage.InputTokens
+		metadata.OutputTokens = state.response.Usage.OutputTokens
+	}
+
+	return send(llmprovider.StreamEvent{Metadata: metadata})
+}
+
+// processEvent updates stream state from an event and emits deltas and complete blocks.
+// Returns true when the response has finished.
+func (s *responsesStreamState) processEvent(event *ResponsesStreamEvent, send func(llmprovider.StreamEvent) error) (bool, error) {
+	switch event.Type {
+	case "response.output_item.added":
+		if event.Item == nil {
+			return false, nil
+		}
+		switch event.Item.Type {
+		case "reasoning":
+			idx, _ := s.blockIndex(event.OutputIndex, 0)
+			return false, send(blockStartEvent(idx, llmprovider.BlockTypeThinking, llmprovider.DeltaTypeThinking))
+
+		case "function_call":
+			idx, _ := s.blockIndex(event.OutputIndex, 0)
+			return false, send(toolCallStartEvent(idx, llmprovider.BlockTypeToolUse, event.Item.CallID, event.Item.Name))
+
+		case "web_search_call":
+			idx, _ := s.blockIndex(event.OutputIndex, 0)
+			return false, send(toolCallStartEvent(idx, llmprovider.BlockTypeWebSearch, event.Item.ID, "web_search"))
+		}
+
+	case "response.content_part.added":
+		if event.Part == nil || (event.Part.Type != "output_text" && event.Part.Type != "refusal") {
+			return false, nil
+		}
+		idx, _ := s.blockIndex(event.OutputIndex, event.ContentIndex)
+		return false, send(blockStartEvent(idx, llmprovider.BlockTypeText, llmprovider.DeltaTypeText))
+
+	case "response.output_text.delta", "response.refusal.delta":
+		idx, isNew := s.blockIndex(event.OutputIndex, event.ContentIndex)
+		if isNew {
+			if err := send(blockStartEvent(idx, llmprovider.BlockTypeText, llmprovider.DeltaTypeText)); err != nil {
+				return false, err
+			}
+		}
+		return false, send(textDeltaEvent(idx, event.Delta))
+
+	case "response.reasoning_summary_part.added":
+		// Summary parts are joined with blank lines in the final thinking block
+		if event.SummaryIndex > 0 {
+			idx, _ := s.blockIndex(event.OutputIndex, 0)
+			return false, send(thinkingDeltaEvent(idx, "\n\n"))
+		}
+
+	case "response.reasoning_summary_text.delta":
+		idx, _ := s.blockIndex(event.OutputIndex, 0)
+		return false, send(thinkingDeltaEvent(idx, event.Delta))
+
+	case "response.function_call_arguments.delta":
+		idx, _ := s.blockIndex(event.OutputIndex, 0)
+		args := event.Delta
+		return false, send(llmprovider.StreamEvent{
+			Delta: &llmprovider.BlockDelta{
+				BlockIndex: idx,
+				DeltaType:  llmprovider.DeltaTypeJSON,
+				JSONDelta:  &args,
+			},
+		})
+
+	case "response.output_item.done":
+		if event.Item == nil {
+			return false, nil
+		}
+		return false, s.completeItem(event.OutputIndex, *event.Item, send)
+
+	case "response.completed", "response.incomplete":
+		s.response = event.Response
+		return true, nil
+
+	case "response.failed":
+		if event.Response != nil {
+			return true, responseFailedError(event.Response.Error)
+		}
+		return true, responseFailedError(nil)
+
+	case "error":
+		return true, responseFailedError(&ResponseError{Code: event.Code, Message: event.Message})
+	}
+
+	return false, nil
+}
+
+// completeItem converts a finished output item and emits its complete blocks (for persistence).
+func (s *responsesStreamState) completeItem(outputIndex int, item OutputItem, send func(llmprovider.StreamEvent) error) error {
+	blocks, err := convertOutputItem(item, 0)
+	if err != nil {
+		return fmt.Errorf("output item %d: %w", outputIndex, err)
+	}
+
+	// Map each block back to the index its deltas used (message blocks follow content part order)
+	contentIndices := make([]int, 0, len(blocks))
+	switch item.Type {
+	case "message":
+		for i, content := range item.Content {
+			if content.Type == "output_text" || content.Type == "refusal" {
+				contentIndices = append(contentIndices, i)
+			}
+		}
+	default:
+		for i := range blocks {
+			contentIndices = append(contentIndices, i)
+		}
+	}
+
+	for i, block := range blocks {
+		block.Sequence, _ = s.blockIndex(outputIndex, contentIndices[i])
+		s.blocks = append(s.blocks, block)
+		if err := send(llmprovider.StreamEvent{Block: block}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,405 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/sse"
+)
+
+// ChatCompletionChunk represents a streaming chunk from the Chat Completions API.
+type ChatCompletionChunk struct {
+	ID                string        `json:"id"`
+	Object            string        `json:"object"` // "chat.completion.chunk"
+	Created           int64         `json:"created"`
+	Model             string        `json:"model"`
+	SystemFingerprint string        `json:"system_fingerprint,omitempty"`
+	Choices           []ChunkChoice `json:"choices"`
+	Usage             *Usage        `json:"usage,omitempty"` // Only in the final chunk (stream_options.include_usage)
+}
+
+// ChunkChoice represents a choice in a streaming chunk.
+type ChunkChoice struct {
+	Index        int       `json:"index"`
+	Delta        Delta     `json:"delta"`
+	LogProbs     *LogProbs `json:"logprobs,omitempty"`
+	FinishReason *string   `json:"finish_reason"`
+}
+
+// Delta represents incremental updates in a chunk.
+type Delta struct {
+	Role        *string      `json:"role,omitempty"`
+	Content     *string      `json:"content,omitempty"`
+	Refusal     *string      `json:"refusal,omitempty"`
+	ToolCalls   []ToolCall   `json:"tool_calls,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// StreamResponse generates a streaming response from OpenAI.
+func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	if err := validateModel(req.Model); err != nil {
+		return nil, err
+	}
+
+	// Build OpenAI API request (shared logic)
+	openaiReq, err := buildChatCompletionRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enable streaming with a final usage chunk
+	openaiReq.Stream = true
+	openaiReq.StreamOptions = &StreamOptions{IncludeUsage: true}
+
+	httpReq, err := p.buildHTTPRequest(ctx, "/chat/completions", openaiReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai HTTP request failed: %w", err)
+	}
+
+	// Check for immediate errors
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	eventChan := make(chan llmprovider.StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+		defer resp.Body.Close()
+
+		if err := streamEvents(ctx, resp.Body, eventChan); err != nil {
+			select {
+			case eventChan <- llmprovider.StreamEvent{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
+
+// streamState tracks block assignment while a Chat Completions stream is consumed.
+// Block indices are assigned in order of first appearance: text, refusal, then tool calls.
+type streamState struct {
+	nextIndex int
+
+	textIndex   int // -1 until text content arrives
+	text        strings.Builder
+	annotations []Annotation
+
+	refusalIndex int // -1 until refusal content arrives
+	refusal      strings.Builder
+
+	toolCalls map[int]*accumulatedToolCall // OpenAI tool call index -> accumulated call
+
+	logProbs    LogProbs
+	model       string
+	responseID  string
+	fingerprint string
+	stopReason  string
+	usage       *Usage
+}
+
+// accumulatedToolCall holds state for accumulating a tool call during streaming.
+type accumulatedToolCall struct {
+	BlockIndex int
+	ID         string
+	Name       string
+	Arguments  strings.Builder
+}
+
+// streamEvents reads SSE events and emits library StreamEvents.
+func streamEvents(ctx context.Context, body io.Reader, eventChan chan<- llmprovider.StreamEvent) error {
+	// Tool call arguments can arrive in large single chunks; the reader has no line limit
+	reader := sse.NewReader(body)
+
+	state := &streamState{
+		textIndex:    -1,
+		refusalIndex: -1,
+		toolCalls:    make(map[int]*accumulatedToolCall),
+	}
+
+	send := func(event llmprovider.StreamEvent) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case eventChan <- event:
+			return nil
+		}
+	}
+
+	for {
+		sseEvent, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+
+		data := strings.TrimSpace(sseEvent.Data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			var errResp errorResponse
+			if json.Unmarshal([]byte(data), &errResp) == nil && errResp.Error.Message != "" {
+				return &llmprovider.ProviderError{
+					Code:      llmprovider.ErrorCodeProviderUnavailable,
+					Provider:  llmprovider.ProviderOpenAI.String(),
+					Message:   errResp.Error.Message,
+					Retryable: errResp.Error.Type == "server_error",
+					Err:       llmprovider.ErrProviderUnavailable,
+				}
+			}
+			// Ignore unparseable chunks (keep-alives, etc.)
+			continue
+		}
+
+		if err := state.processChunk(&chunk, send); err != nil {
+			return err
+		}
+	}
+
+	// Emit complete blocks (for persistence) in block index order
+	blocks, err := state.buildBlocks()
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if err := send(llmprovider.StreamEvent{Block: block}); err != nil {
+			return err
+		}
+	}
+
+	refusal := state.refusal.String()
+	metadata := &llmprovider.StreamMetadata{
+		Model:            state.model,
+		StopReason:       state.stopReason,
+		ResponseMetadata: buildResponseMetadata(state.responseID, state.fingerprint, state.usage, &state.logProbs, &refusal),
+	}
+	if state.usage != nil {
+		metadata.InputTokens = state.usage.PromptTokens
+		metadata.OutputTokens = state.usage.CompletionTokens
+	}
+
+	return send(llmprovider.StreamEvent{Metadata: metadata})
+}
+
+// processChunk updates stream state from a chunk and emits deltas.
+func (s *streamState) processChunk(chunk *ChatCompletionChunk, send func(llmprovider.StreamEvent) error) error {
+	if chunk.Model != "" {
+		s.model = chunk.Model
+	}
+	if chunk.ID != "" {
+		s.responseID = chunk.ID
+	}
+	if chunk.SystemFingerprint != "" {
+		s.fingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		s.usage = chunk.Usage
+	}
+
+	// The final usage chunk has no choices
+	if len(chunk.Choices) == 0 {
+		return nil
+	}
+
+	choice := chunk.Choices[0]
+	delta := choice.Delta
+
+	if choice.LogProbs != nil {
+		s.logProbs.Content = append(s.logProbs.Content, choice.LogProbs.Content...)
+		s.logProbs.Refusal = append(s.logProbs.Refusal, choice.LogProbs.Refusal...)
+	}
+
+	if len(delta.Annotations) > 0 {
+		s.annotations = append(s.annotations, delta.Annotations...)
+	}
+
+	// Text content
+	if delta.Content != nil && *delta.Content != "" {
+		if s.textIndex < 0 {
+			s.textIndex = s.nextIndex
+			s.nextIndex++
+			if err := send(blockStartEvent(s.textIndex, llmprovider.BlockTypeText, llmprovider.DeltaTypeText)); err != nil {
+				return err
+			}
+		}
+		s.text.WriteString(*delta.Content)
+		if err := send(textDeltaEvent(s.textIndex, *delta.Content)); err != nil {
+			return err
+		}
+	}
+
+	// Refusal content (streamed like text, kept as a separate block)
+	if delta.Refusal != nil && *delta.Refusal != "" {
+		if s.refusalIndex < 0 {
+			s.refusalIndex = s.nextIndex
+			s.nextIndex++
+			if err := send(blockStartEvent(s.refusalIndex, llmprovider.BlockTypeText, llmprovider.DeltaTypeText)); err != nil {
+				return err
+			}
+		}
+		s.refusal.WriteString(*delta.Refusal)
+		if err := send(textDeltaEvent(s.refusalIndex, *delta.Refusal)); err != nil {
+			return err
+		}
+	}
+
+	// Tool calls (arguments arrive as incremental JSON)
+	for position, toolCallDelta := range delta.ToolCalls {
+		idx := position
+		if toolCallDelta.Index != nil {
+			idx = *toolCallDelta.Index
+		}
+
+		acc, exists := s.toolCalls[idx]
+		if !exists {
+			acc = &accumulatedToolCall{BlockIndex: s.nextIndex}
+			s.nextIndex++
+			s.toolCalls[idx] = acc
+
+			if err := send(toolCallStartEvent(acc.BlockIndex, llmprovider.BlockTypeToolUse, toolCallDelta.ID, toolCallDelta.Function.Name)); err != nil {
+				return err
+			}
+		}
+
+		if toolCallDelta.ID != "" {
+			acc.ID = toolCallDelta.ID
+		}
+		if toolCallDelta.Function.Name != "" {
+			acc.Name = toolCallDelta.Function.Name
+		}
+		if toolCallDelta.Function.Arguments != "" {
+			acc.Arguments.WriteString(toolCallDelta.Function.Arguments)
+			args := toolCallDelta.Function.Arguments
+			if err := send(llmprovider.StreamEvent{
+				Delta: &llmprovider.BlockDelta{
+					BlockIndex: acc.BlockIndex,
+					DeltaType:  llmprovider.DeltaTypeJSON,
+					JSONDelta:  &args,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if choice.FinishReason != nil {
+		s.stopReason = mapFinishReason(*choice.FinishReason)
+	}
+
+	return nil
+}
+
+// buildBlocks builds the complete blocks accumulated during the stream, ordered by block index.
+func (s *streamState) buildBlocks() ([]*llmprovider.Block, error) {
+	providerIDStr := llmprovider.ProviderOpenAI.String()
+	blocks := make([]*llmprovider.Block, 0, s.nextIndex)
+
+	if s.textIndex >= 0 {
+		text := s.text.String()
+		blocks = append(blocks, &llmprovider.Block{
+			BlockType:   llmprovider.BlockTypeText,
+			Sequence:    s.textIndex,
+			TextContent: &text,
+			Provider:    &providerIDStr,
+			Citations:   convertAnnotationsToCitations(s.annotations),
+		})
+	}
+
+	if s.refusalIndex >= 0 {
+		refusal := s.refusal.String()
+		blocks = append(blocks, &llmprovider.Block{
+			BlockType:   llmprovider.BlockTypeText,
+			Sequence:    s.refusalIndex,
+			TextContent: &refusal,
+			Provider:    &providerIDStr,
+		})
+	}
+
+	for idx, acc := range s.toolCalls {
+		block, err := convertToolCallToBlock(ToolCall{
+			ID:   acc.ID,
+			Type: "function",
+			Function: FunctionCall{
+				Name:      acc.Name,
+				Arguments: acc.Arguments.String(),
+			},
+		}, acc.BlockIndex)
+		if err != nil {
+			return nil, fmt.Errorf("tool call %d: %w", idx, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Sequence < blocks[j].Sequence
+	})
+
+	return blocks, nil
+}
+
+// blockStartEvent builds a delta that signals the start of a new block.
+func blockStartEvent(blockIndex int, blockType string, deltaType string) llmprovider.StreamEvent {
+	return llmprovider.StreamEvent{
+		Delta: &llmprovider.BlockDelta{
+			BlockIndex: blockIndex,
+			BlockType:  &blockType,
+			DeltaType:  deltaType,
+		},
+	}
+}
+
+// textDeltaEvent builds a text delta for the given block.
+func textDeltaEvent(blockIndex int, text string) llmprovider.StreamEvent {
+	return llmprovider.StreamEvent{
+		Delta: &llmprovider.BlockDelta{
+			BlockIndex: blockIndex,
+			DeltaType:  llmprovider.DeltaTypeText,
+			TextDelta:  &text,
+		},
+	}
+}
+
+// toolCallStartEvent builds a delta that signals the start of a tool call block.
+func toolCallStartEvent(blockIndex int, blockType string, id string, name string) llmprovider.StreamEvent {
+	return llmprovider.StreamEvent{
+		Delta: &llmprovider.BlockDelta{
+			BlockIndex:   blockIndex,
+			BlockType:    &blockType,
+			DeltaType:    llmprovider.DeltaTypeToolCallStart,
+			ToolCallID:   &id,
+			ToolCallName: &name,
+		},
+	}
+}
+
+// thinkingDeltaEvent builds a thinking delta for the given block.
+func thinkingDeltaEvent(blockIndex int, text string) llmprovider.StreamEvent {
+	return llmprovider.StreamEvent{
+		Delta: &llmprovider.BlockDelta{
+			BlockIndex: blockIndex,
+			DeltaType:  llmprovider.DeltaTypeThinking,
+			TextDelta:  &text,
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"fmt"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// convertToOpenAITools converts library Tool format to OpenAI format.
+// OpenAI is the native format for our Tool type, so function tools pass through directly.
+//
+// Provider-side search is not a function tool in Chat Completions: it is enabled with
+// web_search_options on search-preview models (e.g., gpt-4o-search-preview), so it is
+// returned separately instead of being added to the tools array.
+func convertToOpenAITools(tools []llmprovider.Tool) ([]Tool, *WebSearchOptions, error) {
+	if len(tools) == 0 {
+		return nil, nil, nil
+	}
+
+	result := make([]Tool, 0, len(tools))
+	var webSearch *WebSearchOptions
+
+	for i, tool := range tools {
+		// Route based on function name (OpenAI format uses tool.Function.Name)
+		switch {
+		case isSearchTool(&tool) && tool.ExecutionSide == llmprovider.ExecutionSideProvider:
+			webSearch = &WebSearchOptions{}
+
+		default:
+			// All other tools use standard function format (executed by our backend)
+			openaiTool, err := convertCustomTool(&tool)
+			if err != nil {
+				return nil, nil, fmt.Errorf("tool %d (%s): %w", i, tool.Function.Name, err)
+			}
+			result = append(result, openaiTool)
+		}
+	}
+
+	return result, webSearch, nil
+}
+
+// isSearchTool returns true if the tool is the built-in search tool.
+func isSearchTool(tool *llmprovider.Tool) bool {
+	return tool.Function.Name == "search" || tool.Function.Name == "web_search"
+}
+
+// convertCustomTool converts a custom function tool to OpenAI format.
+func convertCustomTool(tool *llmprovider.Tool) (Tool, error) {
+	if tool.Function.Name == "" {
+		return Tool{}, fmt.Errorf("function name is required")
+	}
+
+	// Extract parameters (already in JSON Schema format)
+	parameters := tool.Function.Parameters
+	if parameters == nil {
+		parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	funcDef := FunctionDefinition{
+		Name:       tool.Function.Name,
+		Parameters: parameters,
+	}
+
+	if tool.Function.Description != "" {
+		funcDef.Description = &tool.Function.Description
+	}
+
+	return Tool{
+		Type:     "function",
+		Function: funcDef,
+	}, nil
+}
@@ -0,0 +1,264 @@
+package openaicompat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/providers/openrouter"
+)
+
+// Config configures a generic OpenAI-compatible provider.
+type Config struct {
+	// BaseURL is the API root that serves /chat/completions (e.g., "http://localhost:8000/v1"). Required.
+	BaseURL string
+
+	// APIKey is optional; local inference servers usually don't require one.
+	APIKey string
+
+	// AuthHeader is the header carrying the API key. Defaults to "Authorization" with a "Bearer " prefix;
+	// any other header (e.g., "api-key") receives the raw key.
+	AuthHeader string
+
+	// Models is the allowlist of model names. Empty means any model is accepted.
+	Models []string
+
+	// Headers are extra headers sent with every request.
+	Headers map[string]string
+
+	// Name overrides the provider ID stamped on blocks. Defaults to ProviderOpenAICompatible.
+	Name llmprovider.ProviderID
+}
+
+// Provider implements the llmprovider.Provider interface for any server speaking the
+// OpenAI Chat Completions API (vLLM, llama.cpp server, LM Studio, LocalAI, ...).
+//
+// It reuses the request, response and SSE machinery from the openrouter package with
+// OpenRouter's extensions disabled: no reasoning config or reasoning_details, and no
+// synthetic web_search blocks for :online models.
+//
+// Provider-side tools are not supported; all tools are sent as function tools.
+type Provider struct {
+	config     Config
+	dialect    openrouter.Dialect
+	httpClient *http.Client
+}
+
+// NewProvider creates a new OpenAI-compatible provider from the given config.
+// Options set the HTTP client, timeout and user agent; llmprovider.WithBaseURL and
+// llmprovider.WithHeaders take precedence over the matching Config fields.
+func NewProvider(config Config, opts ...llmprovider.Option) (*Provider, error) {
+	options := llmprovider.ApplyOptions(opts...)
+
+	config.BaseURL = options.BaseURLOr(strings.TrimSuffix(config.BaseURL, "/"))
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("openai-compatible provider: base URL is required")
+	}
+
+	if config.Name == "" {
+		config.Name = llmprovider.ProviderOpenAICompatible
+	}
+
+	return &Provider{
+		config:     config,
+		dialect:    openrouter.Dialect{Provider: config.Name},
+		httpClient: options.NewHTTPClient(120 * time.Second),
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() llmprovider.ProviderID {
+	return p.config.Name
+}
+
+// SupportsModel returns true if the model is in the allowlist (or the allowlist is empty).
+func (p *Provider) SupportsModel(model string) bool {
+	if model == "" {
+		return false
+	}
+	if len(p.config.Models) == 0 {
+		return true
+	}
+	for _, allowed := range p.config.Models {
+		if allowed == model {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequest checks the model against the allowlist and rejects provider-side tools.
+func (p *Provider) validateRequest(req *llmprovider.GenerateRequest) error {
+	if !p.SupportsModel(req.Model) {
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    req.Model,
+			Provider: p.Name().String(),
+			Reason:   "model not in the configured allowlist",
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	}
+
+	if req.Params == nil {
+		return nil
+	}
+	for _, tool := range req.Params.Tools {
+		if tool.ExecutionSide == llmprovider.ExecutionSideProvider {
+			return &llmprovider.ToolError{
+				Code:     llmprovider.ErrorCodeUnsupportedTool,
+				Tool:     tool.Function.Name,
+				Provider: p.Name().String(),
+				Model:    req.Model,
+				Reason:   "provider-side tools are not supported by OpenAI-compatible servers",
+				Err:      llmprovider.ErrUnsupportedTool,
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateResponse generates a non-streaming response.
+func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	if err := p.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	chatReq, err := openrouter.BuildChatCompletionRequest(req, p.dialect)
+	if err != nil {
+		return nil, err
+	}
+	chatReq.Stream = false
+
+	httpReq, err := p.buildHTTPRequest(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s HTTP request failed: %w", p.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var chatResp openrouter.ChatCompletionResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	response, err := openrouter.ConvertChatCompletionResponse(&chatResp, p.dialect)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert response: %w", err)
+	}
+
+	return response, nil
+}
+
+// buildHTTPRequest creates a POST request to /chat/completions with auth and custom headers.
+func (p *Provider) buildHTTPRequest(ctx context.Context, req *openrouter.ChatCompletionRequest) (*http.Request, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range p.config.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	if p.config.APIKey != "" {
+		if p.config.AuthHeader == "" || strings.EqualFold(p.config.AuthHeader, "Authorization") {
+			httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+		} else {
+			httpReq.Header.Set(p.config.AuthHeader, p.config.APIKey)
+		}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return httpReq, nil
+}
+
+// errorResponse is the OpenAI-style error envelope. Servers vary in what they fill in,
+// so only the message is required.
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// handleErrorResponse parses error responses from the server.
+func (p *Provider) handleErrorResponse(resp *http.Response, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	message := strings.TrimSpace(string(body))
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		message = errResp.Error.Message
+	}
+
+	switch resp.StatusCode {
+	case 401, 403:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidAPIKey,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidAPIKey,
+		}
+	case 429:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeRateLimited,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  true,
+			Err:        llmprovider.ErrRateLimited,
+		}
+	case 404:
+		return &llmprovider.ModelError{
+			Code:     llmprovider.ErrorCodeInvalidModel,
+			Model:    model,
+			Provider: p.Name().String(),
+			Reason:   message,
+			Err:      llmprovider.ErrInvalidModel,
+		}
+	case 400, 422:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeInvalidRequest,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  false,
+			Err:        llmprovider.ErrInvalidRequest,
+		}
+	default:
+		return &llmprovider.ProviderError{
+			Code:       llmprovider.ErrorCodeProviderUnavailable,
+			Provider:   p.Name().String(),
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Retryable:  resp.StatusCode >= 500,
+			Err:        llmprovider.ErrProviderUnavailable,
+		}
+	}
+}
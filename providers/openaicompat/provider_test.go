@@ -0,0 +1,205 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/haowjy/meridian-llm-go"
+)
+
+// TestGenerateResponse_RequestShape tests auth/custom headers and that OpenRouter extensions are stripped
+func TestGenerateResponse_RequestShape(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"chatcmpl-1","model":"qwen3","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{
+		BaseURL:    server.URL + "/v1/",
+		APIKey:     "secret",
+		AuthHeader: "api-key",
+		Models:     []string{"qwen3"},
+		Headers:    map[string]string{"X-Team": "docs"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	thinking := "Earlier reasoning"
+	text := "Hello"
+	enabled := true
+	level := "high"
+	resp, err := provider.GenerateResponse(context.Background(), &llmprovider.GenerateRequest{
+		Model: "qwen3",
+		Messages: []llmprovider.Message{
+			{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &text}}},
+			{Role: "assistant", Blocks: []*llmprovider.Block{
+				{BlockType: llmprovider.BlockTypeThinking, TextContent: &thinking},
+				{BlockType: llmprovider.BlockTypeText, TextContent: &text},
+			}},
+			{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &text}}},
+		},
+		Params: &llmprovider.RequestParams{ThinkingEnabled: &enabled, ThinkingLevel: &level},
+	})
+	if err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+
+	if gotHeaders.Get("api-key") != "secret" || gotHeaders.Get("Authorization") != "" {
+		t.Errorf("expected raw key in api-key header only, got %v", gotHeaders)
+	}
+	if gotHeaders.Get("X-Team") != "docs" {
+		t.Errorf("expected custom header X-Team, got %v", gotHeaders)
+	}
+
+	if _, ok := gotBody["reasoning"]; ok {
+		t.Errorf("expected no reasoning config, got %v", gotBody["reasoning"])
+	}
+	for _, msg := range gotBody["messages"].([]interface{}) {
+		if _, ok := msg.(map[string]interface{})["reasoning_details"]; ok {
+			t.Errorf("expected no reasoning_details, got %v", msg)
+		}
+	}
+
+	if len(resp.Blocks) != 1 || *resp.Blocks[0].TextContent != "Hi" {
+		t.Fatalf("unexpected blocks: %+v", resp.Blocks)
+	}
+	if !resp.Blocks[0].IsFromProvider(llmprovider.ProviderOpenAICompatible) {
+		t.Errorf("expected block provider %q, got %v", llmprovider.ProviderOpenAICompatible, resp.Blocks[0].Provider)
+	}
+	if resp.StopReason != "end_turn" || resp.InputTokens != 5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+// TestStreamResponse tests SSE streaming with a tool call
+func TestStreamResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no Authorization header without API key")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, strings.Join([]string{
+			`data: {"model":"llama","choices":[{"index":0,"delta":{"content":"Checking"}}]}`,
+			`data: {"model":"llama","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}`,
+			`data: {"model":"llama","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+			`data: [DONE]`,
+			``,
+		}, "\n\n"))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{BaseURL: server.URL, Name: "vllm"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	text := "Weather in Paris?"
+	events, err := provider.StreamResponse(context.Background(), &llmprovider.GenerateRequest{
+		Model:    "llama",
+		Messages: []llmprovider.Message{{Role: "user", Blocks: []*llmprovider.Block{{BlockType: llmprovider.BlockTypeText, TextContent: &text}}}},
+	})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+
+	var blocks []*llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("stream error = %v", event.Error)
+		}
+		if event.Block != nil {
+			blocks = append(blocks, event.Block)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("expected text and tool_use blocks, got %d", len(blocks))
+	}
+	if name, _ := blocks[1].GetToolName(); name != "get_weather" {
+		t.Errorf("expected tool 'get_weather', got '%s'", name)
+	}
+	if !blocks[1].IsFromProvider("vllm") {
+		t.Errorf("expected block provider 'vllm', got %v", blocks[1].Provider)
+	}
+	if metadata == nil || metadata.StopReason != "tool_use" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+// TestModelAllowlist tests SupportsModel and request validation
+func TestModelAllowlist(t *testing.T) {
+	provider, err := NewProvider(Config{BaseURL: "http://localhost:8000/v1", Models: []string{"qwen3"}})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	if !provider.SupportsModel("qwen3") || provider.SupportsModel("gpt-4o") {
+		t.Errorf("allowlist not applied")
+	}
+
+	_, err = provider.GenerateResponse(context.Background(), &llmprovider.GenerateRequest{Model: "gpt-4o"})
+	if !errors.Is(err, llmprovider.ErrInvalidModel) {
+		t.Errorf("expected ErrInvalidModel, got %v", err)
+	}
+
+	if _, err := NewProvider(Config{}); err == nil {
+		t.Errorf("expected error for missing base URL")
+	}
+}
+
+// countingProvider counts GenerateResponse calls.
+type countingProvider struct {
+	*Provider
+	calls int
+}
+
+func (c *countingProvider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+	c.calls++
+	return c.Provider.GenerateResponse(ctx, req)
+}
+
+// TestProviderSideToolNoFallback tests that an unsupported provider-side tool is a
+// ToolError that FallbackProvider surfaces instead of failing over
+func TestProviderSideToolNoFallback(t *testing.T) {
+	provider, err := NewProvider(Config{BaseURL: "http://localhost:8000/v1"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	search, err := llmprovider.NewSearchTool()
+	if err != nil {
+		t.Fatalf("NewSearchTool() error = %v", err)
+	}
+	counting := &countingProvider{Provider: provider}
+
+	_, err = llmprovider.NewFallbackProvider(counting).GenerateResponse(context.Background(), &llmprovider.GenerateRequest{
+		Model:  "qwen3",
+		Params: &llmprovider.RequestParams{Tools: []llmprovider.Tool{*search}, FallbackModels: []string{"llama3.3"}},
+	})
+
+	var toolErr *llmprovider.ToolError
+	if !errors.As(err, &toolErr) || !errors.Is(err, llmprovider.ErrUnsupportedTool) || toolErr.Tool != search.Function.Name {
+		t.Errorf("expected a ToolError wrapping ErrUnsupportedTool, got %v", err)
+	}
+	if counting.calls != 1 {
+		t.Errorf("expected no fallback, got %d calls", counting.calls)
+	}
+}
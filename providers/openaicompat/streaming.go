@@ -0,0 +1,56 @@
+package openaicompat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/providers/openrouter"
+)
+
+// StreamResponse generates a streaming response.
+func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+	if err := p.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	chatReq, err := openrouter.BuildChatCompletionRequest(req, p.dialect)
+	if err != nil {
+		return nil, err
+	}
+	chatReq.Stream = true
+
+	httpReq, err := p.buildHTTPRequest(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s HTTP request failed: %w", p.Name(), err)
+	}
+
+	// Check for immediate errors
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
+	}
+
+	eventChan := make(chan llmprovider.StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+		defer resp.Body.Close()
+
+		if err := openrouter.StreamChatCompletion(ctx, resp.Body, p.dialect, eventChan); err != nil {
+			select {
+			case eventChan <- llmprovider.StreamEvent{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return eventChan, nil
+}
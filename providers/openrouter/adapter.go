@@ -166,15 +166,16 @@ func determineTransition(state BlockState, parsed *ParsedDelta) BlockTransition
 
 // buildNonStreamingBlocks builds complete blocks from parsed delta data.
 // This function only builds blocks - it doesn't parse or manage state transitions.
-func buildNonStreamingBlocks(parsed *ParsedDelta, state *BlockState) ([]*llmprovider.Block, error) {
+func buildNonStreamingBlocks(parsed *ParsedDelta, state *BlockState, dialect Dialect) ([]*llmprovider.Block, error) {
 	blocks := []*llmprovider.Block{}
-	providerIDStr := llmprovider.ProviderOpenRouter.String()
+	providerIDStr := dialect.Provider.String()
 
 	// 1. Web search blocks (if present and not done)
-	if parsed.WebSearch != nil && !state.WebSearchDone {
+	if dialect.OnlineSearch && parsed.WebSearch != nil && !state.WebSearchDone {
 		wsBlocks, err := convertAnnotationsToWebSearchBlocks(
 			parsed.WebSearch.Annotations,
 			state.CurrentIndex,
+			dialect.Provider,
 		)
 		if err != nil {
 			return nil, err
@@ -373,9 +374,14 @@ func convertThinkingToReasoningDetails(block *llmprovider.Block) []ReasoningDeta
 
 // convertToOpenRouterMessages converts library messages to OpenRouter/OpenAI format.
 func convertToOpenRouterMessages(messages []llmprovider.Message) ([]Message, error) {
+	return convertMessages(messages, OpenRouterDialect)
+}
+
+// convertMessages converts library messages to Chat Completions format for the given dialect.
+func convertMessages(messages []llmprovider.Message, dialect Dialect) ([]Message, error) {
 	// Phase 1: Handle cross-provider server tools by splitting messages
 	// This converts server tools from other providers into synthetic conversation turns
-	processedMessages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, llmprovider.ProviderOpenRouter)
+	processedMessages, err := llmprovider.SplitMessagesAtCrossProviderTool(messages, dialect.Provider)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process cross-provider tools: %w", err)
 	}
@@ -400,6 +406,13 @@ func convertToOpenRouterMessages(messages []llmprovider.Message) ([]Message, err
 			return nil, err
 		}
 
+		// Plain OpenAI-compatible servers reject (or silently mishandle) reasoning_details
+		if !dialect.ReasoningDetails {
+			for j := range openrouterMsg {
+				openrouterMsg[j].ReasoningDetails = nil
+			}
+		}
+
 		// System messages are handled in messages array (unlike Anthropic's separate system param)
 		result = append(result, openrouterMsg...)
 	}
@@ -570,6 +583,11 @@ func convertToolUseToToolCall(block *llmprovider.Block, msgIndex, blockIndex int
 
 // convertFromChatCompletionResponse converts OpenRouter response to library format.
 func convertFromChatCompletionResponse(resp *ChatCompletionResponse) (*llmprovider.GenerateResponse, error) {
+	return ConvertChatCompletionResponse(resp, OpenRouterDialect)
+}
+
+// ConvertChatCompletionResponse converts a Chat Completions response to library format for the given dialect.
+func ConvertChatCompletionResponse(resp *ChatCompletionResponse, dialect Dialect) (*llmprovider.GenerateResponse, error) {
 	if len(resp.Choices) == 0 {
 		return nil, fmt.Errorf("no choices in response")
 	}
@@ -594,7 +612,7 @@ func convertFromChatCompletionResponse(resp *ChatCompletionResponse) (*llmprovid
 	)
 
 	// Build blocks using non-streaming builder
-	messageBlocks, err := buildNonStreamingBlocks(parsed, &state)
+	messageBlocks, err := buildNonStreamingBlocks(parsed, &state, dialect)
 	if err != nil {
 		return nil, err
 	}
@@ -612,7 +630,7 @@ func convertFromChatCompletionResponse(resp *ChatCompletionResponse) (*llmprovid
 	}
 
 	// Convert tool_calls to tool_use blocks
-	providerIDStr := llmprovider.ProviderOpenRouter.String()
+	providerIDStr := dialect.Provider.String()
 	for _, toolCall := range choice.Message.ToolCalls {
 		block, err := convertToolCallToBlock(toolCall, state.CurrentIndex)
 		if err != nil {
@@ -678,13 +696,13 @@ func convertToolCallToBlock(toolCall ToolCall, sequence int) (*llmprovider.Block
 // - Warn users about auto-search behavior
 // - Provide opt-out mechanism
 // - Document which models have this behavior
-func convertAnnotationsToWebSearchBlocks(annotations []Annotation, startSequence int) ([]*llmprovider.Block, error) {
+func convertAnnotationsToWebSearchBlocks(annotations []Annotation, startSequence int, provider llmprovider.ProviderID) ([]*llmprovider.Block, error) {
 	if len(annotations) == 0 {
 		return nil, nil
 	}
 
 	blocks := []*llmprovider.Block{}
-	providerIDStr := string(llmprovider.ProviderOpenRouter)
+	providerIDStr := string(provider)
 
 	// Generate synthetic tool_use_id for web search
 	toolUseID := fmt.Sprintf("or_websearch_%d", time.Now().UnixNano())
@@ -1,7 +1,13 @@
 package openrouter
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/haowjy/meridian-llm-go"
 )
@@ -183,3 +189,85 @@ func TestMapFinishReason(t *testing.T) {
 		})
 	}
 }
+
+// TestStreamChatCompletion_LargeChunk tests that a data line larger than bufio.Scanner's
+// 64KB default doesn't end the stream
+func TestStreamChatCompletion_LargeChunk(t *testing.T) {
+	code := strings.Repeat("x", 200*1024)
+	body := strings.Join([]string{
+		`data: {"id":"gen_1","model":"openai/gpt-4.1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"write_file","arguments":"{\"code\": \"` + code + `\"}"}}]}}]}`,
+		``,
+		`data: {"id":"gen_1","model":"openai/gpt-4.1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		``,
+		`data: [DONE]`,
+		``,
+	}, "\r\n")
+
+	eventChan := make(chan llmprovider.StreamEvent, 100)
+	if err := StreamChatCompletion(context.Background(), strings.NewReader(body), OpenRouterDialect, eventChan); err != nil {
+		t.Fatalf("StreamChatCompletion() error = %v", err)
+	}
+	close(eventChan)
+
+	var toolBlock *llmprovider.Block
+	var metadata *llmprovider.StreamMetadata
+	for event := range eventChan {
+		if event.Block != nil && event.Block.BlockType == llmprovider.BlockTypeToolUse {
+			toolBlock = event.Block
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+
+	if toolBlock == nil {
+		t.Fatal("expected a tool_use block")
+	}
+	if input, _ := toolBlock.GetToolInput(); input["code"] != code {
+		t.Errorf("tool input truncated: got %d bytes", len(input["code"].(string)))
+	}
+	if metadata == nil || metadata.StopReason != "tool_use" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+// TestStream_EarlyBreakClosesConnection tests that breaking out of Stream.All aborts the HTTP request
+func TestStream_EarlyBreakClosesConnection(t *testing.T) {
+	disconnected := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(disconnected)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for {
+			_, _ = io.WriteString(w, `data: {"model":"meta-llama/llama-3.3-70b","choices":[{"index":0,"delta":{"content":"lorem "}}]}`+"\n\n")
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-key", llmprovider.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	stream, err := llmprovider.NewStream(context.Background(), provider, &llmprovider.GenerateRequest{Model: "meta-llama/llama-3.3-70b"})
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+	for _, err := range stream.All() {
+		if err != nil {
+			t.Fatalf("stream error = %v", err)
+		}
+		break
+	}
+
+	select {
+	case <-disconnected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server still streaming after the consumer stopped")
+	}
+}
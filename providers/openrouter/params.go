@@ -144,11 +144,38 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// Dialect describes which OpenRouter extensions the Chat Completions machinery uses.
+// OpenRouterDialect enables all of them; other OpenAI-compatible servers (vLLM, llama.cpp,
+// LM Studio, ...) reuse the same request/response/streaming code with the extensions disabled.
+type Dialect struct {
+	// Provider is stamped on output blocks and used for cross-provider tool handling
+	Provider llmprovider.ProviderID
+
+	// ReasoningDetails sends the reasoning config and replays thinking blocks as reasoning_details
+	ReasoningDetails bool
+
+	// OnlineSearch converts url_citation annotations into synthetic web_search blocks (:online models)
+	OnlineSearch bool
+}
+
+// OpenRouterDialect is the dialect used by the OpenRouter provider itself.
+var OpenRouterDialect = Dialect{
+	Provider:         llmprovider.ProviderOpenRouter,
+	ReasoningDetails: true,
+	OnlineSearch:     true,
+}
+
 // buildChatCompletionRequest constructs an OpenRouter API request from a GenerateRequest.
 // This function is shared between GenerateResponse and StreamResponse to avoid duplication.
 func buildChatCompletionRequest(req *llmprovider.GenerateRequest) (*ChatCompletionRequest, error) {
+	return BuildChatCompletionRequest(req, OpenRouterDialect)
+}
+
+// BuildChatCompletionRequest constructs a Chat Completions request for the given dialect.
+// Stream is always false; callers enable it for streaming requests.
+func BuildChatCompletionRequest(req *llmprovider.GenerateRequest, dialect Dialect) (*ChatCompletionRequest, error) {
 	// Convert library messages to OpenRouter format
-	messages, err := convertToOpenRouterMessages(req.Messages)
+	messages, err := convertMessages(req.Messages, dialect)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert messages: %w", err)
 	}
@@ -211,7 +238,7 @@ func buildChatCompletionRequest(req *llmprovider.GenerateRequest) (*ChatCompleti
 
 	// Reasoning/Thinking - map ThinkingEnabled/ThinkingLevel to OpenRouter's reasoning config
 	// See: https://openrouter.ai/docs/guides/best-practices/reasoning-tokens
-	if params.ThinkingEnabled != nil {
+	if dialect.ReasoningDetails && params.ThinkingEnabled != nil {
 		if *params.ThinkingEnabled && params.ThinkingLevel != nil {
 			// Thinking enabled - map level to effort
 			openrouterReq.Reasoning = &ReasoningConfig{
@@ -33,15 +33,18 @@ type Provider struct {
 }
 
 // NewProvider creates a new OpenRouter provider with the given API key.
-func NewProvider(apiKey string) (*Provider, error) {
+// Options override the default base URL, HTTP client, headers and timeout.
+func NewProvider(apiKey string, opts ...llmprovider.Option) (*Provider, error) {
 	if apiKey == "" {
 		return nil, llmprovider.ErrInvalidAPIKey
 	}
 
+	options := llmprovider.ApplyOptions(opts...)
+
 	return &Provider{
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 120 * time.Second},
-		baseURL:    "https://openrouter.ai/api/v1",
+		httpClient: options.NewHTTPClient(120 * time.Second),
+		baseURL:    options.BaseURLOr("https://openrouter.ai/api/v1"),
 	}, nil
 }
 
@@ -126,7 +129,7 @@ func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.Genera
 
 	// Handle error responses
 	if resp.StatusCode != http.StatusOK {
-		return nil, p.handleErrorResponse(resp, req.Model)
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
 	}
 
 	// Read response body
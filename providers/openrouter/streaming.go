@@ -1,15 +1,16 @@
 package openrouter
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/haowjy/meridian-llm-go"
+	"github.com/haowjy/meridian-llm-go/sse"
 )
 
 // ChatCompletionChunk represents a streaming chunk from OpenRouter.
@@ -44,38 +45,37 @@ type Delta struct {
 // emitStreamingBlocks emits stream events based on parsed delta and state transition.
 // Emits both deltas (for real-time UI) and complete blocks (for persistence).
 func emitStreamingBlocks(
+	ctx context.Context,
 	parsed *ParsedDelta,
 	transition BlockTransition,
 	state *BlockState,
 	thinkingContent *strings.Builder,
 	textContent *strings.Builder,
 	thinkingDetails *[]ReasoningDetail,
+	dialect Dialect,
 	eventChan chan<- llmprovider.StreamEvent,
 ) error {
-	providerIDStr := llmprovider.ProviderOpenRouter.String()
+	providerIDStr := dialect.Provider.String()
 
 	// 1. Emit web search blocks (if present and not done)
-	if parsed.WebSearch != nil && !state.WebSearchDone {
-		fmt.Printf("[DEBUG] processing web search annotations: state.CurrentIndex=%d\n", state.CurrentIndex)
+	if dialect.OnlineSearch && parsed.WebSearch != nil && !state.WebSearchDone {
 		blocks, err := convertAnnotationsToWebSearchBlocks(
 			parsed.WebSearch.Annotations,
 			state.CurrentIndex,
+			dialect.Provider,
 		)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("[DEBUG] emitting %d web search blocks\n", len(blocks))
-		for i, block := range blocks {
-			fmt.Printf("[DEBUG]   web search block %d: type=%s, sequence=%d\n", i, block.BlockType, block.Sequence)
-			eventChan <- llmprovider.StreamEvent{Block: block}
+		for _, block := range blocks {
+			if err := send(ctx, eventChan, llmprovider.StreamEvent{Block: block}); err != nil {
+				return err
+			}
 		}
 
-		oldIndex := state.CurrentIndex
 		state.CurrentIndex += len(blocks)
 		state.WebSearchDone = true
-		fmt.Printf("[DEBUG] updated state.CurrentIndex: %d -> %d (added %d web search blocks)\n",
-			oldIndex, state.CurrentIndex, len(blocks))
 	}
 
 	// 2. Close previous block if transition says so (emit complete block for persistence)
@@ -98,7 +98,9 @@ func emitStreamingBlocks(
 				}
 			}
 
-			eventChan <- llmprovider.StreamEvent{Block: block}
+			if err := send(ctx, eventChan, llmprovider.StreamEvent{Block: block}); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -109,12 +111,14 @@ func emitStreamingBlocks(
 			blockType = llmprovider.BlockTypeThinking
 		}
 
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: transition.NewIndex,
 				BlockType:  &blockType,
 				DeltaType:  llmprovider.DeltaTypeText,
 			},
+		}); err != nil {
+			return err
 		}
 
 		state.CurrentType = transition.NewType
@@ -127,12 +131,14 @@ func emitStreamingBlocks(
 		thinkingContent.WriteString(parsed.Thinking.Text)
 
 		// Emit delta for real-time UI
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: state.CurrentIndex,
 				DeltaType:  llmprovider.DeltaTypeText,
 				TextDelta:  &parsed.Thinking.Text,
 			},
+		}); err != nil {
+			return err
 		}
 	}
 
@@ -142,12 +148,14 @@ func emitStreamingBlocks(
 		textContent.WriteString(parsed.Text.Text)
 
 		// Emit delta for real-time UI
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Delta: &llmprovider.BlockDelta{
 				BlockIndex: state.CurrentIndex,
 				DeltaType:  llmprovider.DeltaTypeText,
 				TextDelta:  &parsed.Text.Text,
 			},
+		}); err != nil {
+			return err
 		}
 	}
 
@@ -199,7 +207,7 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 	// Check for immediate errors
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
-		return nil, p.handleErrorResponse(resp, req.Model)
+		return nil, llmprovider.WithResponseHeaders(p.handleErrorResponse(resp, req.Model), resp.Header)
 	}
 
 	// Create streaming channel
@@ -210,17 +218,20 @@ func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.Generate
 		defer close(eventChan)
 		defer resp.Body.Close()
 
-		if err := p.streamEvents(ctx, resp.Body, eventChan); err != nil {
-			eventChan <- llmprovider.StreamEvent{Error: err}
+		if err := StreamChatCompletion(ctx, resp.Body, OpenRouterDialect, eventChan); err != nil {
+			_ = send(ctx, eventChan, llmprovider.StreamEvent{Error: err})
 		}
 	}()
 
 	return eventChan, nil
 }
 
-// streamEvents reads SSE events and emits library StreamEvents.
-func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventChan chan<- llmprovider.StreamEvent) error {
-	scanner := bufio.NewScanner(body)
+// StreamChatCompletion reads Chat Completions SSE events and emits library StreamEvents.
+// The caller owns body and eventChan; errors are returned rather than sent.
+// Sends give up with ctx.Err() once ctx is done, so a consumer that stops reading
+// doesn't block the caller's goroutine.
+func StreamChatCompletion(ctx context.Context, body io.Reader, dialect Dialect, eventChan chan<- llmprovider.StreamEvent) error {
+	reader := sse.NewReader(body)
 
 	// Initialize block state (SOLID-compliant)
 	state := BlockState{CurrentIndex: 0}
@@ -236,20 +247,16 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 	var stopReason string
 	var usage *Usage // Token usage (captured from last chunk)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ":") {
-			continue
+	for {
+		sseEvent, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-
-		// Parse SSE data line
-		if !strings.HasPrefix(line, "data: ") {
-			continue
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
 		}
 
-		data := strings.TrimPrefix(line, "data: ")
+		data := sseEvent.Data
 
 		// Check for termination
 		if data == "[DONE]" {
@@ -267,7 +274,7 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 				} `json:"error"`
 			}
 			if json.Unmarshal([]byte(data), &errResp) == nil && errResp.Error.Message != "" {
-				return fmt.Errorf("openrouter streaming error: %s", errResp.Error.Message)
+				return fmt.Errorf("%s streaming error: %s", dialect.Provider, errResp.Error.Message)
 			}
 			// Ignore unparseable chunks (might be keep-alive or other messages)
 			continue
@@ -310,24 +317,13 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 
 		// Emit blocks/deltas based on parsed data and transition
 		// Pass accumulators so complete blocks can be built for persistence
-		if err := emitStreamingBlocks(parsed, transition, &state, &thinkingContent, &textContent, thinkingDetails, eventChan); err != nil {
+		if err := emitStreamingBlocks(ctx, parsed, transition, &state, &thinkingContent, &textContent, thinkingDetails, dialect, eventChan); err != nil {
 			return err
 		}
 
 		// Process tool calls delta (keep existing logic - tool calls need accumulation)
 		if len(delta.ToolCalls) > 0 {
 			for _, toolCallDelta := range delta.ToolCalls {
-				// DEBUG: Print each tool call delta with actual index from OpenRouter
-				var indexFromOR string
-				if toolCallDelta.Index != nil {
-					indexFromOR = fmt.Sprintf("%d", *toolCallDelta.Index)
-				} else {
-					indexFromOR = "nil"
-				}
-				fmt.Printf("[DEBUG] processing tool call delta: openrouter_index=%s, id=%q, name=%q, args_len=%d, args_preview=%q\n",
-					indexFromOR, toolCallDelta.ID, toolCallDelta.Function.Name,
-					len(toolCallDelta.Function.Arguments), truncateString(toolCallDelta.Function.Arguments, 50))
-
 				// Determine the map index to use (priority order):
 				// 1. Use Index from OpenRouter if present (most reliable)
 				// 2. Find existing entry by ID
@@ -336,15 +332,12 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 				if toolCallDelta.Index != nil {
 					// Use actual index from OpenRouter response
 					idx = *toolCallDelta.Index
-					fmt.Printf("[DEBUG] using OpenRouter index: %d\n", idx)
 				} else if existingIdx, exists := findToolCallIndex(toolCallsMap, toolCallDelta.ID); exists {
 					// Find existing by ID
 					idx = existingIdx
-					fmt.Printf("[DEBUG] found existing tool call by ID: id=%q, map_index=%d\n", toolCallDelta.ID, idx)
 				} else {
 					// Fallback: create new entry
 					idx = len(toolCallsMap)
-					fmt.Printf("[DEBUG] creating new tool call entry (fallback): id=%q, map_index=%d\n", toolCallDelta.ID, idx)
 				}
 
 				acc, exists := toolCallsMap[idx]
@@ -355,9 +348,7 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 
 					blockType := llmprovider.BlockTypeToolUse
 					blockIndex := state.CurrentIndex + 1 + idx
-					fmt.Printf("[DEBUG] emitting tool call START: map_index=%d, blockIndex=%d, state.CurrentIndex=%d, id=%q, name=%q\n",
-						idx, blockIndex, state.CurrentIndex, toolCallDelta.ID, toolCallDelta.Function.Name)
-					eventChan <- llmprovider.StreamEvent{
+					if err := send(ctx, eventChan, llmprovider.StreamEvent{
 						Delta: &llmprovider.BlockDelta{
 							BlockIndex:   blockIndex,
 							BlockType:    &blockType,
@@ -365,6 +356,8 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 							ToolCallID:   &toolCallDelta.ID,
 							ToolCallName: &toolCallDelta.Function.Name,
 						},
+					}); err != nil {
+						return err
 					}
 				}
 
@@ -376,22 +369,18 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 					acc.Name = toolCallDelta.Function.Name
 				}
 				if toolCallDelta.Function.Arguments != "" {
-					prevLength := acc.Arguments.Len()
 					acc.Arguments.WriteString(toolCallDelta.Function.Arguments)
-					newLength := acc.Arguments.Len()
-
-					// DEBUG: Print Arguments accumulation
-					fmt.Printf("[DEBUG] accumulated tool call arguments: id=%q, map_index=%d, prev_len=%d, chunk_len=%d, new_total=%d, preview=%q\n",
-						acc.ID, idx, prevLength, len(toolCallDelta.Function.Arguments), newLength, truncateString(acc.Arguments.String(), 100))
 
 					// Emit input JSON delta
 					blockIndex := state.CurrentIndex + 1 + idx
-					eventChan <- llmprovider.StreamEvent{
+					if err := send(ctx, eventChan, llmprovider.StreamEvent{
 						Delta: &llmprovider.BlockDelta{
 							BlockIndex:  blockIndex,
 							DeltaType:   llmprovider.DeltaTypeJSON,
 							JSONDelta:   &toolCallDelta.Function.Arguments,
 						},
+					}); err != nil {
+						return err
 					}
 				}
 			}
@@ -403,14 +392,10 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading stream: %w", err)
-	}
-
 	// Web search blocks are already emitted during streaming
 	// Emit complete blocks for thinking/text (for persistence) before tool calls
 
-	providerIDStr := llmprovider.ProviderOpenRouter.String()
+	providerIDStr := dialect.Provider.String()
 
 	// Emit complete thinking block if it was started (for persistence)
 	if state.CurrentType == "thinking" && thinkingContent.Len() > 0 {
@@ -430,57 +415,43 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 			}
 		}
 
-		eventChan <- llmprovider.StreamEvent{Block: block}
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{Block: block}); err != nil {
+			return err
+		}
 		state.CurrentIndex++
 	}
 
 	// Emit complete text block if it was started (for persistence)
 	if state.CurrentType == "text" && textContent.Len() > 0 {
 		text := textContent.String()
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Block: &llmprovider.Block{
 				BlockType:   llmprovider.BlockTypeText,
 				Sequence:    state.CurrentIndex,
 				TextContent: &text,
 				Provider:    &providerIDStr,
 			},
+		}); err != nil {
+			return err
 		}
 		state.CurrentIndex++
 	}
 
 	// Tool call blocks (emit in order)
-	// DEBUG: Print toolCallsMap state before finalization
-	fmt.Printf("[DEBUG] finalizing tool calls: total=%d, state.CurrentIndex=%d\n", len(toolCallsMap), state.CurrentIndex)
-
-	// DEBUG: Dump entire toolCallsMap to see what indices exist
-	fmt.Printf("[DEBUG] toolCallsMap contents:\n")
-	for mapIdx, mapAcc := range toolCallsMap {
-		fmt.Printf("[DEBUG]   index=%d: id=%q, name=%q, args_len=%d\n",
-			mapIdx, mapAcc.ID, mapAcc.Name, mapAcc.Arguments.Len())
-	}
-
 	for idx := 0; idx < len(toolCallsMap); idx++ {
 		acc, exists := toolCallsMap[idx]
 		if !exists {
-			fmt.Printf("[DEBUG] WARNING: gap in toolCallsMap at index %d (this should not happen!)\n", idx)
 			continue
 		}
 
-		// DEBUG: Print accumulated tool call before JSON parsing
 		argStr := acc.Arguments.String()
-		fmt.Printf("[DEBUG] parsing accumulated tool call arguments: index=%d, id=%q, name=%q, args_len=%d, args_full=%q\n",
-			idx, acc.ID, acc.Name, acc.Arguments.Len(), argStr)
 
 		// Parse accumulated arguments
 		input := make(map[string]interface{})
 		if acc.Arguments.Len() > 0 {
 			if err := json.Unmarshal([]byte(argStr), &input); err != nil {
-				fmt.Printf("[ERROR] failed to parse tool call arguments: index=%d, id=%q, name=%q, malformed_json=%q, error=%v\n",
-					idx, acc.ID, acc.Name, argStr, err)
 				return fmt.Errorf("invalid tool call arguments at index %d: received malformed JSON %q - %w", idx, argStr, err)
 			}
-			fmt.Printf("[DEBUG] successfully parsed tool call arguments: index=%d, id=%q, name=%q, parsed_input=%v\n",
-				idx, acc.ID, acc.Name, input)
 		}
 
 		content := map[string]interface{}{
@@ -492,7 +463,7 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 		// All OpenRouter tools are backend-side (executed by our backend)
 		executionSide := llmprovider.ExecutionSideServer
 
-		eventChan <- llmprovider.StreamEvent{
+		if err := send(ctx, eventChan, llmprovider.StreamEvent{
 			Block: &llmprovider.Block{
 				BlockType:     llmprovider.BlockTypeToolUse,
 				Sequence:      state.CurrentIndex,
@@ -500,6 +471,8 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 				ExecutionSide: &executionSide,
 				Provider:      &providerIDStr,
 			},
+		}); err != nil {
+			return err
 		}
 		state.CurrentIndex++
 	}
@@ -517,13 +490,25 @@ func (p *Provider) streamEvents(ctx context.Context, body io.ReadCloser, eventCh
 	}
 	// Note: If usage is nil, InputTokens and OutputTokens default to 0
 
-	eventChan <- llmprovider.StreamEvent{
+	if err := send(ctx, eventChan, llmprovider.StreamEvent{
 		Metadata: metadata,
+	}); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// send delivers an event, or returns ctx.Err() if the consumer has cancelled.
+func send(ctx context.Context, eventChan chan<- llmprovider.StreamEvent, event llmprovider.StreamEvent) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case eventChan <- event:
+		return nil
+	}
+}
+
 // accumulatedToolCall holds state for accumulating a tool call during streaming.
 type accumulatedToolCall struct {
 	ID        string
@@ -543,15 +528,3 @@ func findToolCallIndex(toolCallsMap map[int]*accumulatedToolCall, id string) (in
 	}
 	return 0, false
 }
-
-// truncateString truncates a string to maxLen characters, adding "..." if truncated.
-// Used for debug logging to prevent excessive log output.
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	if maxLen <= 3 {
-		return "..."
-	}
-	return s[:maxLen-3] + "..."
-}
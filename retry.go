@@ -0,0 +1,228 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MetadataAttempts is the response metadata key set by RetryProvider:
+// the number of attempts made (1 = succeeded without retrying).
+const MetadataAttempts = "attempts"
+
+// RetryConfig controls RetryProvider's exponential backoff.
+//
+// The delay before retry n is BaseDelay * Multiplier^(n-1), capped at MaxDelay, with up
+// to Jitter of it randomly subtracted. A server-requested delay (ProviderError.RetryAfter)
+// is used instead when longer; if it exceeds MaxDelay the error is returned immediately.
+type RetryConfig struct {
+	MaxAttempts int           // Total attempts, including the first
+	BaseDelay   time.Duration // Delay before the first retry
+	MaxDelay    time.Duration // Upper bound for a single delay
+	Multiplier  float64       // Backoff growth factor
+	Jitter      float64       // Fraction of each delay that is randomized (0 = none, 1 = full jitter)
+}
+
+// DefaultRetryConfig returns the defaults used for zero-valued RetryConfig fields.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+// withDefaults fills zero-valued fields from DefaultRetryConfig.
+// Jitter is left as is, so 0 disables it.
+func (c RetryConfig) withDefaults() RetryConfig {
+	defaults := DefaultRetryConfig()
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = defaults.BaseDelay
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = defaults.MaxDelay
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = defaults.Multiplier
+	}
+	c.Jitter = math.Max(0, math.Min(1, c.Jitter))
+	return c
+}
+
+// delay returns the backoff before retrying after the given (1-based) attempt.
+func (c RetryConfig) delay(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := float64(c.BaseDelay) * math.Pow(c.Multiplier, float64(attempt-1))
+	backoff = math.Min(backoff, float64(c.MaxDelay))
+	backoff -= backoff * c.Jitter * rand.Float64()
+
+	delay := time.Duration(backoff)
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// RetryProvider retries retryable failures (per IsRetryable) of the wrapped provider.
+//
+//	provider := llmprovider.NewRetryProvider(anthropicProvider, llmprovider.DefaultRetryConfig())
+//
+// Streams are retried only if they fail before the first event; once content has been
+// delivered, later errors are passed through. Successful responses (and final stream
+// metadata) record the number of attempts under MetadataAttempts.
+//
+// Providers built on SDKs with their own retries (Anthropic) should be constructed with
+// WithMaxRetries(0) to avoid retrying twice. RetryMiddleware adds it to a Chain.
+type RetryProvider struct {
+	provider Provider
+	config   RetryConfig
+}
+
+// NewRetryProvider wraps provider with retries. Zero-valued config fields take their defaults.
+func NewRetryProvider(provider Provider, config RetryConfig) *RetryProvider {
+	return &RetryProvider{provider: provider, config: config.withDefaults()}
+}
+
+// GenerateResponse calls the wrapped provider, retrying retryable errors.
+func (r *RetryProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := r.provider.GenerateResponse(ctx, req)
+		if err == nil {
+			resp.ResponseMetadata = withMetadataValue(resp.ResponseMetadata, MetadataAttempts, attempt)
+			return resp, nil
+		}
+
+		if stopErr := r.backoff(ctx, attempt, err); stopErr != nil {
+			return nil, stopErr
+		}
+	}
+}
+
+// StreamResponse starts a stream, retrying retryable errors that occur before the first event.
+func (r *RetryProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	for attempt := 1; ; attempt++ {
+		events, err := r.provider.StreamResponse(ctx, req)
+		if err == nil {
+			var first StreamEvent
+			first, err = firstStreamEvent(ctx, events)
+			if err == nil {
+				return forwardStream(ctx, first, events, func(metadata *StreamMetadata) {
+					metadata.ResponseMetadata = withMetadataValue(metadata.ResponseMetadata, MetadataAttempts, attempt)
+				}), nil
+			}
+		}
+
+		if stopErr := r.backoff(ctx, attempt, err); stopErr != nil {
+			return nil, stopErr
+		}
+	}
+}
+
+// Name returns the wrapped provider's name.
+func (r *RetryProvider) Name() ProviderID {
+	return r.provider.Name()
+}
+
+// SupportsModel reports whether the wrapped provider supports the model.
+func (r *RetryProvider) SupportsModel(model string) bool {
+	return r.provider.SupportsModel(model)
+}
+
+// backoff waits before the next attempt. It returns the error to give up with when
+// err is not retryable, attempts are exhausted, the server asks for a longer wait than
+// MaxDelay, or ctx is done.
+func (r *RetryProvider) backoff(ctx context.Context, attempt int, err error) error {
+	if ctx.Err() != nil || !IsRetryable(err) {
+		return err
+	}
+	if attempt >= r.config.MaxAttempts {
+		return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+	}
+
+	var retryAfter time.Duration
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		retryAfter = providerErr.RetryAfter
+	}
+	if retryAfter > r.config.MaxDelay {
+		return fmt.Errorf("retry after %v exceeds max delay %v: %w", retryAfter, r.config.MaxDelay, err)
+	}
+
+	timer := time.NewTimer(r.config.delay(attempt, retryAfter))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withMetadataValue returns a copy of metadata with key set to value.
+func withMetadataValue(metadata map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	result[key] = value
+	return result
+}
+
+// anthropicRateLimits are the limits reported in anthropic-ratelimit-<limit>-remaining/-reset headers.
+var anthropicRateLimits = []string{"requests", "tokens", "input-tokens", "output-tokens"}
+
+// WithResponseHeaders records HTTP response headers on the ProviderError in err's chain
+// and sets its RetryAfter from them. Other errors are returned unchanged.
+//
+// RetryAfter is taken from retry-after-ms, Retry-After (seconds or HTTP date), or
+// else the latest reset time of any exhausted anthropic-ratelimit-* limit.
+func WithResponseHeaders(err error, header http.Header) error {
+	var providerErr *ProviderError
+	if header == nil || !errors.As(err, &providerErr) {
+		return err
+	}
+
+	providerErr.Headers = header
+	if retryAfter := retryAfterFromHeaders(header, time.Now()); retryAfter > 0 {
+		providerErr.RetryAfter = retryAfter
+	}
+	return err
+}
+
+// retryAfterFromHeaders returns the server-requested delay, or 0 if none.
+func retryAfterFromHeaders(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(math.Max(0, seconds) * float64(time.Second))
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(0, at.Sub(now))
+		}
+	}
+
+	var delay time.Duration
+	for _, limit := range anthropicRateLimits {
+		if header.Get("anthropic-ratelimit-"+limit+"-remaining") != "0" {
+			continue
+		}
+		reset, err := time.Parse(time.RFC3339, header.Get("anthropic-ratelimit-"+limit+"-reset"))
+		if err == nil && reset.Sub(now) > delay {
+			delay = reset.Sub(now)
+		}
+	}
+	return delay
+}
@@ -0,0 +1,151 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fastRetries keeps test backoff short and deterministic
+var fastRetries = RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+func TestRetryProvider_GenerateResponse(t *testing.T) {
+	var failures int
+	flaky := &mockProvider{name: ProviderAnthropic, prefix: "claude-"}
+	flaky.generate = func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		if failures < 2 {
+			failures++
+			return nil, NewProviderError("anthropic", 503, "overloaded", ErrProviderUnavailable)
+		}
+		return &GenerateResponse{Model: req.Model, StopReason: "end_turn"}, nil
+	}
+
+	resp, err := NewRetryProvider(flaky, fastRetries).GenerateResponse(context.Background(), &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+	if resp.ResponseMetadata[MetadataAttempts] != 3 {
+		t.Errorf("expected 3 attempts recorded, got %v", resp.ResponseMetadata[MetadataAttempts])
+	}
+}
+
+func TestRetryProvider_StopsOnNonRetryableAndExhaustion(t *testing.T) {
+	invalid := &mockProvider{
+		name: ProviderOpenAI,
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, NewProviderError("openai", 400, "bad request", ErrInvalidRequest)
+		},
+	}
+	_, err := NewRetryProvider(invalid, fastRetries).GenerateResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if !errors.Is(err, ErrInvalidRequest) || invalid.callCount() != 1 {
+		t.Errorf("expected a single attempt for non-retryable error, got %d (%v)", invalid.callCount(), err)
+	}
+
+	rateLimited := &mockProvider{
+		name: ProviderOpenAI,
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, NewProviderError("openai", 429, "slow down", ErrRateLimited)
+		},
+	}
+	_, err = NewRetryProvider(rateLimited, fastRetries).GenerateResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if !errors.Is(err, ErrRateLimited) || rateLimited.callCount() != 3 {
+		t.Errorf("expected 3 attempts then ErrRateLimited, got %d (%v)", rateLimited.callCount(), err)
+	}
+
+	// Server asks for longer than MaxDelay: give up instead of waiting
+	tooLong := &mockProvider{
+		name: ProviderAnthropic,
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return nil, WithResponseHeaders(NewProviderError("anthropic", 429, "slow down", ErrRateLimited), http.Header{"Retry-After": {"60"}})
+		},
+	}
+	_, err = NewRetryProvider(tooLong, fastRetries).GenerateResponse(context.Background(), &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if !errors.Is(err, ErrRateLimited) || tooLong.callCount() != 1 {
+		t.Errorf("expected immediate failure for long Retry-After, got %d (%v)", tooLong.callCount(), err)
+	}
+}
+
+func TestRetryProvider_StreamFailsBeforeFirstEvent(t *testing.T) {
+	var failed bool
+	flaky := &mockProvider{name: ProviderAnthropic}
+	flaky.stream = func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+		if !failed {
+			failed = true
+			return eventsChannel(StreamEvent{Error: NewProviderError("anthropic", 503, "unavailable", ErrProviderUnavailable)}), nil
+		}
+		return eventsChannel(textStreamEvents(req.Model, "ok")...), nil
+	}
+
+	events, err := NewRetryProvider(flaky, fastRetries).StreamResponse(context.Background(), &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+
+	var metadata *StreamMetadata
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("unexpected stream error: %v", event.Error)
+		}
+		if event.Metadata != nil {
+			metadata = event.Metadata
+		}
+	}
+	if metadata == nil || metadata.ResponseMetadata[MetadataAttempts] != 2 {
+		t.Errorf("expected 2 attempts in final metadata, got %+v", metadata)
+	}
+}
+
+func TestRetryAfterFromHeaders(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"seconds", http.Header{"Retry-After": {"7"}}, 7 * time.Second},
+		{"http date", http.Header{"Retry-After": {now.Add(30 * time.Second).Format(http.TimeFormat)}}, 30 * time.Second},
+		{"milliseconds preferred", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"1500"}}, 1500 * time.Millisecond},
+		{"anthropic exhausted limits", http.Header{
+			"Anthropic-Ratelimit-Requests-Remaining": {"0"},
+			"Anthropic-Ratelimit-Requests-Reset":     {now.Add(5 * time.Second).Format(time.RFC3339)},
+			"Anthropic-Ratelimit-Tokens-Remaining":   {"0"},
+			"Anthropic-Ratelimit-Tokens-Reset":       {now.Add(12 * time.Second).Format(time.RFC3339)},
+		}, 12 * time.Second},
+		{"anthropic limit not exhausted", http.Header{
+			"Anthropic-Ratelimit-Requests-Remaining": {"10"},
+			"Anthropic-Ratelimit-Requests-Reset":     {now.Add(5 * time.Second).Format(time.RFC3339)},
+		}, 0},
+		{"none", http.Header{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfterFromHeaders(tt.header, now); got != tt.want {
+				t.Errorf("retryAfterFromHeaders() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryConfig_Delay(t *testing.T) {
+	config := RetryConfig{BaseDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}.withDefaults()
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		if got := config.delay(attempt, 0); got != want {
+			t.Errorf("delay(%d) = %v, expected %v", attempt, got, want)
+		}
+	}
+	if got := config.delay(1, 3*time.Second); got != 3*time.Second {
+		t.Errorf("expected Retry-After to override shorter backoff, got %v", got)
+	}
+
+	config.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got := config.delay(2, 0); got < time.Second || got > 2*time.Second {
+			t.Fatalf("jittered delay %v outside [1s, 2s]", got)
+		}
+	}
+}
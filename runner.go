@@ -0,0 +1,250 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultMaxSteps is the number of model calls a run may make when RunnerConfig.MaxSteps is zero.
+const DefaultMaxSteps = 10
+
+// Errors returned when a run stops at one of its limits. The run's result so far is
+// returned with them; its Messages end with the last tool results, so the conversation
+// can be continued with another run.
+var (
+	ErrMaxSteps  = errors.New("llmprovider: agent run exceeded max steps")
+	ErrMaxTokens = errors.New("llmprovider: agent run exceeded max tokens")
+)
+
+// ToolHandler executes a server-side tool call with the tool_use block's input. The
+// returned text is sent back to the model as the tool_result; an error is sent back as
+// an is_error tool_result with the error message, so the model can recover.
+type ToolHandler func(ctx context.Context, input map[string]interface{}) (string, error)
+
+// RunnerConfig limits a Runner's runs.
+type RunnerConfig struct {
+	MaxSteps  int // Model calls per run (DefaultMaxSteps if zero)
+	MaxTokens int // Input plus output tokens over all steps (unlimited if zero)
+
+	// ValidateInput checks tool input against the tool's schema in req.Params.Tools before
+	// calling its handler. Invalid input is answered with an is_error tool_result listing
+	// the violations (see NewToolErrorResult) instead, so the model can correct the call.
+	ValidateInput bool
+
+	// Tools controls the concurrency and timeouts of each turn's tool calls.
+	Tools ToolExecutorConfig
+}
+
+// Runner runs the agent loop for server-side tools: it calls the model, executes the
+// tool_use blocks it returns with Go handlers, appends the assistant message and a user
+// message of tool_result blocks, and calls the model again until it stops using tools:
+//
+//	runner := llmprovider.NewRunner(provider, map[string]llmprovider.ToolHandler{
+//	    "get_weather": func(ctx context.Context, input map[string]interface{}) (string, error) {
+//	        return weather.Lookup(ctx, input["city"].(string))
+//	    },
+//	}, llmprovider.RunnerConfig{MaxSteps: 5})
+//
+//	result, err := runner.Run(ctx, req) // req.Params.Tools declares get_weather
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(*result.Response.Blocks[0].TextContent)
+//
+// Only backend-side tool_use blocks (ExecutionSideServer, or unset) are executed;
+// provider-side tools such as web_search have already run. If a response calls a
+// client-side tool or a tool without a handler, the run ends there and returns the
+// response so the caller can execute the calls. The calls of a turn run concurrently on
+// a ToolExecutor (see RunnerConfig.Tools); their results keep the calls' order.
+type Runner struct {
+	provider Provider
+	executor *ToolExecutor
+	config   RunnerConfig
+}
+
+// RunResult is the outcome of a run.
+type RunResult struct {
+	Response     *GenerateResponse // The last model response
+	Messages     []Message         // The request's messages, then each step's assistant and tool_result messages
+	Steps        int               // Model calls made
+	InputTokens  int               // Input tokens over all steps
+	OutputTokens int               // Output tokens over all steps
+}
+
+// RunEvent is an event of a streaming run. Model events are forwarded as they arrive,
+// each step ending with its Metadata event; the tool_result blocks of a step follow it,
+// in call order.
+// The last event carries the Result, with Error set if the run failed or hit a limit.
+type RunEvent struct {
+	StreamEvent
+
+	Step       int        // Model call the event belongs to, from 1
+	ToolResult *Block     // A tool_result produced by a handler
+	Result     *RunResult // Set on the final event
+}
+
+// NewRunner creates a Runner that executes tools with handlers, keyed by tool name.
+func NewRunner(provider Provider, handlers map[string]ToolHandler, config RunnerConfig) *Runner {
+	if config.MaxSteps <= 0 {
+		config.MaxSteps = DefaultMaxSteps
+	}
+	return &Runner{provider: provider, executor: NewToolExecutor(handlers, config.Tools), config: config}
+}
+
+// Run runs the loop with blocking GenerateResponse calls. The result so far is returned
+// along with any error, including ErrMaxSteps, ErrMaxTokens and context errors.
+// req is not modified.
+func (r *Runner) Run(ctx context.Context, req *GenerateRequest) (*RunResult, error) {
+	generate := func(ctx context.Context, req *GenerateRequest, step int) (*GenerateResponse, error) {
+		return r.provider.GenerateResponse(ctx, req)
+	}
+	return r.run(ctx, req, generate, nil)
+}
+
+// Stream runs the loop with StreamResponse calls, forwarding every step's events.
+// Errors returned before the first stream starts are returned directly; later errors,
+// including stream error events, end the run with a final event carrying both the
+// Error and the Result so far. If ctx is cancelled the channel may close without it.
+func (r *Runner) Stream(ctx context.Context, req *GenerateRequest) (<-chan RunEvent, error) {
+	first, err := r.provider.StreamResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan RunEvent, 10) // Buffered to prevent blocking
+
+	send := func(ctx context.Context, event RunEvent) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- event:
+			return nil
+		}
+	}
+
+	generate := func(ctx context.Context, req *GenerateRequest, step int) (*GenerateResponse, error) {
+		events := first
+		first = nil
+		if events == nil {
+			var err error
+			if events, err = r.provider.StreamResponse(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+
+		acc := NewAccumulator()
+		for event := range events {
+			if err := acc.Add(event); err != nil {
+				go drainStream(events)
+				return nil, err // Reported once, on the final event
+			}
+			if err := send(ctx, RunEvent{StreamEvent: event, Step: step}); err != nil {
+				go drainStream(events)
+				return nil, err
+			}
+		}
+		return acc.Response()
+	}
+
+	toolResult := func(ctx context.Context, step int, block *Block) error {
+		return send(ctx, RunEvent{Step: step, ToolResult: block})
+	}
+
+	go func() {
+		defer close(out)
+
+		result, err := r.run(ctx, req, generate, toolResult)
+		if first != nil {
+			go drainStream(first) // Cancelled before the first step began
+		}
+
+		_ = send(ctx, RunEvent{StreamEvent: StreamEvent{Error: err}, Step: result.Steps, Result: result})
+	}()
+
+	return out, nil
+}
+
+// run is the agent loop. generate makes one model call; toolResult, if set, is called
+// with each step's tool_result blocks, in order, once they are all done.
+func (r *Runner) run(
+	ctx context.Context,
+	req *GenerateRequest,
+	generate func(ctx context.Context, req *GenerateRequest, step int) (*GenerateResponse, error),
+	toolResult func(ctx context.Context, step int, block *Block) error,
+) (*RunResult, error) {
+	result := &RunResult{Messages: append([]Message(nil), req.Messages...)}
+	tools := r.tools(req)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if result.Steps >= r.config.MaxSteps {
+			return result, ErrMaxSteps
+		}
+
+		stepReq := *req
+		stepReq.Messages = result.Messages
+		resp, err := generate(ctx, &stepReq, result.Steps+1)
+		if err != nil {
+			return result, err
+		}
+
+		result.Steps++
+		result.Response = resp
+		result.InputTokens += resp.InputTokens
+		result.OutputTokens += resp.OutputTokens
+		if len(resp.Blocks) > 0 {
+			result.Messages = append(result.Messages, Message{Role: "assistant", Blocks: resp.Blocks})
+		}
+
+		calls := r.toolCalls(resp.Blocks)
+		if len(calls) == 0 {
+			return result, nil
+		}
+
+		results := ToolResultBlocks(r.executor.execute(ctx, calls, tools))
+		if toolResult != nil {
+			for _, block := range results {
+				if err := toolResult(ctx, result.Steps, block); err != nil {
+					return result, err
+				}
+			}
+		}
+		result.Messages = append(result.Messages, Message{Role: "user", Blocks: results})
+
+		if r.config.MaxTokens > 0 && result.InputTokens+result.OutputTokens >= r.config.MaxTokens {
+			return result, ErrMaxTokens
+		}
+	}
+}
+
+// toolCalls returns the backend-side tool_use blocks of a response, or nil if there are
+// none or the caller has to execute one of the calls (a client-side tool, or a tool
+// without a handler).
+func (r *Runner) toolCalls(blocks []*Block) []*Block {
+	var calls []*Block
+	for _, block := range blocks {
+		if !block.IsToolUseBlock() || block.IsProviderSideTool() {
+			continue
+		}
+		name, _ := block.GetToolName()
+		if !r.executor.Has(name) || block.IsClientSideTool() {
+			return nil
+		}
+		calls = append(calls, block)
+	}
+	return calls
+}
+
+// tools returns the request's tool definitions by name, if input is validated.
+func (r *Runner) tools(req *GenerateRequest) map[string]*Tool {
+	if !r.config.ValidateInput || req.Params == nil {
+		return nil
+	}
+	tools := make(map[string]*Tool, len(req.Params.Tools))
+	for i := range req.Params.Tools {
+		tools[req.Params.Tools[i].Function.Name] = &req.Params.Tools[i]
+	}
+	return tools
+}
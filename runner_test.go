@@ -0,0 +1,228 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// toolUseBlock returns a tool_use block executed on the given side.
+func toolUseBlock(id, name string, input map[string]interface{}, side ExecutionSide) *Block {
+	block := &Block{
+		BlockType: BlockTypeToolUse,
+		Content:   map[string]interface{}{"tool_use_id": id, "tool_name": name, "input": input},
+	}
+	block.SetExecutionSide(side)
+	return block
+}
+
+// agentProvider answers with tool calls until it has seen a tool_result, then with text.
+func agentProvider(calls ...*Block) *mockProvider {
+	generate := func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		if last.Blocks[0].IsToolResultBlock() {
+			return &GenerateResponse{
+				Blocks:       []*Block{{BlockType: BlockTypeText, TextContent: stringPtr("Sunny in Paris")}},
+				Model:        req.Model,
+				InputTokens:  100,
+				OutputTokens: 10,
+				StopReason:   "end_turn",
+			}, nil
+		}
+		return &GenerateResponse{Blocks: calls, Model: req.Model, InputTokens: 50, OutputTokens: 20, StopReason: "tool_use"}, nil
+	}
+	return &mockProvider{
+		name:     ProviderAnthropic,
+		generate: generate,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			resp, err := generate(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return StreamFromResponse(ctx, resp), nil
+		},
+	}
+}
+
+func userRequest(text string) *GenerateRequest {
+	return &GenerateRequest{
+		Model:    "claude-sonnet-4-5",
+		Messages: []Message{{Role: "user", Blocks: []*Block{{BlockType: BlockTypeText, TextContent: &text}}}},
+	}
+}
+
+var weatherHandlers = map[string]ToolHandler{
+	"get_weather": func(ctx context.Context, input map[string]interface{}) (string, error) {
+		return "sunny in " + input["city"].(string), nil
+	},
+	"get_forecast": func(ctx context.Context, input map[string]interface{}) (string, error) {
+		return "", errors.New("forecast service unavailable")
+	},
+}
+
+func TestRunner_Run(t *testing.T) {
+	mock := agentProvider(
+		&Block{BlockType: BlockTypeText, TextContent: stringPtr("Let me check.")},
+		toolUseBlock("srvtoolu_1", "web_search", map[string]interface{}{"query": "paris"}, ExecutionSideProvider),
+		toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer),
+		toolUseBlock("toolu_2", "get_forecast", map[string]interface{}{"city": "Paris"}, ExecutionSideServer),
+	)
+	req := userRequest("Weather in Paris?")
+
+	result, err := NewRunner(mock, weatherHandlers, RunnerConfig{}).Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.Steps != 2 || result.InputTokens != 150 || result.OutputTokens != 30 {
+		t.Errorf("steps = %d, tokens = %d/%d", result.Steps, result.InputTokens, result.OutputTokens)
+	}
+	if result.Response.StopReason != "end_turn" || len(result.Messages) != 4 || len(req.Messages) != 1 {
+		t.Fatalf("unexpected result: %d messages, request has %d", len(result.Messages), len(req.Messages))
+	}
+
+	results := result.Messages[2]
+	want := []*Block{
+		{BlockType: BlockTypeToolResult, Sequence: 0, TextContent: stringPtr("sunny in Paris"),
+			Content: map[string]interface{}{"tool_use_id": "toolu_1", "tool_name": "get_weather", "is_error": false}},
+		{BlockType: BlockTypeToolResult, Sequence: 1, TextContent: stringPtr("forecast service unavailable"),
+			Content: map[string]interface{}{"tool_use_id": "toolu_2", "tool_name": "get_forecast", "is_error": true}},
+	}
+	if results.Role != "user" || !reflect.DeepEqual(results.Blocks, want) {
+		t.Errorf("tool results = %+v", results.Blocks)
+	}
+	if result.Messages[1].Role != "assistant" || len(result.Messages[1].Blocks) != 4 {
+		t.Errorf("expected the assistant's blocks before the results, got %+v", result.Messages[1])
+	}
+}
+
+func TestRunner_StopsAtUnhandledTool(t *testing.T) {
+	mock := agentProvider(
+		toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer),
+		toolUseBlock("toolu_2", "pick_file", map[string]interface{}{}, ExecutionSideClient),
+	)
+
+	result, err := NewRunner(mock, weatherHandlers, RunnerConfig{}).Run(context.Background(), userRequest("Hi"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Steps != 1 || result.Response.StopReason != "tool_use" || len(result.Messages) != 2 {
+		t.Errorf("expected the run to return the client tool call, got %d steps", result.Steps)
+	}
+}
+
+func TestRunner_Limits(t *testing.T) {
+	call := toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer)
+	looping := &mockProvider{
+		name: ProviderOpenAI,
+		generate: func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+			return &GenerateResponse{Blocks: []*Block{call}, InputTokens: 50, OutputTokens: 20, StopReason: "tool_use"}, nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		config    RunnerConfig
+		wantErr   error
+		wantSteps int
+	}{
+		{"max steps", RunnerConfig{MaxSteps: 3}, ErrMaxSteps, 3},
+		{"max tokens", RunnerConfig{MaxTokens: 100}, ErrMaxTokens, 2},
+		{"default max steps", RunnerConfig{}, ErrMaxSteps, DefaultMaxSteps},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewRunner(looping, weatherHandlers, tt.config).Run(context.Background(), userRequest("Hi"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if result.Steps != tt.wantSteps {
+				t.Errorf("steps = %d, want %d", result.Steps, tt.wantSteps)
+			}
+			if last := result.Messages[len(result.Messages)-1]; !last.Blocks[0].IsToolResultBlock() {
+				t.Error("expected the messages to end with the tool results")
+			}
+		})
+	}
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handlers := map[string]ToolHandler{
+		"get_weather": func(ctx context.Context, input map[string]interface{}) (string, error) {
+			cancel()
+			return "", ctx.Err()
+		},
+	}
+	mock := agentProvider(toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer))
+
+	result, err := NewRunner(mock, handlers, RunnerConfig{}).Run(ctx, userRequest("Hi"))
+	if !errors.Is(err, context.Canceled) || result.Steps != 1 || mock.callCount() != 1 {
+		t.Errorf("expected the run to stop after cancellation, got %v after %d steps", err, result.Steps)
+	}
+}
+
+func TestRunner_Stream(t *testing.T) {
+	mock := agentProvider(toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer))
+
+	events, err := NewRunner(mock, weatherHandlers, RunnerConfig{}).Stream(context.Background(), userRequest("Hi"))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	var metadata, toolResults []int
+	var text string
+	var final RunEvent
+	for event := range events {
+		switch {
+		case event.Metadata != nil:
+			metadata = append(metadata, event.Step)
+		case event.ToolResult != nil:
+			toolResults = append(toolResults, event.Step)
+		case event.Delta != nil && event.Delta.TextDelta != nil:
+			text += *event.Delta.TextDelta
+		}
+		final = event
+	}
+
+	if final.Error != nil || final.Result == nil || final.Result.Steps != 2 {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	if !reflect.DeepEqual(metadata, []int{1, 2}) || !reflect.DeepEqual(toolResults, []int{1}) {
+		t.Errorf("metadata steps = %v, tool result steps = %v", metadata, toolResults)
+	}
+	if text != "Sunny in Paris" || *final.Result.Response.Blocks[0].TextContent != text {
+		t.Errorf("text = %q", text)
+	}
+	if input, _ := final.Result.Messages[1].Blocks[0].GetToolInput(); input["city"] != "Paris" {
+		t.Errorf("expected the streamed tool input in the messages, got %v", input)
+	}
+}
+
+func TestRunner_StreamError(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	mock := &mockProvider{
+		name: ProviderOpenAI,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := textStreamEvents(req.Model, "Hel")[:2]
+			return eventsChannel(append(events, StreamEvent{Error: streamErr})...), nil
+		},
+	}
+
+	events, err := NewRunner(mock, nil, RunnerConfig{}).Stream(context.Background(), userRequest("Hi"))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	var errs []error
+	var count int
+	for event := range events {
+		count++
+		if event.Error != nil {
+			errs = append(errs, event.Error)
+		}
+	}
+	if count != 3 || len(errs) != 1 || !errors.Is(errs[0], streamErr) {
+		t.Errorf("expected two events and one final error, got %d events, errors %v", count, errs)
+	}
+}
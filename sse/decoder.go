@@ -0,0 +1,149 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// Decoder reads StreamEvents written by Writer, e.g. from an HTTP response body in tests:
+//
+//	resp, _ := http.Get(server.URL)
+//	decoder := sse.NewDecoder(resp.Body)
+//	for event, err := range decoder.All() {
+//	    ...
+//	}
+type Decoder struct {
+	reader *Reader
+}
+
+// NewDecoder creates a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: NewReader(r)}
+}
+
+// Next returns the next StreamEvent, or io.EOF when the stream ends. A stream error
+// is returned in StreamEvent.Error as a *llmprovider.ProviderError wrapping the sentinel
+// for its code; the returned error is only for read and decode failures.
+// Comments and unknown event types are skipped.
+func (d *Decoder) Next() (llmprovider.StreamEvent, error) {
+	for {
+		raw, err := d.reader.Next()
+		if err != nil {
+			return llmprovider.StreamEvent{}, err
+		}
+
+		event, ok, err := decodeEvent(raw.Type, raw.Data)
+		if err != nil || ok {
+			return event, err
+		}
+	}
+}
+
+// All returns an iterator over the remaining events. The error is the event's Error,
+// or a read/decode failure (after which iteration stops). io.EOF ends iteration silently.
+func (d *Decoder) All() iter.Seq2[llmprovider.StreamEvent, error] {
+	return func(yield func(llmprovider.StreamEvent, error) bool) {
+		for {
+			event, err := d.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(event, err)
+				return
+			}
+			if !yield(event, event.Error) {
+				return
+			}
+		}
+	}
+}
+
+// LastEventID returns the id of the last event read, for resuming with Last-Event-ID.
+func (d *Decoder) LastEventID() string {
+	return d.reader.LastEventID()
+}
+
+// decodeEvent converts an SSE event into a StreamEvent. ok is false for unknown types.
+func decodeEvent(eventType, data string) (llmprovider.StreamEvent, bool, error) {
+	var event llmprovider.StreamEvent
+	var err error
+
+	switch eventType {
+	case EventDelta:
+		event.Delta = &llmprovider.BlockDelta{}
+		err = json.Unmarshal([]byte(data), event.Delta)
+	case EventBlock:
+		event.Block = &llmprovider.Block{}
+		err = json.Unmarshal([]byte(data), event.Block)
+	case EventMetadata:
+		var payload metadataPayload
+		err = json.Unmarshal([]byte(data), &payload)
+		event.Metadata = &llmprovider.StreamMetadata{
+			Model:            payload.Model,
+			InputTokens:      payload.InputTokens,
+			OutputTokens:     payload.OutputTokens,
+			StopReason:       payload.StopReason,
+			ResponseMetadata: payload.ResponseMetadata,
+		}
+	case EventError:
+		var payload errorPayload
+		err = json.Unmarshal([]byte(data), &payload)
+		event.Error = &llmprovider.ProviderError{
+			Code:       payload.Code,
+			Provider:   payload.Provider,
+			StatusCode: payload.StatusCode,
+			Message:    payload.Message,
+			Retryable:  payload.Retryable,
+			Err:        sentinelForCode(payload.Code),
+		}
+	default:
+		return event, false, nil
+	}
+
+	if err != nil {
+		return llmprovider.StreamEvent{}, false, fmt.Errorf("sse: decode %s event: %w", eventType, err)
+	}
+	return event, true, nil
+}
+
+// codeSentinels pairs error codes with their sentinel errors.
+var codeSentinels = []struct {
+	code     llmprovider.ErrorCode
+	sentinel error
+}{
+	{llmprovider.ErrorCodeInvalidModel, llmprovider.ErrInvalidModel},
+	{llmprovider.ErrorCodeInvalidAPIKey, llmprovider.ErrInvalidAPIKey},
+	{llmprovider.ErrorCodeRateLimited, llmprovider.ErrRateLimited},
+	{llmprovider.ErrorCodeUnsupportedFeature, llmprovider.ErrUnsupportedFeature},
+	{llmprovider.ErrorCodeUnsupportedTool, llmprovider.ErrUnsupportedTool},
+	{llmprovider.ErrorCodeToolUnavailable, llmprovider.ErrToolUnavailable},
+	{llmprovider.ErrorCodeInvalidRequest, llmprovider.ErrInvalidRequest},
+	{llmprovider.ErrorCodeProviderUnavailable, llmprovider.ErrProviderUnavailable},
+	{llmprovider.ErrorCodeTimeout, llmprovider.ErrTimeout},
+}
+
+// codeForSentinel returns the code of the first sentinel err wraps, or "".
+func codeForSentinel(err error) llmprovider.ErrorCode {
+	for _, pair := range codeSentinels {
+		if errors.Is(err, pair.sentinel) {
+			return pair.code
+		}
+	}
+	return ""
+}
+
+// sentinelForCode returns the sentinel error for code, or nil.
+func sentinelForCode(code llmprovider.ErrorCode) error {
+	for _, pair := range codeSentinels {
+		if pair.code == code {
+			return pair.sentinel
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// DefaultHeartbeat is the heartbeat interval used when Handler.Heartbeat is zero.
+const DefaultHeartbeat = 15 * time.Second
+
+// Handler is an http.Handler that streams StreamEvents to the client as SSE:
+//
+//	http.Handle("/chat", sse.Handler{
+//	    Stream: func(r *http.Request) (<-chan llmprovider.StreamEvent, error) {
+//	        return provider.StreamResponse(r.Context(), buildRequest(r))
+//	    },
+//	})
+//
+// Pass r.Context() to the provider so the upstream request is cancelled when the
+// client disconnects. An error returned by Stream is sent as an error event.
+type Handler struct {
+	// Stream starts the stream for a request.
+	Stream func(r *http.Request) (<-chan llmprovider.StreamEvent, error)
+
+	// Heartbeat is the interval between keep-alive comments (DefaultHeartbeat if zero,
+	// disabled if negative).
+	Heartbeat time.Duration
+}
+
+// ServeHTTP implements http.Handler.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writer := NewWriter(w)
+
+	events, err := h.Stream(r)
+	if err != nil {
+		_ = writer.WriteEvent(llmprovider.StreamEvent{Error: err})
+		return
+	}
+
+	_ = Relay(r.Context(), writer, events, h.Heartbeat)
+}
+
+// Relay writes events to writer until the channel closes, sending heartbeat comments
+// while the stream is idle. It returns ctx's error if the client disconnects (ctx is
+// typically the request context), or the first write error. On early return the rest
+// of events is drained so the producer can finish.
+func Relay(ctx context.Context, writer *Writer, events <-chan llmprovider.StreamEvent, heartbeat time.Duration) error {
+	return relay(ctx, writer, events, heartbeat, writer.WriteEvent)
+}
+
+// relay implements Relay for any event type, writing each event with write.
+func relay[T any](ctx context.Context, writer *Writer, events <-chan T, heartbeat time.Duration, write func(T) error) error {
+	if heartbeat == 0 {
+		heartbeat = DefaultHeartbeat
+	}
+
+	var ticks <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			go drain(events)
+			return ctx.Err()
+
+		case <-ticks:
+			if err := writer.Comment("heartbeat"); err != nil {
+				go drain(events)
+				return err
+			}
+
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := write(event); err != nil {
+				go drain(events)
+				return err
+			}
+		}
+	}
+}
+
+// drain discards the remaining events so the producer can finish.
+func drain[T any](events <-chan T) {
+	for range events {
+	}
+}
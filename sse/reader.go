@@ -0,0 +1,181 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEventTooLarge is returned by Reader.Next when an event exceeds the maximum size.
+var ErrEventTooLarge = errors.New("sse: event exceeds maximum size")
+
+// Event is a dispatched SSE event.
+type Event struct {
+	ID   string // Last event ID seen on the stream (ids persist across events, per the spec)
+	Type string // Event type ("message" if the event had no event field)
+	Data string // Data lines joined with "\n"
+}
+
+// Reader parses a Server-Sent Events stream per the HTML Living Standard: lines may end
+// in CRLF, LF or CR, multiple data lines are joined, comments are skipped, and event,
+// id and retry fields are honored. Lines have no length limit (unlike bufio.Scanner's
+// 64KB default), so large tool-call arguments or base64 payloads arrive intact.
+//
+// Providers use it to read upstream streams:
+//
+//	reader := sse.NewReader(resp.Body)
+//	for {
+//	    event, err := reader.Next()
+//	    if errors.Is(err, io.EOF) {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return fmt.Errorf("error reading stream: %w", err)
+//	    }
+//	    if event.Data == "[DONE]" {
+//	        break
+//	    }
+//	    ...
+//	}
+type Reader struct {
+	reader       *bufio.Reader
+	maxEventSize int
+
+	line        []byte
+	skipLF      bool // The previous line ended in CR; a following LF belongs to it
+	started     bool // The byte order mark has been checked
+	lastEventID string
+	retry       time.Duration
+}
+
+// NewReader creates a Reader on r with no event size limit.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReader(r)}
+}
+
+// SetMaxEventSize limits the bytes of a single event (field lines, excluding line
+// terminators). Next returns ErrEventTooLarge for larger events. Zero or negative
+// means no limit.
+func (r *Reader) SetMaxEventSize(n int) {
+	r.maxEventSize = n
+}
+
+// Next returns the next event with data, or io.EOF at the end of the stream.
+//
+// Unlike the spec, an event still pending at the end of the stream is dispatched if
+// its last line was terminated, since servers commonly omit the final blank line.
+// An event ending in an unterminated line is discarded as truncated.
+func (r *Reader) Next() (Event, error) {
+	var eventType string
+	var data strings.Builder
+	var hasData bool
+	var size int
+
+	for {
+		line, err := r.readLine(size)
+		if errors.Is(err, io.EOF) && hasData && len(r.line) == 0 {
+			line, err = nil, nil // Dispatch the pending event
+		}
+		if err != nil {
+			return Event{}, err
+		}
+		size += len(line)
+
+		if len(line) == 0 {
+			if !hasData {
+				eventType, size = "", 0
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return Event{
+				ID:   r.lastEventID,
+				Type: eventType,
+				Data: strings.TrimSuffix(data.String(), "\n"),
+			}, nil
+		}
+		if line[0] == ':' {
+			continue // Comment
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "data":
+			data.Write(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "event":
+			eventType = string(value)
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastEventID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// LastEventID returns the last id field seen, for reconnecting with Last-Event-ID.
+func (r *Reader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Retry returns the reconnection delay requested by the server, or 0 if none was sent.
+func (r *Reader) Retry() time.Duration {
+	return r.retry
+}
+
+// readLine returns the next line without its terminator. size is the size of the
+// event so far, for the size limit. The returned slice is valid until the next call.
+func (r *Reader) readLine(size int) ([]byte, error) {
+	r.line = r.line[:0]
+
+	for {
+		if !r.started {
+			r.started = true
+			if bom, err := r.reader.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+				_, _ = r.reader.Discard(3)
+			}
+		}
+		if _, err := r.reader.Peek(1); err != nil {
+			return nil, err // r.line keeps an unterminated last line
+		}
+
+		buffered, _ := r.reader.Peek(r.reader.Buffered())
+		if r.skipLF {
+			r.skipLF = false
+			if buffered[0] == '\n' {
+				_, _ = r.reader.Discard(1)
+				continue
+			}
+		}
+
+		end := bytes.IndexAny(buffered, "\r\n")
+		chunk := buffered
+		if end >= 0 {
+			chunk = buffered[:end]
+		}
+		if r.maxEventSize > 0 && size+len(r.line)+len(chunk) > r.maxEventSize {
+			return nil, fmt.Errorf("%w (%d bytes)", ErrEventTooLarge, r.maxEventSize)
+		}
+		r.line = append(r.line, chunk...)
+
+		if end < 0 {
+			_, _ = r.reader.Discard(len(buffered))
+			continue
+		}
+		r.skipLF = buffered[end] == '\r'
+		_, _ = r.reader.Discard(end + 1)
+		return r.line, nil
+	}
+}
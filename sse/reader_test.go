@@ -0,0 +1,89 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// readEvents reads all events from input, delivered one byte at a time to exercise
+// line terminators split across reads.
+func readEvents(t *testing.T, input string) ([]Event, *Reader) {
+	t.Helper()
+	reader := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+	var events []Event
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return events, reader
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		events = append(events, event)
+	}
+}
+
+func TestReader_Spec(t *testing.T) {
+	input := "\xef\xbb\xbf" + // Byte order mark
+		": comment\n" +
+		"retry: 2500\n" +
+		"\n" + // Dispatches nothing: no data
+		"data: first\r\n" +
+		"data:second\r\n" + // No space after the colon
+		"\r\n" +
+		"id: 7\r" + // Lone CR line endings
+		"event: update\r" +
+		"data\r" + // Field without colon: empty data line
+		"\r" +
+		"id: bad\x00id\n" + // Ids containing NUL are ignored
+		"data: {\"a\": 1}\n" +
+		"\n" +
+		"data: unterminated" // Discarded at EOF
+
+	events, reader := readEvents(t, input)
+	want := []Event{
+		{ID: "", Type: "message", Data: "first\nsecond"},
+		{ID: "7", Type: "update", Data: ""},
+		{ID: "7", Type: "message", Data: `{"a": 1}`},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+	if reader.Retry() != 2500*time.Millisecond || reader.LastEventID() != "7" {
+		t.Errorf("retry = %s, last event id = %q", reader.Retry(), reader.LastEventID())
+	}
+
+	// A missing final blank line still dispatches the last event
+	if events, _ := readEvents(t, "data: last\n"); len(events) != 1 || events[0].Data != "last" {
+		t.Errorf("expected the pending event at EOF, got %+v", events)
+	}
+}
+
+func TestReader_LongLines(t *testing.T) {
+	// Well past bufio.Scanner's 64KB default and bufio.Reader's buffer size
+	payload := `{"arguments":"` + strings.Repeat("x", 1<<20) + `"}`
+	reader := NewReader(strings.NewReader("data: " + payload + "\n\ndata: [DONE]\n\n"))
+
+	event, err := reader.Next()
+	if err != nil || event.Data != payload {
+		t.Fatalf("expected %d byte payload, got %d bytes (%v)", len(payload), len(event.Data), err)
+	}
+	if event, err := reader.Next(); err != nil || event.Data != "[DONE]" {
+		t.Errorf("expected [DONE], got %q (%v)", event.Data, err)
+	}
+
+	limited := NewReader(strings.NewReader("data: " + payload + "\n\n"))
+	limited.SetMaxEventSize(64 * 1024)
+	if _, err := limited.Next(); !errors.Is(err, ErrEventTooLarge) {
+		t.Errorf("expected ErrEventTooLarge, got %v", err)
+	}
+}
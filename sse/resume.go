@@ -0,0 +1,73 @@
+package sse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// LastEventIDParam is the query parameter BufferHandler reads when the Last-Event-ID
+// header is absent. EventSource only sends the header on automatic reconnects, so a
+// page reload can resume with "?last_event_id=N".
+const LastEventIDParam = "last_event_id"
+
+// BufferHandler is an http.Handler that serves a generation from a StreamBuffer.
+// Event ids are log sequence numbers, so a reconnecting client resumes after its
+// Last-Event-ID and the generation keeps running while no client is connected:
+//
+//	http.Handle("/generations/", sse.BufferHandler{
+//	    Buffer: buffer,
+//	    GenerationID: func(r *http.Request) string {
+//	        return strings.TrimPrefix(r.URL.Path, "/generations/")
+//	    },
+//	})
+//
+// Unknown or expired generations get a 404, which stops EventSource from reconnecting.
+type BufferHandler struct {
+	// Buffer holds the generations.
+	Buffer *llmprovider.StreamBuffer
+
+	// GenerationID extracts the generation to serve from a request.
+	GenerationID func(r *http.Request) string
+
+	// Heartbeat is the interval between keep-alive comments (DefaultHeartbeat if zero,
+	// disabled if negative).
+	Heartbeat time.Duration
+}
+
+// ServeHTTP implements http.Handler.
+func (h BufferHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	events, err := h.Buffer.Subscribe(r.Context(), h.GenerationID(r), ResumeSeq(r))
+	if errors.Is(err, llmprovider.ErrGenerationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writer := NewWriter(w)
+	if err != nil {
+		_ = writer.WriteEvent(llmprovider.StreamEvent{Error: err})
+		return
+	}
+
+	_ = relay(r.Context(), writer, events, h.Heartbeat, func(logged llmprovider.LoggedEvent) error {
+		return writer.WriteEventID(strconv.FormatInt(logged.Seq, 10), logged.Event)
+	})
+}
+
+// ResumeSeq returns the sequence number a client has already received, from the
+// Last-Event-ID header or the LastEventIDParam query parameter. It returns 0 (replay
+// everything) if neither is a valid sequence number.
+func ResumeSeq(r *http.Request) int64 {
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.URL.Query().Get(LastEventIDParam)
+	}
+	seq, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || seq < 0 {
+		return 0
+	}
+	return seq
+}
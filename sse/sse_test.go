@@ -0,0 +1,214 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+func testEvents() []llmprovider.StreamEvent {
+	text, tool := llmprovider.BlockTypeText, llmprovider.BlockTypeToolUse
+	hello, id, name, args := "Hello", "call_1", "get_weather", `{"city":"Paris"}`
+	return []llmprovider.StreamEvent{
+		{Delta: &llmprovider.BlockDelta{BlockIndex: 0, BlockType: &text, DeltaType: llmprovider.DeltaTypeText, TextDelta: &hello}},
+		{Block: &llmprovider.Block{BlockType: llmprovider.BlockTypeText, Sequence: 0, TextContent: &hello}},
+		{Delta: &llmprovider.BlockDelta{BlockIndex: 1, BlockType: &tool, DeltaType: llmprovider.DeltaTypeToolCallStart, ToolCallID: &id, ToolCallName: &name}},
+		{Delta: &llmprovider.BlockDelta{BlockIndex: 1, DeltaType: llmprovider.DeltaTypeJSON, JSONDelta: &args}},
+		{Block: &llmprovider.Block{BlockType: llmprovider.BlockTypeToolUse, Sequence: 1, Content: map[string]interface{}{
+			"tool_use_id": id, "tool_name": name, "input": map[string]interface{}{"city": "Paris"},
+		}}},
+		{Metadata: &llmprovider.StreamMetadata{Model: "gpt-4.1", InputTokens: 5, OutputTokens: 9, StopReason: "tool_use", ResponseMetadata: map[string]interface{}{"id": "resp_1"}}},
+	}
+}
+
+func eventsChannel(events ...llmprovider.StreamEvent) <-chan llmprovider.StreamEvent {
+	ch := make(chan llmprovider.StreamEvent, len(events))
+	for _, event := range events {
+		ch <- event
+	}
+	close(ch)
+	return ch
+}
+
+// TestHandler_RoundTrip tests that the decoder reconstructs the events the handler wrote
+func TestHandler_RoundTrip(t *testing.T) {
+	sent := append(testEvents(), llmprovider.StreamEvent{
+		Error: llmprovider.NewProviderError("openai", 429, "slow down", llmprovider.ErrRateLimited),
+	})
+	server := httptest.NewServer(Handler{Stream: func(r *http.Request) (<-chan llmprovider.StreamEvent, error) {
+		return eventsChannel(sent...), nil
+	}})
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	decoder := NewDecoder(resp.Body)
+	var received []llmprovider.StreamEvent
+	for event := range decoder.All() {
+		received = append(received, event)
+	}
+
+	if len(received) != len(sent) {
+		t.Fatalf("expected %d events, got %d", len(sent), len(received))
+	}
+	for i, event := range sent[:len(sent)-1] {
+		if !reflect.DeepEqual(received[i], event) {
+			t.Errorf("event %d mismatch:\n got %+v\nwant %+v", i, received[i], event)
+		}
+	}
+
+	streamErr := received[len(received)-1].Error
+	var providerErr *llmprovider.ProviderError
+	if !errors.As(streamErr, &providerErr) || providerErr.StatusCode != 429 || !errors.Is(streamErr, llmprovider.ErrRateLimited) || !llmprovider.IsRetryable(streamErr) {
+		t.Errorf("error not reconstructed: %#v", streamErr)
+	}
+	if decoder.LastEventID() != "7" {
+		t.Errorf("expected last event id 7, got %q", decoder.LastEventID())
+	}
+}
+
+// TestHandler_StartError tests that an error starting the stream is sent as an error event
+func TestHandler_StartError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler{Stream: func(r *http.Request) (<-chan llmprovider.StreamEvent, error) {
+		return nil, &llmprovider.ModelError{Model: "gpt-9", Provider: "openai", Reason: "unknown", Err: llmprovider.ErrInvalidModel}
+	}}.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+	event, err := NewDecoder(recorder.Body).Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if !errors.Is(event.Error, llmprovider.ErrInvalidModel) || llmprovider.IsRetryable(event.Error) {
+		t.Errorf("expected non-retryable ErrInvalidModel, got %v", event.Error)
+	}
+}
+
+// TestHandler_HeartbeatAndDisconnect tests heartbeat comments and that a client disconnect
+// cancels the request context passed to the provider
+func TestHandler_HeartbeatAndDisconnect(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(Handler{
+		Heartbeat: 10 * time.Millisecond,
+		Stream: func(r *http.Request) (<-chan llmprovider.StreamEvent, error) {
+			events := make(chan llmprovider.StreamEvent)
+			go func() {
+				defer close(events)
+				<-r.Context().Done()
+				close(cancelled)
+			}()
+			return events, nil
+		},
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+
+	buf := make([]byte, len(": heartbeat\n\n"))
+	if _, err := io.ReadFull(resp.Body, buf); err != nil || string(buf) != ": heartbeat\n\n" {
+		t.Errorf("expected heartbeat comment, got %q (%v)", buf, err)
+	}
+
+	cancel()
+	resp.Body.Close()
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request context not cancelled after client disconnect")
+	}
+}
+
+// TestDecoder_Parsing tests SSE framing rules: multi-line data, CRLF, comments, ids and unknown events
+func TestDecoder_Parsing(t *testing.T) {
+	input := strings.Join([]string{
+		": comment\r",
+		"id: 41\r",
+		"event: ping\r",
+		"data: {}\r",
+		"\r",
+		"id: 42",
+		"event: metadata",
+		`data: {"model":"gpt-4.1",`,
+		`data: "stop_reason":"end_turn"}`,
+		"",
+		"event: delta", // Incomplete event at EOF is discarded
+		`data: {"delta_type":"text_delta"}`,
+	}, "\n")
+
+	decoder := NewDecoder(strings.NewReader(input))
+	event, err := decoder.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if event.Metadata == nil || event.Metadata.Model != "gpt-4.1" || event.Metadata.StopReason != "end_turn" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if decoder.LastEventID() != "42" {
+		t.Errorf("expected last event id 42, got %q", decoder.LastEventID())
+	}
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+// TestBufferHandler_Resume tests that a client reconnecting with Last-Event-ID gets only newer events
+func TestBufferHandler_Resume(t *testing.T) {
+	buffer := llmprovider.NewStreamBuffer(llmprovider.StreamBufferConfig{})
+	events := testEvents()
+	if err := buffer.Start(context.Background(), "gen_1", eventsChannel(events...)); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	server := httptest.NewServer(BufferHandler{
+		Buffer:       buffer,
+		GenerationID: func(r *http.Request) string { return strings.TrimPrefix(r.URL.Path, "/") },
+	})
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/gen_1", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	decoder := NewDecoder(resp.Body)
+	var received []llmprovider.StreamEvent
+	for event := range decoder.All() {
+		received = append(received, event)
+	}
+	if !reflect.DeepEqual(received, events[4:]) {
+		t.Errorf("resumed events mismatch:\n got %+v\nwant %+v", received, events[4:])
+	}
+	if decoder.LastEventID() != "6" {
+		t.Errorf("expected last event id 6, got %q", decoder.LastEventID())
+	}
+
+	resp, err = http.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown generation, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,230 @@
+// Package sse relays llmprovider StreamEvents over Server-Sent Events and decodes them back.
+//
+// Each StreamEvent is written as a typed SSE event with a JSON payload:
+//
+//	id: 3
+//	event: delta
+//	data: {"block_index":0,"delta_type":"text_delta","text_delta":"Hello"}
+//
+// Event types are EventDelta, EventBlock, EventMetadata and EventError.
+//
+// Reader is a general SSE parser with no line length limit; providers use it to read
+// upstream streams.
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	llmprovider "github.com/haowjy/meridian-llm-go"
+)
+
+// SSE event types
+const (
+	EventDelta    = "delta"    // data: BlockDelta
+	EventBlock    = "block"    // data: Block
+	EventMetadata = "metadata" // data: metadataPayload
+	EventError    = "error"    // data: errorPayload
+)
+
+// Writer writes StreamEvents as SSE events. It is safe for concurrent use.
+type Writer struct {
+	mu        sync.Mutex
+	w         io.Writer
+	flusher   http.Flusher // nil if w can't flush
+	nextID    int64
+	autoFlush bool
+}
+
+// NewWriter creates a Writer on w. If w is an http.ResponseWriter, the SSE response
+// headers are set (before anything is written). Events are flushed after each write
+// unless SetAutoFlush(false) is called.
+func NewWriter(w io.Writer) *Writer {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		header := rw.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	return &Writer{w: w, flusher: flusher, autoFlush: true}
+}
+
+// SetAutoFlush controls whether each write is flushed immediately.
+// With auto-flush off, call Flush to push buffered events (e.g. once per batch of deltas).
+func (w *Writer) SetAutoFlush(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.autoFlush = enabled
+}
+
+// WriteEvent writes event with the next sequence number as its id (starting at 1).
+func (w *Writer) WriteEvent(event llmprovider.StreamEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.nextID++
+	return w.writeEvent(strconv.FormatInt(w.nextID, 10), event)
+}
+
+// WriteEventID writes event with an explicit id, e.g. a position in an event log.
+// Numeric ids also advance the sequence used by WriteEvent.
+func (w *Writer) WriteEventID(id string, event llmprovider.StreamEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if seq, err := strconv.ParseInt(id, 10, 64); err == nil && seq > w.nextID {
+		w.nextID = seq
+	}
+	return w.writeEvent(id, event)
+}
+
+// Comment writes an SSE comment line, which clients ignore. Used for heartbeats.
+func (w *Writer) Comment(text string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(": ")
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return w.write(b.String())
+}
+
+// SetRetry tells the client how long to wait before reconnecting.
+func (w *Writer) SetRetry(d time.Duration) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.write(fmt.Sprintf("retry: %d\n\n", d.Milliseconds()))
+}
+
+// Flush pushes buffered events to the client.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+}
+
+// writeEvent encodes and writes a single event. Caller holds mu.
+func (w *Writer) writeEvent(id string, event llmprovider.StreamEvent) error {
+	eventType, payload, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+	b.WriteString("event: " + eventType + "\n")
+	b.WriteString("data: ")
+	b.Write(payload) // json.Marshal output contains no newlines
+	b.WriteString("\n\n")
+	return w.write(b.String())
+}
+
+// write writes s and flushes if auto-flush is on. Caller holds mu.
+func (w *Writer) write(s string) error {
+	if _, err := io.WriteString(w.w, s); err != nil {
+		return err
+	}
+	if w.autoFlush && w.flusher != nil {
+		w.flusher.Flush()
+	}
+	return nil
+}
+
+// metadataPayload is the wire form of StreamMetadata.
+type metadataPayload struct {
+	Model            string                 `json:"model"`
+	InputTokens      int                    `json:"input_tokens"`
+	OutputTokens     int                    `json:"output_tokens"`
+	StopReason       string                 `json:"stop_reason"`
+	ResponseMetadata map[string]interface{} `json:"response_metadata,omitempty"`
+}
+
+// errorPayload is the wire form of a stream error.
+type errorPayload struct {
+	Message    string                `json:"message"`
+	Code       llmprovider.ErrorCode `json:"code,omitempty"`
+	Provider   string                `json:"provider,omitempty"`
+	StatusCode int                   `json:"status_code,omitempty"`
+	Retryable  bool                  `json:"retryable"`
+}
+
+// encodeEvent returns the SSE event type and JSON payload for event.
+func encodeEvent(event llmprovider.StreamEvent) (string, []byte, error) {
+	var eventType string
+	var payload interface{}
+
+	switch {
+	case event.Error != nil:
+		eventType, payload = EventError, newErrorPayload(event.Error)
+	case event.Delta != nil:
+		eventType, payload = EventDelta, event.Delta
+	case event.Block != nil:
+		eventType, payload = EventBlock, event.Block
+	case event.Metadata != nil:
+		eventType, payload = EventMetadata, metadataPayload{
+			Model:            event.Metadata.Model,
+			InputTokens:      event.Metadata.InputTokens,
+			OutputTokens:     event.Metadata.OutputTokens,
+			StopReason:       event.Metadata.StopReason,
+			ResponseMetadata: event.Metadata.ResponseMetadata,
+		}
+	default:
+		return "", nil, fmt.Errorf("sse: empty stream event")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("sse: encode %s event: %w", eventType, err)
+	}
+	return eventType, data, nil
+}
+
+// newErrorPayload extracts the machine-readable parts of a library error.
+func newErrorPayload(err error) errorPayload {
+	payload := errorPayload{Message: err.Error(), Retryable: llmprovider.IsRetryable(err)}
+
+	var providerErr *llmprovider.ProviderError
+	var modelErr *llmprovider.ModelError
+	var toolErr *llmprovider.ToolError
+	var validationErr *llmprovider.ValidationError
+	switch {
+	case errors.As(err, &providerErr):
+		payload.Code = providerErr.Code
+		payload.Provider = providerErr.Provider
+		payload.StatusCode = providerErr.StatusCode
+	case errors.As(err, &modelErr):
+		payload.Code = modelErr.Code
+		payload.Provider = modelErr.Provider
+		if payload.Code == "" {
+			payload.Code = llmprovider.ErrorCodeInvalidModel
+		}
+	case errors.As(err, &toolErr):
+		payload.Code = toolErr.Code
+		payload.Provider = toolErr.Provider
+	case errors.As(err, &validationErr):
+		payload.Code = llmprovider.ErrorCodeInvalidRequest
+	}
+
+	if payload.Code == "" {
+		payload.Code = codeForSentinel(err)
+	}
+	return payload
+}
@@ -0,0 +1,77 @@
+package llmprovider
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Stream is a streaming response that cleans up after itself.
+//
+// Iterate with All; breaking out of the loop closes the stream:
+//
+//	stream, err := llmprovider.NewStream(ctx, provider, req)
+//	if err != nil {
+//	    return err
+//	}
+//	for event, err := range stream.All() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if event.Delta != nil && event.Delta.TextDelta != nil {
+//	        fmt.Print(*event.Delta.TextDelta)
+//	    }
+//	}
+//
+// Close cancels the stream's context, which aborts the provider's HTTP request (closing
+// the response body) and stops its goroutine. Remaining events are drained in the
+// background so a producer blocked on a send can exit.
+type Stream struct {
+	events <-chan StreamEvent
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewStream starts a streaming response from provider.
+// Errors returned before streaming starts (validation, HTTP status) are returned directly.
+func NewStream(ctx context.Context, provider Provider, req *GenerateRequest) (*Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	events, err := provider.StreamResponse(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &Stream{events: events, cancel: cancel}, nil
+}
+
+// All returns an iterator over the stream's events. The error is the event's Error
+// (nil for delta, block and metadata events). The stream is closed when iteration
+// ends, including on early break. A Stream can be iterated only once.
+func (s *Stream) All() iter.Seq2[StreamEvent, error] {
+	return func(yield func(StreamEvent, error) bool) {
+		defer s.Close()
+
+		for event := range s.events {
+			if !yield(event, event.Error) {
+				return
+			}
+		}
+	}
+}
+
+// Events returns the underlying event channel, for consumers of the channel API.
+// Call Close if you stop reading before the channel is closed.
+func (s *Stream) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Close stops the stream and releases its resources. It is safe to call more than once.
+func (s *Stream) Close() error {
+	s.once.Do(func() {
+		s.cancel()
+		go drainStream(s.events)
+	})
+	return nil
+}
@@ -0,0 +1,92 @@
+package llmprovider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// leakyProvider streams until cancelled using unconditional sends, like a producer
+// that ignores the consumer going away.
+func leakyProvider(exited chan<- struct{}) *mockProvider {
+	return &mockProvider{
+		name: ProviderLorem,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := make(chan StreamEvent, 10)
+			go func() {
+				defer close(exited)
+				defer close(events)
+				text := "lorem "
+				for ctx.Err() == nil {
+					events <- StreamEvent{Delta: &BlockDelta{DeltaType: DeltaTypeText, TextDelta: &text}}
+				}
+				events <- StreamEvent{Error: ctx.Err()}
+			}()
+			return events, nil
+		},
+	}
+}
+
+func TestStream_BreakCleansUp(t *testing.T) {
+	exited := make(chan struct{})
+	stream, err := NewStream(context.Background(), leakyProvider(exited), &GenerateRequest{Model: "lorem-fast"})
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+
+	var count int
+	for event, err := range stream.All() {
+		if err != nil || event.Delta == nil {
+			t.Fatalf("unexpected event %+v (%v)", event, err)
+		}
+		if count++; count == 3 {
+			break
+		}
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("producer goroutine still running after break")
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestStream_AllYieldsErrors(t *testing.T) {
+	provider := &mockProvider{
+		name: ProviderOpenAI,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := textStreamEvents(req.Model, "hi")
+			return eventsChannel(events[0], events[1], StreamEvent{Error: ErrProviderUnavailable}), nil
+		},
+	}
+
+	stream, err := NewStream(context.Background(), provider, &GenerateRequest{Model: "gpt-4.1"})
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+
+	var events int
+	var streamErr error
+	for _, err := range stream.All() {
+		events++
+		streamErr = err
+	}
+	if events != 3 || streamErr != ErrProviderUnavailable {
+		t.Errorf("expected 3 events ending in ErrProviderUnavailable, got %d (%v)", events, streamErr)
+	}
+}
+
+func TestNewStream_StartError(t *testing.T) {
+	provider := &mockProvider{
+		name: ProviderOpenAI,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			return nil, ErrInvalidModel
+		},
+	}
+	if _, err := NewStream(context.Background(), provider, &GenerateRequest{Model: "gpt-4.1"}); err != ErrInvalidModel {
+		t.Errorf("expected start error to be returned, got %v", err)
+	}
+}
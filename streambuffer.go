@@ -0,0 +1,188 @@
+package llmprovider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultBufferRetention is how long a finished generation stays replayable when
+// StreamBufferConfig.Retention is zero.
+const DefaultBufferRetention = 5 * time.Minute
+
+// StreamBufferConfig configures a StreamBuffer.
+type StreamBufferConfig struct {
+	Log       EventLog      // Event storage (NewMemoryEventLog if nil)
+	Retention time.Duration // How long finished generations are kept (DefaultBufferRetention if zero, forever if negative)
+}
+
+// StreamBuffer decouples a generation from the clients watching it. Start tees a
+// provider's events into an EventLog keyed by generation ID; any number of clients
+// Subscribe to it, each from its own position, so a client that reconnects resumes
+// where it left off without calling the LLM again:
+//
+//	buffer := llmprovider.NewStreamBuffer(llmprovider.StreamBufferConfig{})
+//
+//	// Start the generation on a context that outlives the client's request
+//	events, err := provider.StreamResponse(context.Background(), req)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := buffer.Start(ctx, generationID, events); err != nil {
+//	    return err
+//	}
+//
+//	// Each (re)connecting client passes the last sequence number it received (0 for all)
+//	subscription, err := buffer.Subscribe(r.Context(), generationID, lastSeq)
+//	if err != nil {
+//	    return err
+//	}
+//	for logged := range subscription {
+//	    send(logged.Seq, logged.Event)
+//	}
+//
+// Subscribers read from the log, so a slow or disconnected client never blocks the
+// generation. Live updates are delivered to subscribers of this StreamBuffer; a shared
+// EventLog written by another process can be replayed but is not followed.
+type StreamBuffer struct {
+	log       EventLog
+	retention time.Duration
+
+	mu   sync.Mutex
+	live map[string]chan struct{} // Generation ID -> closed (and replaced) on each append
+}
+
+// NewStreamBuffer creates a StreamBuffer.
+func NewStreamBuffer(config StreamBufferConfig) *StreamBuffer {
+	if config.Log == nil {
+		config.Log = NewMemoryEventLog()
+	}
+	if config.Retention == 0 {
+		config.Retention = DefaultBufferRetention
+	}
+	return &StreamBuffer{
+		log:       config.Log,
+		retention: config.Retention,
+		live:      make(map[string]chan struct{}),
+	}
+}
+
+// Start logs events under generationID in the background until the channel closes.
+// It returns ErrGenerationExists if the ID is in use. ctx is passed to the log, but its
+// cancellation is ignored once recording starts; cancel the context passed to
+// StreamResponse to abort the generation itself.
+//
+// If the log rejects an event, the rest of the stream is discarded and the generation
+// is finished early.
+func (b *StreamBuffer) Start(ctx context.Context, generationID string, events <-chan StreamEvent) error {
+	// Register as live before the log entry is visible, so a Subscribe that finds the
+	// entry also finds the generation live and waits for its events
+	b.mu.Lock()
+	if _, ok := b.live[generationID]; ok {
+		b.mu.Unlock()
+		return ErrGenerationExists
+	}
+	b.live[generationID] = make(chan struct{})
+	b.mu.Unlock()
+
+	if err := b.log.Create(ctx, generationID); err != nil {
+		b.notify(generationID, false)
+		return err
+	}
+
+	go b.record(context.WithoutCancel(ctx), generationID, events)
+	return nil
+}
+
+// record appends events to the log and wakes subscribers after each one.
+func (b *StreamBuffer) record(ctx context.Context, generationID string, events <-chan StreamEvent) {
+	for event := range events {
+		if _, err := b.log.Append(ctx, generationID, event); err != nil {
+			drainStream(events)
+			break
+		}
+		b.notify(generationID, true)
+	}
+
+	_ = b.log.Finish(ctx, generationID)
+	b.notify(generationID, false)
+
+	if b.retention > 0 {
+		time.AfterFunc(b.retention, func() {
+			_ = b.log.Delete(context.Background(), generationID)
+		})
+	}
+}
+
+// notify wakes subscribers waiting on generationID. If more is false the generation
+// is no longer live.
+func (b *StreamBuffer) notify(generationID string, more bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	close(b.live[generationID])
+	if more {
+		b.live[generationID] = make(chan struct{})
+	} else {
+		delete(b.live, generationID)
+	}
+}
+
+// watch returns the channel closed on the generation's next append, or nil if it is not live.
+func (b *StreamBuffer) watch(generationID string) <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.live[generationID]
+}
+
+// Subscribe returns the generation's events after afterSeq (0 for all), followed by
+// new events as they arrive. The channel closes when the generation finishes or ctx
+// is cancelled; cancelling ctx does not stop the generation.
+// Returns ErrGenerationNotFound for unknown (or expired) generations.
+//
+// If the log fails mid-subscription, the error is delivered as a final event with the
+// last delivered sequence number.
+func (b *StreamBuffer) Subscribe(ctx context.Context, generationID string, afterSeq int64) (<-chan LoggedEvent, error) {
+	if _, _, err := b.log.Read(ctx, generationID, afterSeq); err != nil {
+		return nil, err
+	}
+
+	out := make(chan LoggedEvent)
+	go func() {
+		defer close(out)
+
+		seq := afterSeq
+		for {
+			// Watch before reading so an append between the two isn't missed
+			wake := b.watch(generationID)
+
+			events, finished, err := b.log.Read(ctx, generationID, seq)
+			if err != nil {
+				events, finished = []LoggedEvent{{Seq: seq, Event: StreamEvent{Error: err}}}, true
+			}
+			for _, logged := range events {
+				select {
+				case out <- logged:
+					seq = logged.Seq
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if finished || wake == nil {
+				return
+			}
+			select {
+			case <-wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Delete removes a finished generation from the log before its retention expires.
+func (b *StreamBuffer) Delete(ctx context.Context, generationID string) error {
+	return b.log.Delete(ctx, generationID)
+}
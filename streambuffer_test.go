@@ -0,0 +1,182 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// collect reads n events from a subscription, failing the test on timeout.
+func collect(t *testing.T, events <-chan LoggedEvent, n int) []LoggedEvent {
+	t.Helper()
+	var got []LoggedEvent
+	for len(got) < n {
+		select {
+		case logged, ok := <-events:
+			if !ok {
+				t.Fatalf("subscription closed after %d of %d events", len(got), n)
+			}
+			got = append(got, logged)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d of %d events", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestStreamBuffer_ResumeAndMultipleSubscribers(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewStreamBuffer(StreamBufferConfig{})
+	events := textStreamEvents("claude-sonnet-4-5", "Hello there")
+
+	producer := make(chan StreamEvent)
+	if err := buffer.Start(ctx, "gen_1", producer); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := buffer.Start(ctx, "gen_1", producer); !errors.Is(err, ErrGenerationExists) {
+		t.Errorf("expected ErrGenerationExists, got %v", err)
+	}
+
+	first, err := buffer.Subscribe(ctx, "gen_1", 0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	producer <- events[0]
+	producer <- events[1]
+	if got := collect(t, first, 2); got[0].Seq != 1 || got[1].Seq != 2 {
+		t.Errorf("unexpected sequence numbers: %d, %d", got[0].Seq, got[1].Seq)
+	}
+
+	// A reconnecting client resumes after the last event it saw
+	resumed, err := buffer.Subscribe(ctx, "gen_1", 1)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	for _, event := range events[2:] {
+		producer <- event
+	}
+	close(producer)
+
+	got := collect(t, resumed, len(events)-1)
+	if got[0].Seq != 2 || !reflect.DeepEqual(got[len(got)-1].Event, events[len(events)-1]) {
+		t.Errorf("unexpected resumed events: %+v", got)
+	}
+	collect(t, first, len(events)-2)
+	for _, sub := range []<-chan LoggedEvent{first, resumed} {
+		if _, ok := <-sub; ok {
+			t.Error("expected subscription to close when the generation finishes")
+		}
+	}
+
+	// Finished generations can still be replayed
+	replay, err := buffer.Subscribe(ctx, "gen_1", 0)
+	if err != nil {
+		t.Fatalf("Subscribe() after finish error = %v", err)
+	}
+	if got := collect(t, replay, len(events)); got[len(got)-1].Seq != int64(len(events)) {
+		t.Errorf("unexpected replay: %+v", got)
+	}
+}
+
+func TestStreamBuffer_RetentionAndNotFound(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewStreamBuffer(StreamBufferConfig{Retention: 10 * time.Millisecond})
+
+	if _, err := buffer.Subscribe(ctx, "missing", 0); !errors.Is(err, ErrGenerationNotFound) {
+		t.Errorf("expected ErrGenerationNotFound, got %v", err)
+	}
+
+	if err := buffer.Start(ctx, "gen_2", eventsChannel(textStreamEvents("gpt-4.1", "Hi")...)); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := buffer.Subscribe(ctx, "gen_2", 0); errors.Is(err, ErrGenerationNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("generation not deleted after retention")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStreamBuffer_SubscriberCancel(t *testing.T) {
+	buffer := NewStreamBuffer(StreamBufferConfig{})
+	producer := make(chan StreamEvent)
+	if err := buffer.Start(context.Background(), "gen_3", producer); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := buffer.Subscribe(ctx, "gen_3", 0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	cancel()
+	select {
+	case <-sub:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not closed after cancel")
+	}
+
+	// The generation keeps recording without subscribers
+	events := textStreamEvents("gpt-4.1", "Still here")
+	for _, event := range events {
+		producer <- event
+	}
+	close(producer)
+
+	replay, _ := buffer.Subscribe(context.Background(), "gen_3", 0)
+	collect(t, replay, len(events))
+}
+
+// subscribeOnCreate subscribes to a generation as soon as it is created, before Start returns.
+type subscribeOnCreate struct {
+	EventLog
+	buffer       *StreamBuffer
+	subscription <-chan LoggedEvent
+}
+
+func (l *subscribeOnCreate) Create(ctx context.Context, generationID string) error {
+	if err := l.EventLog.Create(ctx, generationID); err != nil {
+		return err
+	}
+	subscription, err := l.buffer.Subscribe(ctx, generationID, 0)
+	l.subscription = subscription
+	time.Sleep(10 * time.Millisecond) // Let the subscription read the log before Start continues
+	return err
+}
+
+func TestStreamBuffer_SubscribeDuringStart(t *testing.T) {
+	ctx := context.Background()
+	log := &subscribeOnCreate{EventLog: NewMemoryEventLog()}
+	buffer := NewStreamBuffer(StreamBufferConfig{Log: log})
+	log.buffer = buffer
+
+	producer := make(chan StreamEvent)
+	if err := buffer.Start(ctx, "gen_1", producer); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	late, err := buffer.Subscribe(ctx, "gen_1", 0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	events := textStreamEvents("claude-sonnet-4-5", "Hello")
+	go func() {
+		for _, event := range events {
+			producer <- event
+		}
+		close(producer)
+	}()
+
+	for _, subscription := range []<-chan LoggedEvent{log.subscription, late} {
+		if got := collect(t, subscription, len(events)); got[len(got)-1].Event.Metadata == nil {
+			t.Errorf("expected metadata last, got %+v", got[len(got)-1])
+		}
+	}
+}
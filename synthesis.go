@@ -0,0 +1,193 @@
+package llmprovider
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// StreamFunc has the signature of Provider.StreamResponse.
+type StreamFunc func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error)
+
+// GenerateFromStream builds a GenerateResponse by consuming a streaming response.
+// Providers that implement streaming can use it for GenerateResponse:
+//
+//	func (p *Provider) GenerateResponse(ctx context.Context, req *llmprovider.GenerateRequest) (*llmprovider.GenerateResponse, error) {
+//	    return llmprovider.GenerateFromStream(ctx, p.StreamResponse, req)
+//	}
+//
+// The stream is cancelled when GenerateFromStream returns. Stream errors are returned as is.
+func GenerateFromStream(ctx context.Context, stream StreamFunc, req *GenerateRequest) (*GenerateResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := NewAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			go drainStream(events)
+			return nil, ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return acc.Response()
+			}
+			if err := acc.Add(event); err != nil {
+				go drainStream(events)
+				return nil, err
+			}
+		}
+	}
+}
+
+// StreamFromResponse replays a complete response as a stream: for each block, chunked
+// deltas (text and thinking split after whitespace, tool input as one JSON delta)
+// followed by the complete Block, then a final Metadata event. Providers that only
+// implement GenerateResponse can use it for StreamResponse:
+//
+//	func (p *Provider) StreamResponse(ctx context.Context, req *llmprovider.GenerateRequest) (<-chan llmprovider.StreamEvent, error) {
+//	    resp, err := p.GenerateResponse(ctx, req)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return llmprovider.StreamFromResponse(ctx, resp), nil
+//	}
+//
+// Blocks are re-sequenced by position; resp is not modified.
+func StreamFromResponse(ctx context.Context, resp *GenerateResponse) <-chan StreamEvent {
+	eventChan := make(chan StreamEvent, 10) // Buffered to prevent blocking
+
+	go func() {
+		defer close(eventChan)
+
+		send := func(event StreamEvent) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case eventChan <- event:
+				return true
+			}
+		}
+
+		for i, block := range resp.Blocks {
+			for _, delta := range blockDeltas(i, block) {
+				if !send(StreamEvent{Delta: delta}) {
+					return
+				}
+			}
+
+			complete := block
+			if block.Sequence != i {
+				copied := *block
+				copied.Sequence = i
+				complete = &copied
+			}
+			if !send(StreamEvent{Block: complete}) {
+				return
+			}
+		}
+
+		send(StreamEvent{Metadata: &StreamMetadata{
+			Model:            resp.Model,
+			InputTokens:      resp.InputTokens,
+			OutputTokens:     resp.OutputTokens,
+			StopReason:       resp.StopReason,
+			ResponseMetadata: resp.ResponseMetadata,
+		}})
+	}()
+
+	return eventChan
+}
+
+// blockDeltas returns the deltas that stream block at index.
+// Blocks without streamable content (images, search results, ...) have none.
+func blockDeltas(index int, block *Block) []*BlockDelta {
+	blockType := block.BlockType
+
+	switch block.BlockType {
+	case BlockTypeText, BlockTypeThinking:
+		deltaType := DeltaTypeText
+		if block.BlockType == BlockTypeThinking {
+			deltaType = DeltaTypeThinking
+		}
+
+		var text string
+		if block.TextContent != nil {
+			text = *block.TextContent
+		}
+
+		var deltas []*BlockDelta
+		for _, chunk := range chunkText(text) {
+			deltas = append(deltas, &BlockDelta{BlockIndex: index, DeltaType: deltaType, TextDelta: &chunk})
+		}
+		if len(deltas) == 0 {
+			deltas = append(deltas, &BlockDelta{BlockIndex: index, DeltaType: deltaType, TextDelta: &text})
+		}
+		deltas[0].BlockType = &blockType
+
+		if signature := blockSignature(block); signature != "" {
+			deltas = append(deltas, &BlockDelta{BlockIndex: index, DeltaType: DeltaTypeSignature, SignatureDelta: &signature})
+		}
+		return deltas
+
+	case BlockTypeToolUse:
+		id, _ := block.GetToolUseID()
+		name, _ := block.GetToolName()
+		deltas := []*BlockDelta{{
+			BlockIndex:   index,
+			BlockType:    &blockType,
+			DeltaType:    DeltaTypeToolCallStart,
+			ToolCallID:   &id,
+			ToolCallName: &name,
+		}}
+
+		if input, ok := block.Content["input"]; ok {
+			if data, err := json.Marshal(input); err == nil {
+				inputJSON := string(data)
+				deltas = append(deltas, &BlockDelta{BlockIndex: index, DeltaType: DeltaTypeJSON, JSONDelta: &inputJSON})
+			}
+		}
+		return deltas
+
+	default:
+		return nil
+	}
+}
+
+// blockSignature returns the signature stored in a block's ProviderData, if any:
+// "signature" (Anthropic) or "thought_signature" (Gemini).
+func blockSignature(block *Block) string {
+	var providerData struct {
+		Signature        string `json:"signature"`
+		ThoughtSignature string `json:"thought_signature"`
+	}
+	if len(block.ProviderData) == 0 || json.Unmarshal(block.ProviderData, &providerData) != nil {
+		return ""
+	}
+	if providerData.Signature != "" {
+		return providerData.Signature
+	}
+	return providerData.ThoughtSignature
+}
+
+// chunkText splits text after each run of whitespace, so chunks concatenate back to text.
+func chunkText(text string) []string {
+	var chunks []string
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		space := r == ' ' || r == '\n' || r == '\t'
+		if inSpace && !space {
+			chunks = append(chunks, text[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+	if start < len(text) {
+		chunks = append(chunks, text[start:])
+	}
+	return chunks
+}
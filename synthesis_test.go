@@ -0,0 +1,111 @@
+package llmprovider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamFromResponse_RoundTrip(t *testing.T) {
+	thinking := "Check the\nforecast  first."
+	text := "Looking it up."
+	resp := &GenerateResponse{
+		Blocks: []*Block{
+			{BlockType: BlockTypeThinking, TextContent: &thinking, ProviderData: []byte(`{"signature":"sig"}`)},
+			{BlockType: BlockTypeText, TextContent: &text},
+			{BlockType: BlockTypeToolUse, Content: map[string]interface{}{
+				"tool_use_id": "call_1",
+				"tool_name":   "get_weather",
+				"input":       map[string]interface{}{"city": "Paris"},
+			}},
+		},
+		Model:            "claude-sonnet-4-5",
+		InputTokens:      12,
+		OutputTokens:     34,
+		StopReason:       "tool_use",
+		ResponseMetadata: map[string]interface{}{"id": "msg_1"},
+	}
+
+	// Deltas alone must rebuild the same content
+	acc := NewAccumulator()
+	var deltas int
+	for event := range StreamFromResponse(context.Background(), resp) {
+		if event.Delta != nil {
+			deltas++
+			_ = acc.Add(event)
+		}
+	}
+	if deltas < 8 {
+		t.Errorf("expected chunked deltas, got %d", deltas)
+	}
+	fromDeltas := acc.Blocks()
+	if *fromDeltas[0].TextContent != thinking || string(fromDeltas[0].ProviderData) != `{"signature":"sig"}` || *fromDeltas[1].TextContent != text {
+		t.Errorf("deltas did not rebuild text: %q, %q", *fromDeltas[0].TextContent, *fromDeltas[1].TextContent)
+	}
+	if input, _ := fromDeltas[2].GetToolInput(); input["city"] != "Paris" {
+		t.Errorf("deltas did not rebuild tool input: %v", fromDeltas[2].Content)
+	}
+
+	mock := &mockProvider{
+		name: ProviderAnthropic,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			return StreamFromResponse(ctx, resp), nil
+		},
+	}
+	rebuilt, err := GenerateFromStream(context.Background(), mock.StreamResponse, &GenerateRequest{Model: "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("GenerateFromStream() error = %v", err)
+	}
+
+	for i, block := range resp.Blocks {
+		block.Sequence = i // StreamFromResponse re-sequences by position
+	}
+	if !reflect.DeepEqual(rebuilt, resp) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", rebuilt, resp)
+	}
+}
+
+func TestStreamFromResponse_GeminiSignature(t *testing.T) {
+	thought := "Considering the forecast."
+	resp := &GenerateResponse{
+		Blocks: []*Block{
+			{BlockType: BlockTypeThinking, TextContent: &thought, ProviderData: []byte(`{"thought_signature":"sig_abc"}`)},
+		},
+		Model: "gemini-2.5-pro",
+	}
+
+	var signatures []string
+	for event := range StreamFromResponse(context.Background(), resp) {
+		if event.Delta != nil && event.Delta.IsSignatureDelta() {
+			signatures = append(signatures, *event.Delta.SignatureDelta)
+		}
+	}
+	if !reflect.DeepEqual(signatures, []string{"sig_abc"}) {
+		t.Errorf("signature deltas = %v, want [sig_abc]", signatures)
+	}
+}
+
+func TestGenerateFromStream_Error(t *testing.T) {
+	mock := &mockProvider{
+		name: ProviderOpenAI,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			events := textStreamEvents(req.Model, "partial")
+			return eventsChannel(events[0], events[1], StreamEvent{Error: ErrProviderUnavailable}), nil
+		},
+	}
+	if _, err := GenerateFromStream(context.Background(), mock.StreamResponse, &GenerateRequest{Model: "gpt-4.1"}); err != ErrProviderUnavailable {
+		t.Errorf("expected stream error, got %v", err)
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	for _, text := range []string{"", "one", "two words", "  leading and trailing  ", "line\nbreaks\n\nhere"} {
+		if got := strings.Join(chunkText(text), ""); got != text {
+			t.Errorf("chunks of %q rejoin to %q", text, got)
+		}
+	}
+	if got := chunkText("a b  c"); !reflect.DeepEqual(got, []string{"a ", "b  ", "c"}) {
+		t.Errorf("unexpected chunks: %q", got)
+	}
+}
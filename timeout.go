@@ -0,0 +1,155 @@
+package llmprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StreamTimeouts bounds how long a stream may go without events. Zero disables a limit.
+type StreamTimeouts struct {
+	FirstToken time.Duration // From the StreamResponse call until the first event (including the HTTP handshake)
+	Idle       time.Duration // Between consecutive events
+}
+
+// TimeoutProvider aborts streams that stall. When a limit is exceeded the upstream
+// request is cancelled and the stream ends with an error event: a retryable
+// *ProviderError wrapping ErrTimeout.
+//
+//	provider := llmprovider.NewTimeoutProvider(anthropicProvider, llmprovider.StreamTimeouts{
+//	    FirstToken: 30 * time.Second,
+//	    Idle:       15 * time.Second,
+//	})
+//
+// A first-token timeout that fires before StreamResponse returns is returned as its
+// error instead, so a RetryProvider wrapped around a TimeoutProvider retries it.
+// GenerateResponse is passed through; use WithTimeout or a context deadline for it.
+type TimeoutProvider struct {
+	provider Provider
+	timeouts StreamTimeouts
+}
+
+// NewTimeoutProvider wraps provider with stream timeouts.
+func NewTimeoutProvider(provider Provider, timeouts StreamTimeouts) *TimeoutProvider {
+	return &TimeoutProvider{provider: provider, timeouts: timeouts}
+}
+
+// TimeoutMiddleware returns a Middleware that wraps providers with NewTimeoutProvider.
+func TimeoutMiddleware(timeouts StreamTimeouts) Middleware {
+	return func(next Provider) Provider {
+		return NewTimeoutProvider(next, timeouts)
+	}
+}
+
+// GenerateResponse calls the wrapped provider.
+func (t *TimeoutProvider) GenerateResponse(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return t.provider.GenerateResponse(ctx, req)
+}
+
+// StreamResponse starts a stream that is cancelled if it stalls.
+func (t *TimeoutProvider) StreamResponse(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	if t.timeouts.FirstToken <= 0 && t.timeouts.Idle <= 0 {
+		return t.provider.StreamResponse(ctx, req)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	started := time.Now()
+
+	// Cover the HTTP handshake: providers typically wait for the response headers
+	// before StreamResponse returns
+	var firstTimer *time.Timer
+	if t.timeouts.FirstToken > 0 {
+		firstTimer = time.AfterFunc(t.timeouts.FirstToken, cancel)
+	}
+
+	events, err := t.provider.StreamResponse(streamCtx, req)
+	if err != nil {
+		if firstTimer != nil && !firstTimer.Stop() {
+			err = t.timeoutError("no response within %s", t.timeouts.FirstToken)
+		}
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+
+	go func() {
+		defer close(out)
+		defer cancel()
+
+		// The first-token limit keeps counting from the StreamResponse call
+		limit, message := t.timeouts.FirstToken, "no stream event within %s"
+		timer := time.NewTimer(limit - time.Since(started))
+		defer timer.Stop()
+		expired := timer.C
+
+		fail := func() {
+			cancel()
+			go drainStream(events)
+			select {
+			case out <- StreamEvent{Error: t.timeoutError(message, limit)}:
+			case <-ctx.Done():
+			}
+		}
+
+		switch {
+		case firstTimer == nil:
+			timer.Stop()
+			expired = nil
+		case !firstTimer.Stop():
+			fail() // Fired just as StreamResponse returned
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					go drainStream(events)
+					return
+				}
+
+				// Restart the clock once the consumer has the event
+				limit, message = t.timeouts.Idle, "stream idle for %s"
+				timer.Stop()
+				expired = nil
+				if limit > 0 {
+					timer.Reset(limit)
+					expired = timer.C
+				}
+
+			case <-expired:
+				fail()
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Name returns the wrapped provider's name.
+func (t *TimeoutProvider) Name() ProviderID {
+	return t.provider.Name()
+}
+
+// SupportsModel reports whether the wrapped provider supports the model.
+func (t *TimeoutProvider) SupportsModel(model string) bool {
+	return t.provider.SupportsModel(model)
+}
+
+// timeoutError builds the error reported when a limit is exceeded.
+func (t *TimeoutProvider) timeoutError(format string, limit time.Duration) *ProviderError {
+	return &ProviderError{
+		Code:      ErrorCodeTimeout,
+		Provider:  t.provider.Name().String(),
+		Message:   fmt.Sprintf(format, limit),
+		Retryable: true,
+		Err:       ErrTimeout,
+	}
+}
@@ -0,0 +1,119 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stallingStream sends events, then blocks until ctx is cancelled. cancelled is closed
+// when the upstream context is cancelled.
+func stallingStream(cancelled chan struct{}, events ...StreamEvent) func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+	return func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+		out := make(chan StreamEvent)
+		go func() {
+			defer close(out)
+			for _, event := range events {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+				}
+			}
+			<-ctx.Done()
+			close(cancelled)
+		}()
+		return out, nil
+	}
+}
+
+// expectTimeout reads events until the stream closes and checks that it ended with a timeout.
+func expectTimeout(t *testing.T, events <-chan StreamEvent, wantEvents int) {
+	t.Helper()
+	var received []StreamEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	if len(received) != wantEvents+1 {
+		t.Fatalf("expected %d events and a timeout, got %d events", wantEvents, len(received))
+	}
+	err := received[wantEvents].Error
+	var providerErr *ProviderError
+	if !errors.Is(err, ErrTimeout) || !IsRetryable(err) || !errors.As(err, &providerErr) || providerErr.Provider != "openai" {
+		t.Errorf("expected retryable ProviderError wrapping ErrTimeout, got %v", err)
+	}
+}
+
+func TestTimeoutProvider_FirstToken(t *testing.T) {
+	cancelled := make(chan struct{})
+	mock := &mockProvider{name: ProviderOpenAI, stream: stallingStream(cancelled)}
+	provider := NewTimeoutProvider(mock, StreamTimeouts{FirstToken: 20 * time.Millisecond, Idle: time.Hour})
+
+	events, err := provider.StreamResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+	expectTimeout(t, events, 0)
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream request not cancelled")
+	}
+}
+
+func TestTimeoutProvider_Idle(t *testing.T) {
+	cancelled := make(chan struct{})
+	first := textStreamEvents("gpt-4.1", "Hello")[:2]
+	mock := &mockProvider{name: ProviderOpenAI, stream: stallingStream(cancelled, first...)}
+	provider := NewTimeoutProvider(mock, StreamTimeouts{Idle: 20 * time.Millisecond})
+
+	events, err := provider.StreamResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+	expectTimeout(t, events, 2)
+	<-cancelled
+}
+
+func TestTimeoutProvider_StalledHandshake(t *testing.T) {
+	mock := &mockProvider{
+		name: ProviderOpenAI,
+		stream: func(ctx context.Context, req *GenerateRequest) (<-chan StreamEvent, error) {
+			<-ctx.Done() // Like an HTTP request waiting for response headers
+			return nil, ctx.Err()
+		},
+	}
+	provider := Chain(mock,
+		RetryMiddleware(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		TimeoutMiddleware(StreamTimeouts{FirstToken: 10 * time.Millisecond}),
+	)
+
+	_, err := provider.StreamResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if mock.callCount() != 2 {
+		t.Errorf("expected the timeout to be retried, got %d calls", mock.callCount())
+	}
+}
+
+func TestTimeoutProvider_PassThrough(t *testing.T) {
+	mock := &mockProvider{name: ProviderOpenAI}
+	provider := NewTimeoutProvider(mock, StreamTimeouts{FirstToken: time.Second, Idle: time.Second})
+
+	events, err := provider.StreamResponse(context.Background(), &GenerateRequest{Model: "gpt-4.1"})
+	if err != nil {
+		t.Fatalf("StreamResponse() error = %v", err)
+	}
+	var count int
+	for event := range events {
+		if event.Error != nil {
+			t.Errorf("unexpected error: %v", event.Error)
+		}
+		count++
+	}
+	if count != len(textStreamEvents("gpt-4.1", "ok")) {
+		t.Errorf("expected all events, got %d", count)
+	}
+}
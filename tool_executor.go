@@ -0,0 +1,186 @@
+package llmprovider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultToolConcurrency is the number of tool calls run at once when
+// ToolExecutorConfig.Concurrency is zero.
+const DefaultToolConcurrency = 4
+
+// ToolExecutorConfig controls how a ToolExecutor runs tool calls.
+type ToolExecutorConfig struct {
+	Concurrency int                      // Calls run at once (DefaultToolConcurrency if zero, 1 runs them one at a time)
+	Timeout     time.Duration            // Per-call timeout (none if zero)
+	Timeouts    map[string]time.Duration // Per-tool timeouts by tool name, overriding Timeout
+}
+
+// ToolCallResult is the outcome of one tool call.
+type ToolCallResult struct {
+	Call   *Block        // The tool_use block
+	Result *Block        // The tool_result block answering it
+	Err    error         // The handler's error, or a *ToolError (timeouts, unknown tools, invalid input); nil on success
+	Took   time.Duration // How long the call ran
+}
+
+// ToolExecutor runs the tool_use blocks of a turn with Go handlers on a bounded pool of
+// workers, so a model's parallel tool calls run concurrently:
+//
+//	executor := llmprovider.NewToolExecutor(handlers, llmprovider.ToolExecutorConfig{
+//	    Concurrency: 8,
+//	    Timeout:     30 * time.Second,
+//	    Timeouts:    map[string]time.Duration{"run_tests": 5 * time.Minute},
+//	})
+//	results := executor.Execute(ctx, toolUseBlocks)
+//	blocks := llmprovider.ToolResultBlocks(results) // For the next user message
+//
+// Results are in the order of the calls, whatever order they finish in. A call that
+// exceeds its timeout is answered with an is_error tool_result and a retryable
+// *ToolError wrapping ErrTimeout. Its handler's context is cancelled, but the executor
+// doesn't wait for a handler that ignores it, so handlers should honor ctx.
+type ToolExecutor struct {
+	handlers map[string]ToolHandler
+	config   ToolExecutorConfig
+}
+
+// NewToolExecutor creates a ToolExecutor that executes tools with handlers, keyed by tool name.
+func NewToolExecutor(handlers map[string]ToolHandler, config ToolExecutorConfig) *ToolExecutor {
+	copied := make(map[string]ToolHandler, len(handlers))
+	for name, handler := range handlers {
+		copied[name] = handler
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultToolConcurrency
+	}
+	return &ToolExecutor{handlers: copied, config: config}
+}
+
+// Has reports whether the executor has a handler for the tool.
+func (e *ToolExecutor) Has(name string) bool {
+	_, ok := e.handlers[name]
+	return ok
+}
+
+// Execute runs calls and returns their results in the same order. Calls not started
+// before ctx is done are answered with ctx's error.
+func (e *ToolExecutor) Execute(ctx context.Context, calls []*Block) []ToolCallResult {
+	return e.execute(ctx, calls, nil)
+}
+
+// execute runs calls, validating their input against tools (if set) first.
+func (e *ToolExecutor) execute(ctx context.Context, calls []*Block, tools map[string]*Tool) []ToolCallResult {
+	results := make([]ToolCallResult, len(calls))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(e.config.Concurrency, len(calls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = e.call(ctx, calls[i], tools)
+			}
+		}()
+	}
+
+	for i := range calls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := range results {
+		results[i].Result.Sequence = i
+	}
+	return results
+}
+
+// call runs a single tool call.
+func (e *ToolExecutor) call(ctx context.Context, call *Block, tools map[string]*Tool) ToolCallResult {
+	started := time.Now()
+	result := func(text string, err error) ToolCallResult {
+		outcome := ToolCallResult{Call: call, Err: err, Took: time.Since(started)}
+		if err != nil {
+			outcome.Result = NewToolErrorResult(call, err)
+		} else {
+			id, _ := call.GetToolUseID()
+			name, _ := call.GetToolName()
+			outcome.Result = NewToolResultBlock(id, name, text, false)
+		}
+		return outcome
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result("", err)
+	}
+
+	name, _ := call.GetToolName()
+	handler, ok := e.handlers[name]
+	if !ok {
+		return result("", &ToolError{
+			Code:   ErrorCodeUnsupportedTool,
+			Tool:   name,
+			Reason: "no handler is registered for this tool",
+			Err:    ErrUnsupportedTool,
+		})
+	}
+	if tool, ok := tools[name]; ok {
+		if err := ValidateToolInput(tool, call); err != nil {
+			return result("", err)
+		}
+	}
+
+	input, ok := call.GetToolInput()
+	if !ok {
+		input = map[string]interface{}{}
+	}
+
+	timeout := e.config.Timeout
+	if override, ok := e.config.Timeouts[name]; ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return result(handler(ctx, input))
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type handlerResult struct {
+		text string
+		err  error
+	}
+	done := make(chan handlerResult, 1) // Buffered so an abandoned handler can exit
+	go func() {
+		text, err := handler(callCtx, input)
+		done <- handlerResult{text, err}
+	}()
+
+	select {
+	case out := <-done:
+		return result(out.text, out.err)
+	case <-callCtx.Done():
+		if ctx.Err() != nil {
+			return result("", ctx.Err())
+		}
+		return result("", &ToolError{
+			Code:      ErrorCodeTimeout,
+			Tool:      name,
+			Reason:    fmt.Sprintf("timed out after %s", timeout),
+			Err:       ErrTimeout,
+			Retryable: true,
+		})
+	}
+}
+
+// ToolResultBlocks returns the tool_result blocks of results, in order.
+func ToolResultBlocks(results []ToolCallResult) []*Block {
+	blocks := make([]*Block, len(results))
+	for i, result := range results {
+		blocks[i] = result.Result
+	}
+	return blocks
+}
@@ -0,0 +1,107 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestToolExecutor_OrderAndConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	handlers := map[string]ToolHandler{
+		"sleep": func(ctx context.Context, input map[string]interface{}) (string, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := peak.Load()
+				if n <= current || peak.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			// Later calls finish first
+			time.Sleep(time.Duration(50-10*int(input["n"].(float64))) * time.Millisecond)
+			return fmt.Sprint(input["n"]), nil
+		},
+	}
+
+	var calls []*Block
+	for i := 0; i < 5; i++ {
+		calls = append(calls, toolUseBlock(fmt.Sprintf("toolu_%d", i), "sleep", map[string]interface{}{"n": float64(i)}, ExecutionSideServer))
+	}
+
+	results := NewToolExecutor(handlers, ToolExecutorConfig{Concurrency: 2}).Execute(context.Background(), calls)
+	for i, result := range results {
+		text, isError := ToolResultText(result.Result)
+		id, _ := result.Result.GetToolUseID()
+		if result.Call != calls[i] || id != fmt.Sprintf("toolu_%d", i) || text != fmt.Sprint(i) || isError || result.Result.Sequence != i {
+			t.Errorf("result %d = %q (%s), out of order", i, text, id)
+		}
+	}
+	if peak.Load() != 2 {
+		t.Errorf("expected 2 calls at once, got %d", peak.Load())
+	}
+}
+
+func TestToolExecutor_Timeouts(t *testing.T) {
+	block := func(ctx context.Context, input map[string]interface{}) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	stuck := func(ctx context.Context, input map[string]interface{}) (string, error) {
+		time.Sleep(time.Second) // Ignores ctx
+		return "late", nil
+	}
+	handlers := map[string]ToolHandler{"slow": block, "slower": block, "stuck": stuck}
+	executor := NewToolExecutor(handlers, ToolExecutorConfig{
+		Timeout:  10 * time.Millisecond,
+		Timeouts: map[string]time.Duration{"slower": 30 * time.Millisecond},
+	})
+
+	started := time.Now()
+	results := executor.Execute(context.Background(), []*Block{
+		toolUseBlock("toolu_1", "slow", nil, ExecutionSideServer),
+		toolUseBlock("toolu_2", "slower", nil, ExecutionSideServer),
+		toolUseBlock("toolu_3", "stuck", nil, ExecutionSideServer),
+		toolUseBlock("toolu_4", "missing", nil, ExecutionSideServer),
+	})
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("executor waited for a handler ignoring its timeout (%s)", elapsed)
+	}
+
+	for i, name := range []string{"slow", "slower", "stuck"} {
+		var toolErr *ToolError
+		err := results[i].Err
+		if !errors.As(err, &toolErr) || !errors.Is(err, ErrTimeout) || !IsRetryable(err) || toolErr.Tool != name {
+			t.Errorf("%s: expected a retryable timeout ToolError, got %v", name, err)
+		}
+		if _, isError := ToolResultText(results[i].Result); !isError {
+			t.Errorf("%s: expected an is_error tool_result", name)
+		}
+	}
+	if results[1].Took < 30*time.Millisecond {
+		t.Errorf("per-tool timeout not applied: took %s", results[1].Took)
+	}
+	if text, _ := ToolResultText(results[0].Result); text != "tool 'slow' error: timed out after 10ms" {
+		t.Errorf("unexpected timeout text %q", text)
+	}
+	if !errors.Is(results[3].Err, ErrUnsupportedTool) || IsRetryable(results[3].Err) {
+		t.Errorf("expected an unsupported tool error, got %v", results[3].Err)
+	}
+}
+
+func TestToolExecutor_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	executor := NewToolExecutor(weatherHandlers, ToolExecutorConfig{Timeout: time.Second})
+
+	results := executor.Execute(ctx, []*Block{toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris"}, ExecutionSideServer)})
+	if !errors.Is(results[0].Err, context.Canceled) || IsRetryable(results[0].Err) {
+		t.Errorf("expected context.Canceled, got %v", results[0].Err)
+	}
+	if blocks := ToolResultBlocks(results); len(blocks) != 1 || blocks[0] != results[0].Result {
+		t.Errorf("unexpected blocks: %v", blocks)
+	}
+}
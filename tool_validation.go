@@ -0,0 +1,299 @@
+package llmprovider
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaViolation is one way a value fails a JSON Schema.
+type SchemaViolation struct {
+	Path   string // Location of the value, e.g. "$.items[2].name" ("$" is the whole input)
+	Reason string // What is wrong, e.g. "missing required property"
+}
+
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Reason
+}
+
+// ValidateToolInput checks a tool_use block's input against tool's parameters schema.
+// It returns nil if the input is valid, or a *ToolError wrapping ErrInvalidToolInput
+// whose Violations list every problem found.
+//
+// The schema keywords checked are type, properties, required, enum, additionalProperties
+// and items; others are ignored. Turn the error into feedback for the model with
+// NewToolErrorResult, so it can correct the call.
+func ValidateToolInput(tool *Tool, block *Block) error {
+	name, _ := block.GetToolName()
+	if name != tool.Function.Name {
+		return &ToolError{
+			Code:   ErrorCodeInvalidToolInput,
+			Tool:   name,
+			Reason: fmt.Sprintf("tool_use block is for tool %q, not %q", name, tool.Function.Name),
+			Err:    ErrInvalidToolInput,
+		}
+	}
+
+	var input interface{} = map[string]interface{}{}
+	if raw, ok := block.Content["input"]; ok && raw != nil {
+		input = raw
+	}
+
+	violations := ValidateSchema(tool.Function.Parameters, input)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, len(violations))
+	for i, violation := range violations {
+		reasons[i] = violation.String()
+	}
+	provider := ""
+	if block.Provider != nil {
+		provider = *block.Provider
+	}
+	return &ToolError{
+		Code:       ErrorCodeInvalidToolInput,
+		Tool:       name,
+		Provider:   provider,
+		Reason:     "input does not match schema: " + strings.Join(reasons, "; "),
+		Err:        ErrInvalidToolInput,
+		Violations: violations,
+	}
+}
+
+// NewToolErrorResult returns the is_error tool_result answering block with err's
+// message. For invalid input the text lists the violations and asks the model to call
+// the tool again with corrected input.
+func NewToolErrorResult(block *Block, err error) *Block {
+	id, _ := block.GetToolUseID()
+	name, _ := block.GetToolName()
+	return NewToolResultBlock(id, name, toolErrorText(name, err), true)
+}
+
+// toolErrorText returns the message sent to the model for a failed tool call.
+func toolErrorText(name string, err error) string {
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || len(toolErr.Violations) == 0 {
+		return err.Error()
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Invalid input for tool %s:\n", name)
+	for _, violation := range toolErr.Violations {
+		fmt.Fprintf(&text, "- %s\n", violation)
+	}
+	text.WriteString("Call the tool again with input that matches its parameters schema.")
+	return text.String()
+}
+
+// ValidateSchema checks value, as decoded by encoding/json, against a JSON Schema. See
+// ValidateToolInput for the keywords checked. Object properties are checked in name
+// order, so the violations are in a stable order.
+func ValidateSchema(schema map[string]interface{}, value interface{}) []SchemaViolation {
+	var violations []SchemaViolation
+	validateValue(schema, value, "$", &violations)
+	return violations
+}
+
+// validateValue appends the violations of value at path to violations.
+func validateValue(schema map[string]interface{}, value interface{}, path string, violations *[]SchemaViolation) {
+	add := func(path, format string, args ...interface{}) {
+		*violations = append(*violations, SchemaViolation{Path: path, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !matchesAnyType(value, types) {
+		add(path, "expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+		return
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		values := interfaceSlice(enum)
+		if !containsJSONValue(values, value) {
+			add(path, "must be one of %s", formatEnum(values))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		for _, name := range stringSlice(schema["required"]) {
+			if _, ok := value[name]; !ok {
+				add(propertyPath(path, name), "missing required property")
+			}
+		}
+
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				validateValue(property, value[name], propertyPath(path, name), violations)
+				continue
+			}
+			if _, ok := properties[name]; ok {
+				continue // Boolean or malformed property schema: accept
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					add(propertyPath(path, name), "unknown property (allowed: %s)", strings.Join(sortedKeys(properties), ", "))
+				}
+			case map[string]interface{}:
+				validateValue(additional, value[name], propertyPath(path, name), violations)
+			}
+		}
+
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+}
+
+// schemaTypes returns the type keyword as a list ("string" or ["string", "null"]).
+func schemaTypes(keyword interface{}) []string {
+	if name, ok := keyword.(string); ok {
+		return []string{name}
+	}
+	return stringSlice(keyword)
+}
+
+// matchesAnyType reports whether value is an instance of one of the JSON Schema types.
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, name := range types {
+		switch name {
+		case "integer":
+			if number, ok := jsonNumber(value); ok && number == float64(int64(number)) {
+				return true
+			}
+		case "number":
+			if _, ok := jsonNumber(value); ok {
+				return true
+			}
+		default:
+			if jsonTypeName(value) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type of a decoded value.
+func jsonTypeName(value interface{}) string {
+	if _, ok := jsonNumber(value); ok {
+		return "number"
+	}
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// jsonNumber returns value as a float64 if it is numeric. Input usually holds float64,
+// but blocks built in Go may hold other numeric types.
+func jsonNumber(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+// containsJSONValue reports whether values contains value, comparing numbers by value.
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	number, isNumber := jsonNumber(value)
+	for _, candidate := range values {
+		if other, ok := jsonNumber(candidate); ok && isNumber {
+			if other == number {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum formats enum values for a violation message.
+func formatEnum(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		if text, ok := value.(string); ok {
+			formatted[i] = fmt.Sprintf("%q", text)
+		} else {
+			formatted[i] = fmt.Sprint(value)
+		}
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+// interfaceSlice converts any slice (e.g. []string or []interface{}) to []interface{}.
+func interfaceSlice(value interface{}) []interface{} {
+	if values, ok := value.([]interface{}); ok {
+		return values
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
+
+// stringSlice converts []string or []interface{} of strings to []string.
+func stringSlice(value interface{}) []string {
+	if values, ok := value.([]string); ok {
+		return values
+	}
+	var values []string
+	for _, item := range interfaceSlice(value) {
+		if text, ok := item.(string); ok {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// propertyPath returns the path of property name within the object at path.
+func propertyPath(path, name string) string {
+	return path + "." + name
+}
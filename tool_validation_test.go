@@ -0,0 +1,133 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func bookingTool(t *testing.T) *Tool {
+	t.Helper()
+	tool, err := NewCustomTool("book_trip", "Book a trip", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"city":   map[string]interface{}{"type": "string"},
+			"nights": map[string]interface{}{"type": "integer"},
+			"class":  map[string]interface{}{"type": "string", "enum": []interface{}{"economy", "business"}},
+			"travelers": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"properties":           map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "age": map[string]interface{}{"type": "number"}},
+					"required":             []interface{}{"name"},
+					"additionalProperties": false,
+				},
+			},
+			"notes": map[string]interface{}{"type": []interface{}{"string", "null"}},
+		},
+		"required":             []string{"city", "nights"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		t.Fatalf("NewCustomTool() error = %v", err)
+	}
+	return tool
+}
+
+func TestValidateToolInput(t *testing.T) {
+	tool := bookingTool(t)
+
+	valid := toolUseBlock("toolu_1", "book_trip", map[string]interface{}{
+		"city": "Paris", "nights": float64(3), "class": "economy", "notes": nil,
+		"travelers": []interface{}{map[string]interface{}{"name": "Ada", "age": 36.5}},
+	}, ExecutionSideServer)
+	if err := ValidateToolInput(tool, valid); err != nil {
+		t.Errorf("expected valid input, got %v", err)
+	}
+
+	invalid := toolUseBlock("toolu_2", "book_trip", map[string]interface{}{
+		"nights": 2.5,
+		"class":  "first",
+		"travelers": []interface{}{
+			map[string]interface{}{"name": "Ada"},
+			map[string]interface{}{"age": "old", "email": "x"},
+		},
+		"pets": true,
+	}, ExecutionSideServer)
+	err := ValidateToolInput(tool, invalid)
+
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || !errors.Is(err, ErrInvalidToolInput) || toolErr.Code != ErrorCodeInvalidToolInput {
+		t.Fatalf("expected a ToolError wrapping ErrInvalidToolInput, got %v", err)
+	}
+	want := []SchemaViolation{
+		{Path: "$.city", Reason: "missing required property"},
+		{Path: "$.class", Reason: `must be one of ["economy", "business"]`},
+		{Path: "$.nights", Reason: "expected integer, got number"},
+		{Path: "$.pets", Reason: "unknown property (allowed: city, class, nights, notes, travelers)"},
+		{Path: "$.travelers[1].name", Reason: "missing required property"},
+		{Path: "$.travelers[1].age", Reason: "expected number, got string"},
+		{Path: "$.travelers[1].email", Reason: "unknown property (allowed: age, name)"},
+	}
+	if !reflect.DeepEqual(toolErr.Violations, want) {
+		t.Errorf("violations = %v\nwant %v", toolErr.Violations, want)
+	}
+	if IsRetryable(err) {
+		t.Error("invalid input should not be retryable")
+	}
+
+	result := NewToolErrorResult(invalid, err)
+	text, isError := ToolResultText(result)
+	if id, _ := result.GetToolUseID(); id != "toolu_2" || !isError {
+		t.Errorf("unexpected tool_result: %+v", result)
+	}
+	if !strings.HasPrefix(text, "Invalid input for tool book_trip:\n- $.city: missing required property\n") ||
+		!strings.HasSuffix(text, "matches its parameters schema.") {
+		t.Errorf("unexpected tool_result text:\n%s", text)
+	}
+}
+
+func TestValidateToolInput_Typed(t *testing.T) {
+	weather := newWeatherTool(t)
+	block := toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"days": float64(2)}, ExecutionSideServer)
+
+	err := ValidateToolInput(weather.Tool(), block)
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || len(toolErr.Violations) != 1 || toolErr.Violations[0].Path != "$.city" {
+		t.Errorf("expected a missing city, got %v", err)
+	}
+
+	other := toolUseBlock("toolu_2", "get_time", nil, ExecutionSideServer)
+	err = ValidateToolInput(weather.Tool(), other)
+	if !errors.Is(err, ErrInvalidToolInput) {
+		t.Errorf("expected a tool mismatch error, got %v", err)
+	}
+	if want := `tool 'get_time' error: tool_use block is for tool "get_time", not "get_weather"`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRunner_ValidateInput(t *testing.T) {
+	tool := bookingTool(t)
+	calls := 0
+	handlers := map[string]ToolHandler{
+		"book_trip": func(ctx context.Context, input map[string]interface{}) (string, error) {
+			calls++
+			return "booked", nil
+		},
+	}
+	mock := agentProvider(toolUseBlock("toolu_1", "book_trip", map[string]interface{}{"city": "Paris"}, ExecutionSideServer))
+	req := userRequest("Book Paris")
+	req.Params = &RequestParams{Tools: []Tool{*tool}}
+
+	result, err := NewRunner(mock, handlers, RunnerConfig{ValidateInput: true}).Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	text, isError := ToolResultText(result.Messages[2].Blocks[0])
+	if calls != 0 || !isError || !strings.Contains(text, "$.nights: missing required property") {
+		t.Errorf("expected a validation error result without calling the handler, got %q (%d calls)", text, calls)
+	}
+}
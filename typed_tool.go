@@ -0,0 +1,137 @@
+package llmprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TypedTool is a server-side tool defined by a Go function: its schema is derived from
+// the input struct In (see SchemaFor), tool_use input is decoded into In, and the
+// result Out is sent back to the model (strings as is, other values as JSON).
+//
+//	type WeatherInput struct {
+//	    City string `json:"city" jsonschema:"description=City name"`
+//	    Unit string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+//	}
+//
+//	weather, err := llmprovider.NewTypedTool("get_weather", "Get current weather for a city",
+//	    func(ctx context.Context, input WeatherInput) (string, error) {
+//	        return lookup(ctx, input.City, input.Unit)
+//	    })
+//
+//	params := &llmprovider.RequestParams{Tools: []llmprovider.Tool{*weather.Tool()}}
+//	runner := llmprovider.NewRunner(provider, map[string]llmprovider.ToolHandler{
+//	    weather.Name(): weather.Handler(),
+//	}, llmprovider.RunnerConfig{})
+type TypedTool[In, Out any] struct {
+	tool    *Tool
+	handler func(ctx context.Context, input In) (Out, error)
+}
+
+// NewTypedTool creates a TypedTool from handler, deriving the parameters schema from In.
+func NewTypedTool[In, Out any](name, description string, handler func(ctx context.Context, input In) (Out, error)) (*TypedTool[In, Out], error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler is required for tool %s", name)
+	}
+
+	parameters, err := SchemaFor[In]()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create typed tool %s: %w", name, err)
+	}
+
+	tool, err := NewCustomTool(name, description, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedTool[In, Out]{tool: tool, handler: handler}, nil
+}
+
+// Name returns the tool's name.
+func (t *TypedTool[In, Out]) Name() string {
+	return t.tool.Function.Name
+}
+
+// Tool returns a copy of the tool definition, for RequestParams.Tools.
+func (t *TypedTool[In, Out]) Tool() *Tool {
+	tool := *t.tool
+	return &tool
+}
+
+// Definition returns a ToolDefinition for registering the tool with a ToolRegistry:
+//
+//	err := llmprovider.RegisterTool(weather.Definition())
+func (t *TypedTool[In, Out]) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name:        t.tool.Function.Name,
+		Description: t.tool.Function.Description,
+		Factory: func() (*Tool, error) {
+			return t.Tool(), nil
+		},
+	}
+}
+
+// Decode decodes a tool_use block's input into In.
+func (t *TypedTool[In, Out]) Decode(block *Block) (In, error) {
+	var input In
+	if name, _ := block.GetToolName(); name != t.Name() {
+		return input, fmt.Errorf("tool_use block is for tool %q, not %q", name, t.Name())
+	}
+	raw, _ := block.GetToolInput()
+	if err := decodeToolInput(raw, &input); err != nil {
+		return input, fmt.Errorf("invalid input for tool %s: %w", t.Name(), err)
+	}
+	return input, nil
+}
+
+// Call decodes a tool_use block's input and runs the handler with it.
+func (t *TypedTool[In, Out]) Call(ctx context.Context, block *Block) (Out, error) {
+	input, err := t.Decode(block)
+	if err != nil {
+		var zero Out
+		return zero, err
+	}
+	return t.handler(ctx, input)
+}
+
+// Handler returns a ToolHandler for Runner. Input that doesn't decode into In is
+// reported to the model as an error, like a handler error.
+func (t *TypedTool[In, Out]) Handler() ToolHandler {
+	return func(ctx context.Context, raw map[string]interface{}) (string, error) {
+		var input In
+		if err := decodeToolInput(raw, &input); err != nil {
+			return "", fmt.Errorf("invalid input for tool %s: %w", t.Name(), err)
+		}
+
+		output, err := t.handler(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		return encodeToolOutput(output)
+	}
+}
+
+// decodeToolInput decodes tool_use input into target through JSON, so json tags apply.
+func decodeToolInput(raw map[string]interface{}, target interface{}) error {
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// encodeToolOutput returns the tool_result text for a handler's output.
+func encodeToolOutput(output interface{}) (string, error) {
+	if text, ok := output.(string); ok {
+		return text, nil
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tool output: %w", err)
+	}
+	return string(data), nil
+}
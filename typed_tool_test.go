@@ -0,0 +1,87 @@
+package llmprovider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type weatherInput struct {
+	City string `json:"city" jsonschema:"description=City name"`
+	Days int    `json:"days,omitempty"`
+}
+
+type weatherOutput struct {
+	City     string   `json:"city"`
+	Forecast []string `json:"forecast"`
+}
+
+func newWeatherTool(t *testing.T) *TypedTool[weatherInput, weatherOutput] {
+	t.Helper()
+	tool, err := NewTypedTool("get_weather", "Get the weather forecast for a city",
+		func(ctx context.Context, input weatherInput) (weatherOutput, error) {
+			if input.City == "" {
+				return weatherOutput{}, errors.New("city is required")
+			}
+			return weatherOutput{City: input.City, Forecast: make([]string, input.Days)}, nil
+		})
+	if err != nil {
+		t.Fatalf("NewTypedTool() error = %v", err)
+	}
+	return tool
+}
+
+func TestTypedTool(t *testing.T) {
+	weather := newWeatherTool(t)
+
+	tool := weather.Tool()
+	if tool.Function.Name != "get_weather" || tool.ExecutionSide != ExecutionSideServer {
+		t.Errorf("unexpected tool: %+v", tool)
+	}
+	if required := tool.Function.Parameters["required"]; len(required.([]string)) != 1 {
+		t.Errorf("required = %v", required)
+	}
+
+	block := toolUseBlock("toolu_1", "get_weather", map[string]interface{}{"city": "Paris", "days": float64(2)}, ExecutionSideServer)
+	input, err := weather.Decode(block)
+	if err != nil || input != (weatherInput{City: "Paris", Days: 2}) {
+		t.Errorf("Decode() = %+v, %v", input, err)
+	}
+	if output, err := weather.Call(context.Background(), block); err != nil || len(output.Forecast) != 2 {
+		t.Errorf("Call() = %+v, %v", output, err)
+	}
+
+	other := toolUseBlock("toolu_2", "get_time", nil, ExecutionSideServer)
+	if _, err := weather.Decode(other); err == nil {
+		t.Error("expected an error decoding another tool's block")
+	}
+}
+
+func TestTypedTool_Handler(t *testing.T) {
+	handler := newWeatherTool(t).Handler()
+
+	text, err := handler(context.Background(), map[string]interface{}{"city": "Paris", "days": float64(1)})
+	if err != nil || text != `{"city":"Paris","forecast":[""]}` {
+		t.Errorf("handler() = %q, %v", text, err)
+	}
+	if _, err := handler(context.Background(), map[string]interface{}{"days": "two"}); err == nil || !strings.Contains(err.Error(), "invalid input for tool get_weather") {
+		t.Errorf("expected a decode error, got %v", err)
+	}
+	if _, err := handler(context.Background(), map[string]interface{}{}); err == nil || err.Error() != "city is required" {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestTypedTool_Registry(t *testing.T) {
+	weather := newWeatherTool(t)
+	if err := RegisterTool(weather.Definition()); err != nil {
+		t.Fatalf("RegisterTool() error = %v", err)
+	}
+	defer func() { _ = GetToolRegistry().Unregister(weather.Name()) }()
+
+	tool, err := CreateTool("get_weather")
+	if err != nil || tool.Function.Description != "Get the weather forecast for a city" {
+		t.Errorf("CreateTool() = %+v, %v", tool, err)
+	}
+}
@@ -61,7 +61,7 @@ type Citation struct {
 //
 // The Content field stores block-type-specific structured data as a map:
 // - text: empty (text in TextContent field)
-// - thinking: {"signature": "4k_a"} (optional, text in TextContent)
+// - thinking: empty (text in TextContent, signature in ProviderData)
 // - tool_use: {"tool_use_id": "toolu_...", "tool_name": "...", "input": {...}}
 // - tool_result: {"tool_use_id": "toolu_...", "is_error": false}
 // - web_search: {"tool_use_id": "toolu_...", "tool_name": "web_search", "input": {...}}
@@ -210,6 +210,23 @@ func (b *Block) GetToolInput() (map[string]interface{}, bool) {
 	return input, ok
 }
 
+// NewToolResultBlock creates the tool_result block answering a tool_use block.
+// toolName is optional but needed by providers that match results by name (Gemini).
+func NewToolResultBlock(toolUseID, toolName, result string, isError bool) *Block {
+	content := map[string]interface{}{
+		"tool_use_id": toolUseID,
+		"is_error":    isError,
+	}
+	if toolName != "" {
+		content["tool_name"] = toolName
+	}
+	return &Block{
+		BlockType:   BlockTypeToolResult,
+		Content:     content,
+		TextContent: &result,
+	}
+}
+
 // IsFromDifferentProvider returns true if this block was created by a different provider
 func (b *Block) IsFromDifferentProvider(currentProvider ProviderID) bool {
 	return b.Provider != nil && *b.Provider != "" && *b.Provider != currentProvider.String()
@@ -295,7 +312,9 @@ type BlockDelta struct {
 
 	// SignatureDelta contains incremental cryptographic signature
 	// (Anthropic/Gemini Extended Thinking only)
-	// Accumulated into Block.Content["signature"]
+	// Providers store it in Block.ProviderData: {"signature": ...} for Anthropic,
+	// {"thought_signature": ...} for Gemini. Accumulator uses {"signature": ...}
+	// for blocks built only from deltas.
 	SignatureDelta *string `json:"signature_delta,omitempty"`
 
 	// JSONDelta contains incremental JSON content
@@ -325,7 +344,7 @@ type BlockDelta struct {
 	ToolName *string `json:"tool_name,omitempty"`
 
 	// ThinkingSignature is DEPRECATED, use SignatureDelta with DeltaTypeSignature
-	// Stored in Block.Content["signature"]
+	// Stored like SignatureDelta
 	ThinkingSignature *string `json:"thinking_signature,omitempty"`
 
 	// === Usage Metadata ===